@@ -20,10 +20,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AccountService_CreateAccount_FullMethodName = "/proto.AccountService/CreateAccount"
-	AccountService_GetAccount_FullMethodName    = "/proto.AccountService/GetAccount"
-	AccountService_UpdateAccount_FullMethodName = "/proto.AccountService/UpdateAccount"
-	AccountService_DeleteAccount_FullMethodName = "/proto.AccountService/DeleteAccount"
+	AccountService_CreateAccount_FullMethodName         = "/proto.AccountService/CreateAccount"
+	AccountService_GetAccount_FullMethodName            = "/proto.AccountService/GetAccount"
+	AccountService_UpdateAccount_FullMethodName         = "/proto.AccountService/UpdateAccount"
+	AccountService_DeleteAccount_FullMethodName         = "/proto.AccountService/DeleteAccount"
+	AccountService_ChangeAccountStatus_FullMethodName   = "/proto.AccountService/ChangeAccountStatus"
+	AccountService_ListMyAccounts_FullMethodName        = "/proto.AccountService/ListMyAccounts"
+	AccountService_ListAccounts_FullMethodName          = "/proto.AccountService/ListAccounts"
+	AccountService_ValidateAccountNumber_FullMethodName = "/proto.AccountService/ValidateAccountNumber"
+	AccountService_ListProducts_FullMethodName          = "/proto.AccountService/ListProducts"
+	AccountService_WatchAccount_FullMethodName          = "/proto.AccountService/WatchAccount"
 )
 
 // AccountServiceClient is the client API for AccountService service.
@@ -33,8 +39,22 @@ type AccountServiceClient interface {
 	// Account CRUD
 	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*AccountResponse, error)
 	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	// Deprecated: Do not use.
+	// 더 이상 사용하지 않습니다. 바꿀 수 있는 필드가 없어 계좌를 확인만 하고 그대로 돌려줍니다.
 	UpdateAccount(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	// 잔액이 0인 계좌를 해지합니다. 기록은 지우지 않으며, 잔액이 남았으면 TransactionService.CloseAccount를 사용합니다.
 	DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// 계좌 동결/휴면 처리와 해제
+	ChangeAccountStatus(ctx context.Context, in *ChangeAccountStatusRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	// 계좌 목록. ListMyAccounts는 요청한 사용자의 계좌를, ListAccounts는 조건에 맞는 전체 계좌를 반환합니다.
+	ListMyAccounts(ctx context.Context, in *ListMyAccountsRequest, opts ...grpc.CallOption) (*AccountListResponse, error)
+	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*AccountListResponse, error)
+	// 계좌번호의 형식과 검증 숫자를 확인합니다. 계좌가 있는지는 알려주지 않습니다.
+	ValidateAccountNumber(ctx context.Context, in *ValidateAccountNumberRequest, opts ...grpc.CallOption) (*ValidateAccountNumberResponse, error)
+	// 개설할 수 있는 상품 목록입니다. 로그인하지 않아도 볼 수 있습니다.
+	ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error)
+	// 잔액 등 계좌 변경을 실시간으로 받습니다.
+	WatchAccount(ctx context.Context, in *WatchAccountRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AccountResponse], error)
 }
 
 type accountServiceClient struct {
@@ -65,6 +85,7 @@ func (c *accountServiceClient) GetAccount(ctx context.Context, in *GetAccountReq
 	return out, nil
 }
 
+// Deprecated: Do not use.
 func (c *accountServiceClient) UpdateAccount(ctx context.Context, in *UpdateAccountRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(AccountResponse)
@@ -85,6 +106,75 @@ func (c *accountServiceClient) DeleteAccount(ctx context.Context, in *DeleteAcco
 	return out, nil
 }
 
+func (c *accountServiceClient) ChangeAccountStatus(ctx context.Context, in *ChangeAccountStatusRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_ChangeAccountStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ListMyAccounts(ctx context.Context, in *ListMyAccountsRequest, opts ...grpc.CallOption) (*AccountListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountListResponse)
+	err := c.cc.Invoke(ctx, AccountService_ListMyAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*AccountListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountListResponse)
+	err := c.cc.Invoke(ctx, AccountService_ListAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ValidateAccountNumber(ctx context.Context, in *ValidateAccountNumberRequest, opts ...grpc.CallOption) (*ValidateAccountNumberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateAccountNumberResponse)
+	err := c.cc.Invoke(ctx, AccountService_ValidateAccountNumber_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) ListProducts(ctx context.Context, in *ListProductsRequest, opts ...grpc.CallOption) (*ListProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProductsResponse)
+	err := c.cc.Invoke(ctx, AccountService_ListProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) WatchAccount(ctx context.Context, in *WatchAccountRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AccountResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AccountService_ServiceDesc.Streams[0], AccountService_WatchAccount_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchAccountRequest, AccountResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AccountService_WatchAccountClient = grpc.ServerStreamingClient[AccountResponse]
+
 // AccountServiceServer is the server API for AccountService service.
 // All implementations must embed UnimplementedAccountServiceServer
 // for forward compatibility.
@@ -92,8 +182,22 @@ type AccountServiceServer interface {
 	// Account CRUD
 	CreateAccount(context.Context, *CreateAccountRequest) (*AccountResponse, error)
 	GetAccount(context.Context, *GetAccountRequest) (*AccountResponse, error)
+	// Deprecated: Do not use.
+	// 더 이상 사용하지 않습니다. 바꿀 수 있는 필드가 없어 계좌를 확인만 하고 그대로 돌려줍니다.
 	UpdateAccount(context.Context, *UpdateAccountRequest) (*AccountResponse, error)
+	// 잔액이 0인 계좌를 해지합니다. 기록은 지우지 않으며, 잔액이 남았으면 TransactionService.CloseAccount를 사용합니다.
 	DeleteAccount(context.Context, *DeleteAccountRequest) (*emptypb.Empty, error)
+	// 계좌 동결/휴면 처리와 해제
+	ChangeAccountStatus(context.Context, *ChangeAccountStatusRequest) (*AccountResponse, error)
+	// 계좌 목록. ListMyAccounts는 요청한 사용자의 계좌를, ListAccounts는 조건에 맞는 전체 계좌를 반환합니다.
+	ListMyAccounts(context.Context, *ListMyAccountsRequest) (*AccountListResponse, error)
+	ListAccounts(context.Context, *ListAccountsRequest) (*AccountListResponse, error)
+	// 계좌번호의 형식과 검증 숫자를 확인합니다. 계좌가 있는지는 알려주지 않습니다.
+	ValidateAccountNumber(context.Context, *ValidateAccountNumberRequest) (*ValidateAccountNumberResponse, error)
+	// 개설할 수 있는 상품 목록입니다. 로그인하지 않아도 볼 수 있습니다.
+	ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error)
+	// 잔액 등 계좌 변경을 실시간으로 받습니다.
+	WatchAccount(*WatchAccountRequest, grpc.ServerStreamingServer[AccountResponse]) error
 	mustEmbedUnimplementedAccountServiceServer()
 }
 
@@ -116,6 +220,24 @@ func (UnimplementedAccountServiceServer) UpdateAccount(context.Context, *UpdateA
 func (UnimplementedAccountServiceServer) DeleteAccount(context.Context, *DeleteAccountRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccount not implemented")
 }
+func (UnimplementedAccountServiceServer) ChangeAccountStatus(context.Context, *ChangeAccountStatusRequest) (*AccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeAccountStatus not implemented")
+}
+func (UnimplementedAccountServiceServer) ListMyAccounts(context.Context, *ListMyAccountsRequest) (*AccountListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMyAccounts not implemented")
+}
+func (UnimplementedAccountServiceServer) ListAccounts(context.Context, *ListAccountsRequest) (*AccountListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccounts not implemented")
+}
+func (UnimplementedAccountServiceServer) ValidateAccountNumber(context.Context, *ValidateAccountNumberRequest) (*ValidateAccountNumberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateAccountNumber not implemented")
+}
+func (UnimplementedAccountServiceServer) ListProducts(context.Context, *ListProductsRequest) (*ListProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProducts not implemented")
+}
+func (UnimplementedAccountServiceServer) WatchAccount(*WatchAccountRequest, grpc.ServerStreamingServer[AccountResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchAccount not implemented")
+}
 func (UnimplementedAccountServiceServer) mustEmbedUnimplementedAccountServiceServer() {}
 func (UnimplementedAccountServiceServer) testEmbeddedByValue()                        {}
 
@@ -209,6 +331,107 @@ func _AccountService_DeleteAccount_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccountService_ChangeAccountStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeAccountStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ChangeAccountStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ChangeAccountStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ChangeAccountStatus(ctx, req.(*ChangeAccountStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ListMyAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMyAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ListMyAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ListMyAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ListMyAccounts(ctx, req.(*ListMyAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ListAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ListAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ListAccounts(ctx, req.(*ListAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ValidateAccountNumber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateAccountNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ValidateAccountNumber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ValidateAccountNumber_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ValidateAccountNumber(ctx, req.(*ValidateAccountNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).ListProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_ListProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).ListProducts(ctx, req.(*ListProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_WatchAccount_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchAccountRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AccountServiceServer).WatchAccount(m, &grpc.GenericServerStream[WatchAccountRequest, AccountResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AccountService_WatchAccountServer = grpc.ServerStreamingServer[AccountResponse]
+
 // AccountService_ServiceDesc is the grpc.ServiceDesc for AccountService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -232,7 +455,33 @@ var AccountService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteAccount",
 			Handler:    _AccountService_DeleteAccount_Handler,
 		},
+		{
+			MethodName: "ChangeAccountStatus",
+			Handler:    _AccountService_ChangeAccountStatus_Handler,
+		},
+		{
+			MethodName: "ListMyAccounts",
+			Handler:    _AccountService_ListMyAccounts_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _AccountService_ListAccounts_Handler,
+		},
+		{
+			MethodName: "ValidateAccountNumber",
+			Handler:    _AccountService_ValidateAccountNumber_Handler,
+		},
+		{
+			MethodName: "ListProducts",
+			Handler:    _AccountService_ListProducts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchAccount",
+			Handler:       _AccountService_WatchAccount_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/v1/account.proto",
 }
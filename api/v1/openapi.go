@@ -0,0 +1,8 @@
+package ebank
+
+import "embed"
+
+// OpenAPI `make proto`로 생성한 *.swagger.json 문서입니다. pkg/gateway가 합쳐서 /openapi.json으로 제공합니다.
+//
+//go:embed *.swagger.json
+var OpenAPI embed.FS
@@ -21,7 +21,11 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	TransactionService_Deposit_FullMethodName               = "/proto.TransactionService/Deposit"
 	TransactionService_Withdraw_FullMethodName              = "/proto.TransactionService/Withdraw"
+	TransactionService_Transfer_FullMethodName              = "/proto.TransactionService/Transfer"
+	TransactionService_CloseAccount_FullMethodName          = "/proto.TransactionService/CloseAccount"
+	TransactionService_MatureAccounts_FullMethodName        = "/proto.TransactionService/MatureAccounts"
 	TransactionService_GetTransactionHistory_FullMethodName = "/proto.TransactionService/GetTransactionHistory"
+	TransactionService_WatchTransactions_FullMethodName     = "/proto.TransactionService/WatchTransactions"
 )
 
 // TransactionServiceClient is the client API for TransactionService service.
@@ -30,9 +34,20 @@ const (
 type TransactionServiceClient interface {
 	// 입금/출금
 	Deposit(ctx context.Context, in *DepositRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	// 출금, 이체, 해지 지급 이체는 -step_up_threshold를 넘으면 gRPC 메타데이터 "otp-code"에 TOTP 코드나 복구 코드가 필요합니다.
+	// 2단계 인증을 등록하지 않은 사용자는 UserService.EnrollTOTP와 ConfirmTOTP로 등록하기 전까지 FAILED_PRECONDITION을 받습니다.
+	// 틀린 코드는 로그인 실패와 같은 기준으로 사용자별로 세어, 너무 많이 틀리면 잠금 시간 동안 RESOURCE_EXHAUSTED를 받습니다.
 	Withdraw(ctx context.Context, in *WithdrawRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
-	// 거래 내역 조회
+	// 계좌 이체
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
+	// 계좌 해지. 남은 잔액을 옮기는 이체와 함께 처리합니다.
+	CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountResponse, error)
+	// 만기가 된 정기예금을 처리합니다. 서버가 -maturity_interval마다 실행하며, 관리자가 바로 실행할 수도 있습니다.
+	MatureAccounts(ctx context.Context, in *MatureAccountsRequest, opts ...grpc.CallOption) (*MatureAccountsResponse, error)
+	// 거래 내역 조회 (해지한 계좌도 조회 가능)
 	GetTransactionHistory(ctx context.Context, in *GetTransactionHistoryRequest, opts ...grpc.CallOption) (*GetTransactionHistoryResponse, error)
+	// 계좌에 기록되는 거래를 오래된 것부터 실시간으로 받습니다.
+	WatchTransactions(ctx context.Context, in *WatchTransactionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransactionEvent], error)
 }
 
 type transactionServiceClient struct {
@@ -63,6 +78,36 @@ func (c *transactionServiceClient) Withdraw(ctx context.Context, in *WithdrawReq
 	return out, nil
 }
 
+func (c *transactionServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, TransactionService_Transfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) CloseAccount(ctx context.Context, in *CloseAccountRequest, opts ...grpc.CallOption) (*CloseAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseAccountResponse)
+	err := c.cc.Invoke(ctx, TransactionService_CloseAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactionServiceClient) MatureAccounts(ctx context.Context, in *MatureAccountsRequest, opts ...grpc.CallOption) (*MatureAccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatureAccountsResponse)
+	err := c.cc.Invoke(ctx, TransactionService_MatureAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *transactionServiceClient) GetTransactionHistory(ctx context.Context, in *GetTransactionHistoryRequest, opts ...grpc.CallOption) (*GetTransactionHistoryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetTransactionHistoryResponse)
@@ -73,15 +118,45 @@ func (c *transactionServiceClient) GetTransactionHistory(ctx context.Context, in
 	return out, nil
 }
 
+func (c *transactionServiceClient) WatchTransactions(ctx context.Context, in *WatchTransactionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TransactionEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TransactionService_ServiceDesc.Streams[0], TransactionService_WatchTransactions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTransactionsRequest, TransactionEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransactionService_WatchTransactionsClient = grpc.ServerStreamingClient[TransactionEvent]
+
 // TransactionServiceServer is the server API for TransactionService service.
 // All implementations must embed UnimplementedTransactionServiceServer
 // for forward compatibility.
 type TransactionServiceServer interface {
 	// 입금/출금
 	Deposit(context.Context, *DepositRequest) (*TransactionResponse, error)
+	// 출금, 이체, 해지 지급 이체는 -step_up_threshold를 넘으면 gRPC 메타데이터 "otp-code"에 TOTP 코드나 복구 코드가 필요합니다.
+	// 2단계 인증을 등록하지 않은 사용자는 UserService.EnrollTOTP와 ConfirmTOTP로 등록하기 전까지 FAILED_PRECONDITION을 받습니다.
+	// 틀린 코드는 로그인 실패와 같은 기준으로 사용자별로 세어, 너무 많이 틀리면 잠금 시간 동안 RESOURCE_EXHAUSTED를 받습니다.
 	Withdraw(context.Context, *WithdrawRequest) (*TransactionResponse, error)
-	// 거래 내역 조회
+	// 계좌 이체
+	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
+	// 계좌 해지. 남은 잔액을 옮기는 이체와 함께 처리합니다.
+	CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountResponse, error)
+	// 만기가 된 정기예금을 처리합니다. 서버가 -maturity_interval마다 실행하며, 관리자가 바로 실행할 수도 있습니다.
+	MatureAccounts(context.Context, *MatureAccountsRequest) (*MatureAccountsResponse, error)
+	// 거래 내역 조회 (해지한 계좌도 조회 가능)
 	GetTransactionHistory(context.Context, *GetTransactionHistoryRequest) (*GetTransactionHistoryResponse, error)
+	// 계좌에 기록되는 거래를 오래된 것부터 실시간으로 받습니다.
+	WatchTransactions(*WatchTransactionsRequest, grpc.ServerStreamingServer[TransactionEvent]) error
 	mustEmbedUnimplementedTransactionServiceServer()
 }
 
@@ -98,9 +173,21 @@ func (UnimplementedTransactionServiceServer) Deposit(context.Context, *DepositRe
 func (UnimplementedTransactionServiceServer) Withdraw(context.Context, *WithdrawRequest) (*TransactionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Withdraw not implemented")
 }
+func (UnimplementedTransactionServiceServer) Transfer(context.Context, *TransferRequest) (*TransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (UnimplementedTransactionServiceServer) CloseAccount(context.Context, *CloseAccountRequest) (*CloseAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseAccount not implemented")
+}
+func (UnimplementedTransactionServiceServer) MatureAccounts(context.Context, *MatureAccountsRequest) (*MatureAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatureAccounts not implemented")
+}
 func (UnimplementedTransactionServiceServer) GetTransactionHistory(context.Context, *GetTransactionHistoryRequest) (*GetTransactionHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionHistory not implemented")
 }
+func (UnimplementedTransactionServiceServer) WatchTransactions(*WatchTransactionsRequest, grpc.ServerStreamingServer[TransactionEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTransactions not implemented")
+}
 func (UnimplementedTransactionServiceServer) mustEmbedUnimplementedTransactionServiceServer() {}
 func (UnimplementedTransactionServiceServer) testEmbeddedByValue()                            {}
 
@@ -158,6 +245,60 @@ func _TransactionService_Withdraw_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TransactionService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_CloseAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).CloseAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_CloseAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).CloseAccount(ctx, req.(*CloseAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TransactionService_MatureAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatureAccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactionServiceServer).MatureAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TransactionService_MatureAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactionServiceServer).MatureAccounts(ctx, req.(*MatureAccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TransactionService_GetTransactionHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetTransactionHistoryRequest)
 	if err := dec(in); err != nil {
@@ -176,6 +317,17 @@ func _TransactionService_GetTransactionHistory_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TransactionService_WatchTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTransactionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TransactionServiceServer).WatchTransactions(m, &grpc.GenericServerStream[WatchTransactionsRequest, TransactionEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TransactionService_WatchTransactionsServer = grpc.ServerStreamingServer[TransactionEvent]
+
 // TransactionService_ServiceDesc is the grpc.ServiceDesc for TransactionService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,11 +343,29 @@ var TransactionService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Withdraw",
 			Handler:    _TransactionService_Withdraw_Handler,
 		},
+		{
+			MethodName: "Transfer",
+			Handler:    _TransactionService_Transfer_Handler,
+		},
+		{
+			MethodName: "CloseAccount",
+			Handler:    _TransactionService_CloseAccount_Handler,
+		},
+		{
+			MethodName: "MatureAccounts",
+			Handler:    _TransactionService_MatureAccounts_Handler,
+		},
 		{
 			MethodName: "GetTransactionHistory",
 			Handler:    _TransactionService_GetTransactionHistory_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTransactions",
+			Handler:       _TransactionService_WatchTransactions_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "api/v1/transaction.proto",
 }
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"flag"
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -10,24 +10,21 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 
 	"ebank/api/v1"
 	"ebank/pkg/config"
-	"ebank/services/account/repository"
+	"ebank/pkg/gateway"
+	"ebank/pkg/jwt_manager"
+	"ebank/pkg/storage"
 	accountService "ebank/services/account/service"
-)
-
-var (
-	// command-line options:
-	// gRPC server endpoint
-	grpcServerEndpoint = flag.String("grpc-server-endpoint", "localhost:9090", "gRPC server endpoint")
+	userService "ebank/services/user/service"
 )
 
 func main() {
-	cfg := config.New()
+	cfg := config.New(config.AccountDefaults)
+	cfg.RequireSharedDB()
 	lis, err := net.Listen("tcp", cfg.Server.Port)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
@@ -35,31 +32,76 @@ func main() {
 
 	logrusEntry := logrus.NewEntry(logrus.StandardLogger())
 
+	storage, err := storage.Open(cfg.DB)
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
+	}
+	defer storage.Close()
+
+	// 토큰 검증 시 로그아웃된 세션인지 확인합니다.
+	sessionRepository, err := storage.SessionRepository()
+	if err != nil {
+		log.Fatalf("failed to make sessionRepository: %v", err)
+	}
+
+	jwtKeys, err := jwt_manager.OpenVerificationKeys(cfg.Jwt)
+	if err != nil {
+		log.Fatalf("failed to open jwt keys: %v", err)
+	}
+	jwtManager := jwt_manager.NewJWTManager(jwtKeys, cfg.Jwt.Duration, cfg.Jwt.RefreshDuration, sessionRepository)
+	interceptor := userService.NewUserInterceptor(jwtManager)
+
 	s := grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			grpc_logrus.UnaryServerInterceptor(logrusEntry),
 			grpc_recovery.UnaryServerInterceptor(),
+			interceptor.Unary(),
 		)),
+		grpc.ChainStreamInterceptor(
+			grpc_logrus.StreamServerInterceptor(logrusEntry),
+			grpc_recovery.StreamServerInterceptor(),
+			interceptor.Stream(),
+		),
 	)
 
-	accountRepository, err := repository.NewAccountFileRepository(cfg.DB.AccountTablePath)
+	// 계좌 주인 확인에 사용자 저장소가 필요합니다.
+	userRepository, err := storage.UserRepository()
+	if err != nil {
+		log.Fatalf("failed to make userRepository: %v", err)
+	}
+
+	accountRepository, err := storage.AccountRepository()
 	if err != nil {
 		log.Fatalf("failed to make accountRepository: %v", err)
 	}
 
-	accountService := accountService.NewAccountService(accountRepository)
+	// OTP 코드를 틀린 횟수는 user 서비스와 같은 저장소에 세어, 어느 서비스에서 틀려도 함께 잠깁니다.
+	loginAttemptRepository, err := storage.LoginAttemptRepository()
+	if err != nil {
+		log.Fatalf("failed to make loginAttemptRepository: %v", err)
+	}
+	otpLimiter := userService.NewLoginLimiter(loginAttemptRepository, userService.LoginPolicy{
+		MaxFailures:      cfg.Auth.MaxLoginFailures,
+		MaxFailuresPerIP: cfg.Auth.MaxLoginFailuresPerIP,
+		Backoff:          cfg.Auth.LoginBackoff,
+		Lockout:          cfg.Auth.LockoutDuration,
+	})
+
+	accountService := accountService.NewAccountService(userService.NewUserHelper(userRepository, otpLimiter), accountRepository, cfg.Account.BranchCode, storage.Changes())
 
 	ebank.RegisterAccountServiceServer(s, accountService)
 
-	mux := runtime.NewServeMux()
-	// opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	// err = ebank.RegisterAccountServiceHandlerFromEndpoint(context.TODO(), mux, *grpcServerEndpoint, opts)
-	// if err != nil {
-	// 	log.Fatalf("Failed to register handler: %v", err)
-	// }
+	handler, err := gateway.NewHandler(context.Background(), cfg.Server.Port, gateway.AccountService)
+	if err != nil {
+		log.Fatalf("failed to make gateway handler: %v", err)
+	}
 
-	// Start HTTP server (and proxy calls to gRPC server endpoint)
-	http.ListenAndServe(":8081", mux)
+	go func() {
+		fmt.Println("HTTP server is running on " + cfg.Server.HTTPPort)
+		if err := http.ListenAndServe(cfg.Server.HTTPPort, handler); err != nil {
+			log.Fatalf("Failed to serve HTTP: %v", err)
+		}
+	}()
 
 	fmt.Println("Server is running on " + cfg.Server.Port)
 	if err := s.Serve(lis); err != nil {
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
+	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"ebank/api/v1"
+	"ebank/pkg/config"
+	"ebank/pkg/gateway"
+	"ebank/pkg/jwt_manager"
+	"ebank/pkg/money"
+	"ebank/pkg/storage"
+	accountService "ebank/services/account/service"
+	ledgerService "ebank/services/ledger/service"
+	transactionService "ebank/services/transaction/service"
+	authService "ebank/services/user/service"
+)
+
+// User, Auth, Account, Transaction 서비스를 하나의 gRPC 서버에서 실행합니다.
+// 서비스별로 나누어 배포할 때는 cmd/user, cmd/account, cmd/transaction을 사용합니다.
+func main() {
+	cfg := config.New(config.EbankDefaults)
+	lis, err := net.Listen("tcp", cfg.Server.Port)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+
+	logrusEntry := logrus.NewEntry(logrus.StandardLogger())
+
+	// 파일 저장소는 같은 파일을 한 번만 열어야 하므로 저장소를 한 번씩 만들어 서비스끼리 공유합니다.
+	storage, err := storage.Open(cfg.DB)
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
+	}
+	defer storage.Close()
+
+	// 토큰 검증 시 로그아웃된 세션인지 확인합니다.
+	sessionRepository, err := storage.SessionRepository()
+	if err != nil {
+		log.Fatalf("failed to make sessionRepository: %v", err)
+	}
+
+	jwtKeys, err := jwt_manager.OpenSigningKeys(cfg.Jwt)
+	if err != nil {
+		log.Fatalf("failed to open jwt keys: %v", err)
+	}
+	jwtManager := jwt_manager.NewJWTManager(jwtKeys, cfg.Jwt.Duration, cfg.Jwt.RefreshDuration, sessionRepository)
+	interceptor := authService.NewUserInterceptor(jwtManager)
+
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+			grpc_logrus.UnaryServerInterceptor(logrusEntry),
+			grpc_recovery.UnaryServerInterceptor(),
+			interceptor.Unary(),
+		)),
+		grpc.ChainStreamInterceptor(
+			grpc_logrus.StreamServerInterceptor(logrusEntry),
+			grpc_recovery.StreamServerInterceptor(),
+			interceptor.Stream(),
+		),
+	)
+
+	userRepository, err := storage.UserRepository()
+	if err != nil {
+		log.Fatalf("failed to make userRepository: %v", err)
+	}
+
+	if cfg.Auth.AdminPhoneNumber != "" {
+		if err := authService.EnsureAdmin(context.Background(), userRepository, cfg.Auth.AdminPhoneNumber); err != nil {
+			log.Fatalf("failed to promote admin: %v", err)
+		}
+	}
+
+	accountRepository, err := storage.AccountRepository()
+	if err != nil {
+		log.Fatalf("failed to make accountRepository: %v", err)
+	}
+
+	transactionRepository, err := storage.TransactionRepository()
+	if err != nil {
+		log.Fatalf("failed to make transactionRepository: %v", err)
+	}
+
+	idempotencyRepository, err := storage.IdempotencyRepository()
+	if err != nil {
+		log.Fatalf("failed to make idempotencyRepository: %v", err)
+	}
+
+	journalEntryRepository, err := storage.JournalEntryRepository()
+	if err != nil {
+		log.Fatalf("failed to make journalEntryRepository: %v", err)
+	}
+
+	ledger := ledgerService.NewLedger(journalEntryRepository)
+
+	// 원장이 비어 있으면 원장 도입 이전의 계좌 잔액을 기초 잔액으로 옮기고, 그 뒤에는 원장과 계좌 잔액이 맞는지 확인만 합니다.
+	// 맞지 않아도 바로잡는 분개를 기록하지 않고 로그로 알립니다.
+	accounts, err := accountRepository.GetAllAccounts(context.Background())
+	if err != nil {
+		log.Fatalf("failed to load accounts: %v", err)
+	}
+	balances := make(map[int64]money.Money, len(accounts))
+	for _, account := range accounts {
+		balances[account.ID] = account.Balance
+	}
+	if err := ledger.Reconcile(context.Background(), balances); errors.Is(err, ledgerService.ErrUnbalanced) {
+		log.Printf("ledger check failed: %v", err)
+	} else if err != nil {
+		log.Fatalf("failed to reconcile ledger: %v", err)
+	}
+
+	loginAttemptRepository, err := storage.LoginAttemptRepository()
+	if err != nil {
+		log.Fatalf("failed to make loginAttemptRepository: %v", err)
+	}
+	loginLimiter := authService.NewLoginLimiter(loginAttemptRepository, authService.LoginPolicy{
+		MaxFailures:      cfg.Auth.MaxLoginFailures,
+		MaxFailuresPerIP: cfg.Auth.MaxLoginFailuresPerIP,
+		Backoff:          cfg.Auth.LoginBackoff,
+		Lockout:          cfg.Auth.LockoutDuration,
+	})
+
+	userHelper := authService.NewUserHelper(userRepository, loginLimiter)
+
+	ebank.RegisterUserServiceServer(s, authService.NewUserService(userHelper, userRepository, accountRepository, sessionRepository, loginLimiter))
+	ebank.RegisterAuthServiceServer(s, authService.NewAuthService(userHelper, userRepository, sessionRepository, jwtManager, loginLimiter))
+	ebank.RegisterAccountServiceServer(s, accountService.NewAccountService(userHelper, accountRepository, cfg.Account.BranchCode, storage.Changes()))
+	transactionServer := transactionService.NewTransactionService(userHelper, accountRepository, transactionRepository, idempotencyRepository, ledger, storage, cfg.Auth.StepUpThresholds, storage.Changes())
+	ebank.RegisterTransactionServiceServer(s, transactionServer)
+	go transactionService.RunMaturity(context.Background(), transactionServer, cfg.Account.MaturityInterval)
+	go transactionService.RunIdempotencyKeyCleanup(context.Background(), idempotencyRepository, cfg.Transaction.IdempotencyKeyTTL)
+
+	handler, err := gateway.NewHandler(context.Background(), cfg.Server.Port, gateway.UserService, gateway.AuthService, gateway.AccountService, gateway.TransactionService)
+	if err != nil {
+		log.Fatalf("failed to make gateway handler: %v", err)
+	}
+
+	// 토큰을 검증만 하는 서비스가 개인 키 없이 검증하도록 공개 키를 제공합니다.
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	mux.Handle(jwt_manager.JWKSPath, jwt_manager.JWKSHandler(jwtKeys))
+
+	go func() {
+		fmt.Println("HTTP server is running on " + cfg.Server.HTTPPort)
+		if err := http.ListenAndServe(cfg.Server.HTTPPort, mux); err != nil {
+			log.Fatalf("Failed to serve HTTP: %v", err)
+		}
+	}()
+
+	fmt.Println("Server is running on " + cfg.Server.Port)
+	if err := s.Serve(lis); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+}
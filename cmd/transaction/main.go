@@ -1,7 +1,8 @@
 package main
 
 import (
-	"flag"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -10,24 +11,23 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 
 	"ebank/api/v1"
 	"ebank/pkg/config"
-	"ebank/services/transaction/repository"
+	"ebank/pkg/gateway"
+	"ebank/pkg/jwt_manager"
+	"ebank/pkg/money"
+	"ebank/pkg/storage"
+	ledgerService "ebank/services/ledger/service"
 	transactionService "ebank/services/transaction/service"
-)
-
-var (
-	// command-line options:
-	// gRPC server endpoint
-	grpcServerEndpoint = flag.String("grpc-server-endpoint", "localhost:9090", "gRPC server endpoint")
+	userService "ebank/services/user/service"
 )
 
 func main() {
-	cfg := config.New()
+	cfg := config.New(config.TransactionDefaults)
+	cfg.RequireSharedDB()
 	lis, err := net.Listen("tcp", cfg.Server.Port)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
@@ -35,26 +35,111 @@ func main() {
 
 	logrusEntry := logrus.NewEntry(logrus.StandardLogger())
 
+	storage, err := storage.Open(cfg.DB)
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
+	}
+	defer storage.Close()
+
+	// 토큰 검증 시 로그아웃된 세션인지 확인합니다.
+	sessionRepository, err := storage.SessionRepository()
+	if err != nil {
+		log.Fatalf("failed to make sessionRepository: %v", err)
+	}
+
+	jwtKeys, err := jwt_manager.OpenVerificationKeys(cfg.Jwt)
+	if err != nil {
+		log.Fatalf("failed to open jwt keys: %v", err)
+	}
+	jwtManager := jwt_manager.NewJWTManager(jwtKeys, cfg.Jwt.Duration, cfg.Jwt.RefreshDuration, sessionRepository)
+	interceptor := userService.NewUserInterceptor(jwtManager)
+
 	s := grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			grpc_logrus.UnaryServerInterceptor(logrusEntry),
 			grpc_recovery.UnaryServerInterceptor(),
+			interceptor.Unary(),
 		)),
+		grpc.ChainStreamInterceptor(
+			grpc_logrus.StreamServerInterceptor(logrusEntry),
+			grpc_recovery.StreamServerInterceptor(),
+			interceptor.Stream(),
+		),
 	)
 
-	transactionRepository, err := repository.NewTransactionFileRepository(cfg.DB.TransactionTablePath)
+	// 계좌 주인 확인에 사용자 저장소가 필요합니다.
+	userRepository, err := storage.UserRepository()
+	if err != nil {
+		log.Fatalf("failed to make userRepository: %v", err)
+	}
+
+	accountRepository, err := storage.AccountRepository()
+	if err != nil {
+		log.Fatalf("failed to make accountRepository: %v", err)
+	}
+
+	transactionRepository, err := storage.TransactionRepository()
 	if err != nil {
 		log.Fatalf("failed to make transactionRepository: %v", err)
 	}
 
-	transactionService := transactionService.NewTransactionService(transactionRepository)
+	idempotencyRepository, err := storage.IdempotencyRepository()
+	if err != nil {
+		log.Fatalf("failed to make idempotencyRepository: %v", err)
+	}
+
+	journalEntryRepository, err := storage.JournalEntryRepository()
+	if err != nil {
+		log.Fatalf("failed to make journalEntryRepository: %v", err)
+	}
 
-	ebank.RegisterTransactionServiceServer(s, transactionService)
+	ledger := ledgerService.NewLedger(journalEntryRepository)
 
-	mux := runtime.NewServeMux()
+	// 원장이 비어 있으면 원장 도입 이전의 계좌 잔액을 기초 잔액으로 옮기고, 그 뒤에는 원장과 계좌 잔액이 맞는지 확인만 합니다.
+	// 맞지 않아도 바로잡는 분개를 기록하지 않고 로그로 알립니다.
+	accounts, err := accountRepository.GetAllAccounts(context.Background())
+	if err != nil {
+		log.Fatalf("failed to load accounts: %v", err)
+	}
+	balances := make(map[int64]money.Money, len(accounts))
+	for _, account := range accounts {
+		balances[account.ID] = account.Balance
+	}
+	if err := ledger.Reconcile(context.Background(), balances); errors.Is(err, ledgerService.ErrUnbalanced) {
+		log.Printf("ledger check failed: %v", err)
+	} else if err != nil {
+		log.Fatalf("failed to reconcile ledger: %v", err)
+	}
+
+	// OTP 코드를 틀린 횟수는 user 서비스와 같은 저장소에 세어, 어느 서비스에서 틀려도 함께 잠깁니다.
+	loginAttemptRepository, err := storage.LoginAttemptRepository()
+	if err != nil {
+		log.Fatalf("failed to make loginAttemptRepository: %v", err)
+	}
+	otpLimiter := userService.NewLoginLimiter(loginAttemptRepository, userService.LoginPolicy{
+		MaxFailures:      cfg.Auth.MaxLoginFailures,
+		MaxFailuresPerIP: cfg.Auth.MaxLoginFailuresPerIP,
+		Backoff:          cfg.Auth.LoginBackoff,
+		Lockout:          cfg.Auth.LockoutDuration,
+	})
+
+	transactionServer := transactionService.NewTransactionService(userService.NewUserHelper(userRepository, otpLimiter), accountRepository, transactionRepository, idempotencyRepository, ledger, storage, cfg.Auth.StepUpThresholds, storage.Changes())
+
+	ebank.RegisterTransactionServiceServer(s, transactionServer)
+	go transactionService.RunMaturity(context.Background(), transactionServer, cfg.Account.MaturityInterval)
+	go transactionService.RunIdempotencyKeyCleanup(context.Background(), idempotencyRepository, cfg.Transaction.IdempotencyKeyTTL)
+
+	handler, err := gateway.NewHandler(context.Background(), cfg.Server.Port, gateway.TransactionService)
+	if err != nil {
+		log.Fatalf("failed to make gateway handler: %v", err)
+	}
 
-	// Start HTTP server (and proxy calls to gRPC server endpoint)
-	http.ListenAndServe(":8081", mux)
+	go func() {
+		fmt.Println("HTTP server is running on " + cfg.Server.HTTPPort)
+		if err := http.ListenAndServe(cfg.Server.HTTPPort, handler); err != nil {
+			log.Fatalf("Failed to serve HTTP: %v", err)
+		}
+	}()
 
 	fmt.Println("Server is running on " + cfg.Server.Port)
 	if err := s.Serve(lis); err != nil {
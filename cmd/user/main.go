@@ -1,7 +1,7 @@
 package main
 
 import (
-	"flag"
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -10,25 +10,20 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 
 	"ebank/api/v1"
 	"ebank/pkg/config"
+	"ebank/pkg/gateway"
 	"ebank/pkg/jwt_manager"
-	"ebank/services/user/repository"
+	"ebank/pkg/storage"
 	authService "ebank/services/user/service"
 )
 
-var (
-	// command-line options:
-	// gRPC server endpoint
-	grpcServerEndpoint = flag.String("grpc-server-endpoint", "localhost:9090", "gRPC server endpoint")
-)
-
 func main() {
-	cfg := config.New()
+	cfg := config.New(config.UserDefaults)
+	cfg.RequireSharedDB()
 	lis, err := net.Listen("tcp", cfg.Server.Port)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
@@ -36,7 +31,23 @@ func main() {
 
 	logrusEntry := logrus.NewEntry(logrus.StandardLogger())
 
-	jwtManager := jwt_manager.NewJWTManager(cfg.Jwt.SecretKey, cfg.Jwt.Duration)
+	storage, err := storage.Open(cfg.DB)
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
+	}
+	defer storage.Close()
+
+	// 토큰 검증 시 로그아웃된 세션인지 확인합니다.
+	sessionRepository, err := storage.SessionRepository()
+	if err != nil {
+		log.Fatalf("failed to make sessionRepository: %v", err)
+	}
+
+	jwtKeys, err := jwt_manager.OpenSigningKeys(cfg.Jwt)
+	if err != nil {
+		log.Fatalf("failed to open jwt keys: %v", err)
+	}
+	jwtManager := jwt_manager.NewJWTManager(jwtKeys, cfg.Jwt.Duration, cfg.Jwt.RefreshDuration, sessionRepository)
 	interceptor := authService.NewUserInterceptor(jwtManager)
 
 	s := grpc.NewServer(
@@ -45,29 +56,64 @@ func main() {
 			grpc_recovery.UnaryServerInterceptor(),
 			interceptor.Unary(),
 		)),
+		grpc.ChainStreamInterceptor(
+			grpc_logrus.StreamServerInterceptor(logrusEntry),
+			grpc_recovery.StreamServerInterceptor(),
+			interceptor.Stream(),
+		),
 	)
 
-	userFileRepository, err := repository.NewUserFileRepository(cfg.DB.UserTablePath)
+	userRepository, err := storage.UserRepository()
 	if err != nil {
-		log.Fatalf("failed to make userFileRepository: %v", err)
+		log.Fatalf("failed to make userRepository: %v", err)
 	}
 
-	userHelper := authService.NewUserHelper(userFileRepository)
-	userService := authService.NewUserService(userHelper, userFileRepository, jwtManager)
-	authService := authService.NewAuthService(userFileRepository, jwtManager)
+	if cfg.Auth.AdminPhoneNumber != "" {
+		if err := authService.EnsureAdmin(context.Background(), userRepository, cfg.Auth.AdminPhoneNumber); err != nil {
+			log.Fatalf("failed to promote admin: %v", err)
+		}
+	}
+
+	// GetUser가 사용자의 계좌를 함께 보여줄 때 읽습니다.
+	accountRepository, err := storage.AccountRepository()
+	if err != nil {
+		log.Fatalf("failed to make accountRepository: %v", err)
+	}
+
+	loginAttemptRepository, err := storage.LoginAttemptRepository()
+	if err != nil {
+		log.Fatalf("failed to make loginAttemptRepository: %v", err)
+	}
+	loginLimiter := authService.NewLoginLimiter(loginAttemptRepository, authService.LoginPolicy{
+		MaxFailures:      cfg.Auth.MaxLoginFailures,
+		MaxFailuresPerIP: cfg.Auth.MaxLoginFailuresPerIP,
+		Backoff:          cfg.Auth.LoginBackoff,
+		Lockout:          cfg.Auth.LockoutDuration,
+	})
+
+	userHelper := authService.NewUserHelper(userRepository, loginLimiter)
+	userService := authService.NewUserService(userHelper, userRepository, accountRepository, sessionRepository, loginLimiter)
+	authService := authService.NewAuthService(userHelper, userRepository, sessionRepository, jwtManager, loginLimiter)
 
 	ebank.RegisterUserServiceServer(s, userService)
 	ebank.RegisterAuthServiceServer(s, authService)
 
-	mux := runtime.NewServeMux()
-	// opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-	// err = ebank.RegisterAccountServiceHandlerFromEndpoint(context.TODO(), mux, *grpcServerEndpoint, opts)
-	// if err != nil {
-	// 	log.Fatalf("Failed to register handler: %v", err)
-	// }
+	handler, err := gateway.NewHandler(context.Background(), cfg.Server.Port, gateway.UserService, gateway.AuthService)
+	if err != nil {
+		log.Fatalf("failed to make gateway handler: %v", err)
+	}
+
+	// 토큰을 검증만 하는 서비스가 개인 키 없이 검증하도록 공개 키를 제공합니다.
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	mux.Handle(jwt_manager.JWKSPath, jwt_manager.JWKSHandler(jwtKeys))
 
-	// Start HTTP server (and proxy calls to gRPC server endpoint)
-	http.ListenAndServe(":8081", mux)
+	go func() {
+		fmt.Println("HTTP server is running on " + cfg.Server.HTTPPort)
+		if err := http.ListenAndServe(cfg.Server.HTTPPort, mux); err != nil {
+			log.Fatalf("Failed to serve HTTP: %v", err)
+		}
+	}()
 
 	fmt.Println("Server is running on " + cfg.Server.Port)
 	if err := s.Serve(lis); err != nil {
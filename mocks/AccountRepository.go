@@ -4,7 +4,7 @@ package mocks
 
 import (
 	context "context"
-	model "ebank/internal/model"
+	model "ebank/services/account/model"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -302,6 +302,65 @@ func (_c *AccountRepository_GetAllAccounts_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// ListAccounts provides a mock function with given fields: ctx, query
+func (_m *AccountRepository) ListAccounts(ctx context.Context, query model.AccountQuery) ([]model.Account, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAccounts")
+	}
+
+	var r0 []model.Account
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.AccountQuery) ([]model.Account, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.AccountQuery) []model.Account); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Account)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.AccountQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountRepository_ListAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAccounts'
+type AccountRepository_ListAccounts_Call struct {
+	*mock.Call
+}
+
+// ListAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query model.AccountQuery
+func (_e *AccountRepository_Expecter) ListAccounts(ctx interface{}, query interface{}) *AccountRepository_ListAccounts_Call {
+	return &AccountRepository_ListAccounts_Call{Call: _e.mock.On("ListAccounts", ctx, query)}
+}
+
+func (_c *AccountRepository_ListAccounts_Call) Run(run func(ctx context.Context, query model.AccountQuery)) *AccountRepository_ListAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.AccountQuery))
+	})
+	return _c
+}
+
+func (_c *AccountRepository_ListAccounts_Call) Return(_a0 []model.Account, _a1 error) *AccountRepository_ListAccounts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountRepository_ListAccounts_Call) RunAndReturn(run func(context.Context, model.AccountQuery) ([]model.Account, error)) *AccountRepository_ListAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // LockAccountByID provides a mock function with given fields: ctx, id
 func (_m *AccountRepository) LockAccountByID(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -349,6 +408,63 @@ func (_c *AccountRepository_LockAccountByID_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// NextAccountNumberSequence provides a mock function with given fields: ctx, prefix
+func (_m *AccountRepository) NextAccountNumberSequence(ctx context.Context, prefix string) (int64, error) {
+	ret := _m.Called(ctx, prefix)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NextAccountNumberSequence")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, prefix)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccountRepository_NextAccountNumberSequence_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NextAccountNumberSequence'
+type AccountRepository_NextAccountNumberSequence_Call struct {
+	*mock.Call
+}
+
+// NextAccountNumberSequence is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+func (_e *AccountRepository_Expecter) NextAccountNumberSequence(ctx interface{}, prefix interface{}) *AccountRepository_NextAccountNumberSequence_Call {
+	return &AccountRepository_NextAccountNumberSequence_Call{Call: _e.mock.On("NextAccountNumberSequence", ctx, prefix)}
+}
+
+func (_c *AccountRepository_NextAccountNumberSequence_Call) Run(run func(ctx context.Context, prefix string)) *AccountRepository_NextAccountNumberSequence_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AccountRepository_NextAccountNumberSequence_Call) Return(_a0 int64, _a1 error) *AccountRepository_NextAccountNumberSequence_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AccountRepository_NextAccountNumberSequence_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *AccountRepository_NextAccountNumberSequence_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UnlockAccountByID provides a mock function with given fields: ctx, id
 func (_m *AccountRepository) UnlockAccountByID(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -397,7 +513,7 @@ func (_c *AccountRepository_UnlockAccountByID_Call) RunAndReturn(run func(contex
 }
 
 // UpdateAccount provides a mock function with given fields: ctx, account
-func (_m *AccountRepository) UpdateAccount(ctx context.Context, account model.Account) error {
+func (_m *AccountRepository) UpdateAccount(ctx context.Context, account *model.Account) error {
 	ret := _m.Called(ctx, account)
 
 	if len(ret) == 0 {
@@ -405,7 +521,7 @@ func (_m *AccountRepository) UpdateAccount(ctx context.Context, account model.Ac
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.Account) error); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Account) error); ok {
 		r0 = rf(ctx, account)
 	} else {
 		r0 = ret.Error(0)
@@ -421,14 +537,14 @@ type AccountRepository_UpdateAccount_Call struct {
 
 // UpdateAccount is a helper method to define mock.On call
 //   - ctx context.Context
-//   - account model.Account
+//   - account *model.Account
 func (_e *AccountRepository_Expecter) UpdateAccount(ctx interface{}, account interface{}) *AccountRepository_UpdateAccount_Call {
 	return &AccountRepository_UpdateAccount_Call{Call: _e.mock.On("UpdateAccount", ctx, account)}
 }
 
-func (_c *AccountRepository_UpdateAccount_Call) Run(run func(ctx context.Context, account model.Account)) *AccountRepository_UpdateAccount_Call {
+func (_c *AccountRepository_UpdateAccount_Call) Run(run func(ctx context.Context, account *model.Account)) *AccountRepository_UpdateAccount_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(model.Account))
+		run(args[0].(context.Context), args[1].(*model.Account))
 	})
 	return _c
 }
@@ -438,7 +554,7 @@ func (_c *AccountRepository_UpdateAccount_Call) Return(_a0 error) *AccountReposi
 	return _c
 }
 
-func (_c *AccountRepository_UpdateAccount_Call) RunAndReturn(run func(context.Context, model.Account) error) *AccountRepository_UpdateAccount_Call {
+func (_c *AccountRepository_UpdateAccount_Call) RunAndReturn(run func(context.Context, *model.Account) error) *AccountRepository_UpdateAccount_Call {
 	_c.Call.Return(run)
 	return _c
 }
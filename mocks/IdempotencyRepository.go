@@ -0,0 +1,386 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "ebank/services/transaction/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// IdempotencyRepository is an autogenerated mock type for the IdempotencyRepository type
+type IdempotencyRepository struct {
+	mock.Mock
+}
+
+type IdempotencyRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *IdempotencyRepository) EXPECT() *IdempotencyRepository_Expecter {
+	return &IdempotencyRepository_Expecter{mock: &_m.Mock}
+}
+
+// CompleteIdempotencyKey provides a mock function with given fields: ctx, userID, key, response
+func (_m *IdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, userID int64, key string, response []byte) error {
+	ret := _m.Called(ctx, userID, key, response)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, []byte) error); ok {
+		r0 = rf(ctx, userID, key, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyRepository_CompleteIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteIdempotencyKey'
+type IdempotencyRepository_CompleteIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// CompleteIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - key string
+//   - response []byte
+func (_e *IdempotencyRepository_Expecter) CompleteIdempotencyKey(ctx interface{}, userID interface{}, key interface{}, response interface{}) *IdempotencyRepository_CompleteIdempotencyKey_Call {
+	return &IdempotencyRepository_CompleteIdempotencyKey_Call{Call: _e.mock.On("CompleteIdempotencyKey", ctx, userID, key, response)}
+}
+
+func (_c *IdempotencyRepository_CompleteIdempotencyKey_Call) Run(run func(ctx context.Context, userID int64, key string, response []byte)) *IdempotencyRepository_CompleteIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string), args[3].([]byte))
+	})
+	return _c
+}
+
+func (_c *IdempotencyRepository_CompleteIdempotencyKey_Call) Return(_a0 error) *IdempotencyRepository_CompleteIdempotencyKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IdempotencyRepository_CompleteIdempotencyKey_Call) RunAndReturn(run func(context.Context, int64, string, []byte) error) *IdempotencyRepository_CompleteIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateIdempotencyKey provides a mock function with given fields: ctx, idempotencyKey
+func (_m *IdempotencyRepository) CreateIdempotencyKey(ctx context.Context, idempotencyKey model.IdempotencyKey) error {
+	ret := _m.Called(ctx, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.IdempotencyKey) error); ok {
+		r0 = rf(ctx, idempotencyKey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyRepository_CreateIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIdempotencyKey'
+type IdempotencyRepository_CreateIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// CreateIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - idempotencyKey model.IdempotencyKey
+func (_e *IdempotencyRepository_Expecter) CreateIdempotencyKey(ctx interface{}, idempotencyKey interface{}) *IdempotencyRepository_CreateIdempotencyKey_Call {
+	return &IdempotencyRepository_CreateIdempotencyKey_Call{Call: _e.mock.On("CreateIdempotencyKey", ctx, idempotencyKey)}
+}
+
+func (_c *IdempotencyRepository_CreateIdempotencyKey_Call) Run(run func(ctx context.Context, idempotencyKey model.IdempotencyKey)) *IdempotencyRepository_CreateIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.IdempotencyKey))
+	})
+	return _c
+}
+
+func (_c *IdempotencyRepository_CreateIdempotencyKey_Call) Return(_a0 error) *IdempotencyRepository_CreateIdempotencyKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IdempotencyRepository_CreateIdempotencyKey_Call) RunAndReturn(run func(context.Context, model.IdempotencyKey) error) *IdempotencyRepository_CreateIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteIdempotencyKey provides a mock function with given fields: ctx, userID, key
+func (_m *IdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyRepository_DeleteIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIdempotencyKey'
+type IdempotencyRepository_DeleteIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// DeleteIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - key string
+func (_e *IdempotencyRepository_Expecter) DeleteIdempotencyKey(ctx interface{}, userID interface{}, key interface{}) *IdempotencyRepository_DeleteIdempotencyKey_Call {
+	return &IdempotencyRepository_DeleteIdempotencyKey_Call{Call: _e.mock.On("DeleteIdempotencyKey", ctx, userID, key)}
+}
+
+func (_c *IdempotencyRepository_DeleteIdempotencyKey_Call) Run(run func(ctx context.Context, userID int64, key string)) *IdempotencyRepository_DeleteIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *IdempotencyRepository_DeleteIdempotencyKey_Call) Return(_a0 error) *IdempotencyRepository_DeleteIdempotencyKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IdempotencyRepository_DeleteIdempotencyKey_Call) RunAndReturn(run func(context.Context, int64, string) error) *IdempotencyRepository_DeleteIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteIdempotencyKeysBefore provides a mock function with given fields: ctx, before
+func (_m *IdempotencyRepository) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteIdempotencyKeysBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyRepository_DeleteIdempotencyKeysBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIdempotencyKeysBefore'
+type IdempotencyRepository_DeleteIdempotencyKeysBefore_Call struct {
+	*mock.Call
+}
+
+// DeleteIdempotencyKeysBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *IdempotencyRepository_Expecter) DeleteIdempotencyKeysBefore(ctx interface{}, before interface{}) *IdempotencyRepository_DeleteIdempotencyKeysBefore_Call {
+	return &IdempotencyRepository_DeleteIdempotencyKeysBefore_Call{Call: _e.mock.On("DeleteIdempotencyKeysBefore", ctx, before)}
+}
+
+func (_c *IdempotencyRepository_DeleteIdempotencyKeysBefore_Call) Run(run func(ctx context.Context, before time.Time)) *IdempotencyRepository_DeleteIdempotencyKeysBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *IdempotencyRepository_DeleteIdempotencyKeysBefore_Call) Return(_a0 error) *IdempotencyRepository_DeleteIdempotencyKeysBefore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IdempotencyRepository_DeleteIdempotencyKeysBefore_Call) RunAndReturn(run func(context.Context, time.Time) error) *IdempotencyRepository_DeleteIdempotencyKeysBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIdempotencyKey provides a mock function with given fields: ctx, userID, key
+func (_m *IdempotencyRepository) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*model.IdempotencyKey, error) {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdempotencyKey")
+	}
+
+	var r0 *model.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*model.IdempotencyKey, error)); ok {
+		return rf(ctx, userID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *model.IdempotencyKey); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.IdempotencyKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IdempotencyRepository_GetIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIdempotencyKey'
+type IdempotencyRepository_GetIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// GetIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - key string
+func (_e *IdempotencyRepository_Expecter) GetIdempotencyKey(ctx interface{}, userID interface{}, key interface{}) *IdempotencyRepository_GetIdempotencyKey_Call {
+	return &IdempotencyRepository_GetIdempotencyKey_Call{Call: _e.mock.On("GetIdempotencyKey", ctx, userID, key)}
+}
+
+func (_c *IdempotencyRepository_GetIdempotencyKey_Call) Run(run func(ctx context.Context, userID int64, key string)) *IdempotencyRepository_GetIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *IdempotencyRepository_GetIdempotencyKey_Call) Return(_a0 *model.IdempotencyKey, _a1 error) *IdempotencyRepository_GetIdempotencyKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *IdempotencyRepository_GetIdempotencyKey_Call) RunAndReturn(run func(context.Context, int64, string) (*model.IdempotencyKey, error)) *IdempotencyRepository_GetIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockIdempotencyKey provides a mock function with given fields: ctx, userID, key
+func (_m *IdempotencyRepository) LockIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyRepository_LockIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockIdempotencyKey'
+type IdempotencyRepository_LockIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// LockIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - key string
+func (_e *IdempotencyRepository_Expecter) LockIdempotencyKey(ctx interface{}, userID interface{}, key interface{}) *IdempotencyRepository_LockIdempotencyKey_Call {
+	return &IdempotencyRepository_LockIdempotencyKey_Call{Call: _e.mock.On("LockIdempotencyKey", ctx, userID, key)}
+}
+
+func (_c *IdempotencyRepository_LockIdempotencyKey_Call) Run(run func(ctx context.Context, userID int64, key string)) *IdempotencyRepository_LockIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *IdempotencyRepository_LockIdempotencyKey_Call) Return(_a0 error) *IdempotencyRepository_LockIdempotencyKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IdempotencyRepository_LockIdempotencyKey_Call) RunAndReturn(run func(context.Context, int64, string) error) *IdempotencyRepository_LockIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnlockIdempotencyKey provides a mock function with given fields: ctx, userID, key
+func (_m *IdempotencyRepository) UnlockIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyRepository_UnlockIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlockIdempotencyKey'
+type IdempotencyRepository_UnlockIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// UnlockIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - key string
+func (_e *IdempotencyRepository_Expecter) UnlockIdempotencyKey(ctx interface{}, userID interface{}, key interface{}) *IdempotencyRepository_UnlockIdempotencyKey_Call {
+	return &IdempotencyRepository_UnlockIdempotencyKey_Call{Call: _e.mock.On("UnlockIdempotencyKey", ctx, userID, key)}
+}
+
+func (_c *IdempotencyRepository_UnlockIdempotencyKey_Call) Run(run func(ctx context.Context, userID int64, key string)) *IdempotencyRepository_UnlockIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *IdempotencyRepository_UnlockIdempotencyKey_Call) Return(_a0 error) *IdempotencyRepository_UnlockIdempotencyKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IdempotencyRepository_UnlockIdempotencyKey_Call) RunAndReturn(run func(context.Context, int64, string) error) *IdempotencyRepository_UnlockIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewIdempotencyRepository creates a new instance of IdempotencyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyRepository {
+	mock := &IdempotencyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
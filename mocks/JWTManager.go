@@ -7,7 +7,7 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
-	model "ebank/internal/model"
+	model "ebank/services/user/model"
 )
 
 // JWTManager is an autogenerated mock type for the JWTManager type
@@ -23,9 +23,9 @@ func (_m *JWTManager) EXPECT() *JWTManager_Expecter {
 	return &JWTManager_Expecter{mock: &_m.Mock}
 }
 
-// Generate provides a mock function with given fields: user
-func (_m *JWTManager) Generate(user model.User) (string, error) {
-	ret := _m.Called(user)
+// Generate provides a mock function with given fields: user, sessionID
+func (_m *JWTManager) Generate(user model.User, sessionID string) (string, error) {
+	ret := _m.Called(user, sessionID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Generate")
@@ -33,17 +33,17 @@ func (_m *JWTManager) Generate(user model.User) (string, error) {
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(model.User) (string, error)); ok {
-		return rf(user)
+	if rf, ok := ret.Get(0).(func(model.User, string) (string, error)); ok {
+		return rf(user, sessionID)
 	}
-	if rf, ok := ret.Get(0).(func(model.User) string); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(model.User, string) string); ok {
+		r0 = rf(user, sessionID)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(model.User) error); ok {
-		r1 = rf(user)
+	if rf, ok := ret.Get(1).(func(model.User, string) error); ok {
+		r1 = rf(user, sessionID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -58,13 +58,14 @@ type JWTManager_Generate_Call struct {
 
 // Generate is a helper method to define mock.On call
 //   - user model.User
-func (_e *JWTManager_Expecter) Generate(user interface{}) *JWTManager_Generate_Call {
-	return &JWTManager_Generate_Call{Call: _e.mock.On("Generate", user)}
+//   - sessionID string
+func (_e *JWTManager_Expecter) Generate(user interface{}, sessionID interface{}) *JWTManager_Generate_Call {
+	return &JWTManager_Generate_Call{Call: _e.mock.On("Generate", user, sessionID)}
 }
 
-func (_c *JWTManager_Generate_Call) Run(run func(user model.User)) *JWTManager_Generate_Call {
+func (_c *JWTManager_Generate_Call) Run(run func(user model.User, sessionID string)) *JWTManager_Generate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(model.User))
+		run(args[0].(model.User), args[1].(string))
 	})
 	return _c
 }
@@ -74,7 +75,109 @@ func (_c *JWTManager_Generate_Call) Return(_a0 string, _a1 error) *JWTManager_Ge
 	return _c
 }
 
-func (_c *JWTManager_Generate_Call) RunAndReturn(run func(model.User) (string, error)) *JWTManager_Generate_Call {
+func (_c *JWTManager_Generate_Call) RunAndReturn(run func(model.User, string) (string, error)) *JWTManager_Generate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GenerateRefreshToken provides a mock function with given fields: session
+func (_m *JWTManager) GenerateRefreshToken(session model.Session) (string, error) {
+	ret := _m.Called(session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateRefreshToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(model.Session) (string, error)); ok {
+		return rf(session)
+	}
+	if rf, ok := ret.Get(0).(func(model.Session) string); ok {
+		r0 = rf(session)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(model.Session) error); ok {
+		r1 = rf(session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JWTManager_GenerateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GenerateRefreshToken'
+type JWTManager_GenerateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// GenerateRefreshToken is a helper method to define mock.On call
+//   - session model.Session
+func (_e *JWTManager_Expecter) GenerateRefreshToken(session interface{}) *JWTManager_GenerateRefreshToken_Call {
+	return &JWTManager_GenerateRefreshToken_Call{Call: _e.mock.On("GenerateRefreshToken", session)}
+}
+
+func (_c *JWTManager_GenerateRefreshToken_Call) Run(run func(session model.Session)) *JWTManager_GenerateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(model.Session))
+	})
+	return _c
+}
+
+func (_c *JWTManager_GenerateRefreshToken_Call) Return(_a0 string, _a1 error) *JWTManager_GenerateRefreshToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JWTManager_GenerateRefreshToken_Call) RunAndReturn(run func(model.Session) (string, error)) *JWTManager_GenerateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSession provides a mock function with given fields: userID
+func (_m *JWTManager) NewSession(userID int64) model.Session {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewSession")
+	}
+
+	var r0 model.Session
+	if rf, ok := ret.Get(0).(func(int64) model.Session); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(model.Session)
+	}
+
+	return r0
+}
+
+// JWTManager_NewSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NewSession'
+type JWTManager_NewSession_Call struct {
+	*mock.Call
+}
+
+// NewSession is a helper method to define mock.On call
+//   - userID int64
+func (_e *JWTManager_Expecter) NewSession(userID interface{}) *JWTManager_NewSession_Call {
+	return &JWTManager_NewSession_Call{Call: _e.mock.On("NewSession", userID)}
+}
+
+func (_c *JWTManager_NewSession_Call) Run(run func(userID int64)) *JWTManager_NewSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *JWTManager_NewSession_Call) Return(_a0 model.Session) *JWTManager_NewSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *JWTManager_NewSession_Call) RunAndReturn(run func(int64) model.Session) *JWTManager_NewSession_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -137,6 +240,64 @@ func (_c *JWTManager_Verify_Call) RunAndReturn(run func(string) (*jwt_manager.Us
 	return _c
 }
 
+// VerifyRefreshToken provides a mock function with given fields: refreshToken
+func (_m *JWTManager) VerifyRefreshToken(refreshToken string) (*jwt_manager.RefreshClaims, error) {
+	ret := _m.Called(refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyRefreshToken")
+	}
+
+	var r0 *jwt_manager.RefreshClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*jwt_manager.RefreshClaims, error)); ok {
+		return rf(refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(string) *jwt_manager.RefreshClaims); ok {
+		r0 = rf(refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*jwt_manager.RefreshClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JWTManager_VerifyRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyRefreshToken'
+type JWTManager_VerifyRefreshToken_Call struct {
+	*mock.Call
+}
+
+// VerifyRefreshToken is a helper method to define mock.On call
+//   - refreshToken string
+func (_e *JWTManager_Expecter) VerifyRefreshToken(refreshToken interface{}) *JWTManager_VerifyRefreshToken_Call {
+	return &JWTManager_VerifyRefreshToken_Call{Call: _e.mock.On("VerifyRefreshToken", refreshToken)}
+}
+
+func (_c *JWTManager_VerifyRefreshToken_Call) Run(run func(refreshToken string)) *JWTManager_VerifyRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *JWTManager_VerifyRefreshToken_Call) Return(_a0 *jwt_manager.RefreshClaims, _a1 error) *JWTManager_VerifyRefreshToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JWTManager_VerifyRefreshToken_Call) RunAndReturn(run func(string) (*jwt_manager.RefreshClaims, error)) *JWTManager_VerifyRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewJWTManager creates a new instance of JWTManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewJWTManager(t interface {
@@ -0,0 +1,268 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "ebank/services/ledger/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// JournalEntryRepository is an autogenerated mock type for the JournalEntryRepository type
+type JournalEntryRepository struct {
+	mock.Mock
+}
+
+type JournalEntryRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *JournalEntryRepository) EXPECT() *JournalEntryRepository_Expecter {
+	return &JournalEntryRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateJournalEntry provides a mock function with given fields: ctx, entry
+func (_m *JournalEntryRepository) CreateJournalEntry(ctx context.Context, entry model.JournalEntry) (model.JournalEntry, error) {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJournalEntry")
+	}
+
+	var r0 model.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.JournalEntry) (model.JournalEntry, error)); ok {
+		return rf(ctx, entry)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.JournalEntry) model.JournalEntry); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Get(0).(model.JournalEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.JournalEntry) error); ok {
+		r1 = rf(ctx, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JournalEntryRepository_CreateJournalEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateJournalEntry'
+type JournalEntryRepository_CreateJournalEntry_Call struct {
+	*mock.Call
+}
+
+// CreateJournalEntry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry model.JournalEntry
+func (_e *JournalEntryRepository_Expecter) CreateJournalEntry(ctx interface{}, entry interface{}) *JournalEntryRepository_CreateJournalEntry_Call {
+	return &JournalEntryRepository_CreateJournalEntry_Call{Call: _e.mock.On("CreateJournalEntry", ctx, entry)}
+}
+
+func (_c *JournalEntryRepository_CreateJournalEntry_Call) Run(run func(ctx context.Context, entry model.JournalEntry)) *JournalEntryRepository_CreateJournalEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.JournalEntry))
+	})
+	return _c
+}
+
+func (_c *JournalEntryRepository_CreateJournalEntry_Call) Return(_a0 model.JournalEntry, _a1 error) *JournalEntryRepository_CreateJournalEntry_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JournalEntryRepository_CreateJournalEntry_Call) RunAndReturn(run func(context.Context, model.JournalEntry) (model.JournalEntry, error)) *JournalEntryRepository_CreateJournalEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllJournalEntries provides a mock function with given fields: ctx
+func (_m *JournalEntryRepository) GetAllJournalEntries(ctx context.Context) ([]model.JournalEntry, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllJournalEntries")
+	}
+
+	var r0 []model.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.JournalEntry, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.JournalEntry); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.JournalEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JournalEntryRepository_GetAllJournalEntries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllJournalEntries'
+type JournalEntryRepository_GetAllJournalEntries_Call struct {
+	*mock.Call
+}
+
+// GetAllJournalEntries is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *JournalEntryRepository_Expecter) GetAllJournalEntries(ctx interface{}) *JournalEntryRepository_GetAllJournalEntries_Call {
+	return &JournalEntryRepository_GetAllJournalEntries_Call{Call: _e.mock.On("GetAllJournalEntries", ctx)}
+}
+
+func (_c *JournalEntryRepository_GetAllJournalEntries_Call) Run(run func(ctx context.Context)) *JournalEntryRepository_GetAllJournalEntries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *JournalEntryRepository_GetAllJournalEntries_Call) Return(_a0 []model.JournalEntry, _a1 error) *JournalEntryRepository_GetAllJournalEntries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JournalEntryRepository_GetAllJournalEntries_Call) RunAndReturn(run func(context.Context) ([]model.JournalEntry, error)) *JournalEntryRepository_GetAllJournalEntries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJournalEntriesByAccountCode provides a mock function with given fields: ctx, accountCode
+func (_m *JournalEntryRepository) GetJournalEntriesByAccountCode(ctx context.Context, accountCode string) ([]model.JournalEntry, error) {
+	ret := _m.Called(ctx, accountCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJournalEntriesByAccountCode")
+	}
+
+	var r0 []model.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.JournalEntry, error)); ok {
+		return rf(ctx, accountCode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.JournalEntry); ok {
+		r0 = rf(ctx, accountCode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.JournalEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accountCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JournalEntryRepository_GetJournalEntriesByAccountCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJournalEntriesByAccountCode'
+type JournalEntryRepository_GetJournalEntriesByAccountCode_Call struct {
+	*mock.Call
+}
+
+// GetJournalEntriesByAccountCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountCode string
+func (_e *JournalEntryRepository_Expecter) GetJournalEntriesByAccountCode(ctx interface{}, accountCode interface{}) *JournalEntryRepository_GetJournalEntriesByAccountCode_Call {
+	return &JournalEntryRepository_GetJournalEntriesByAccountCode_Call{Call: _e.mock.On("GetJournalEntriesByAccountCode", ctx, accountCode)}
+}
+
+func (_c *JournalEntryRepository_GetJournalEntriesByAccountCode_Call) Run(run func(ctx context.Context, accountCode string)) *JournalEntryRepository_GetJournalEntriesByAccountCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *JournalEntryRepository_GetJournalEntriesByAccountCode_Call) Return(_a0 []model.JournalEntry, _a1 error) *JournalEntryRepository_GetJournalEntriesByAccountCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JournalEntryRepository_GetJournalEntriesByAccountCode_Call) RunAndReturn(run func(context.Context, string) ([]model.JournalEntry, error)) *JournalEntryRepository_GetJournalEntriesByAccountCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetJournalEntryByID provides a mock function with given fields: ctx, id
+func (_m *JournalEntryRepository) GetJournalEntryByID(ctx context.Context, id int64) (model.JournalEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJournalEntryByID")
+	}
+
+	var r0 model.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (model.JournalEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) model.JournalEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.JournalEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// JournalEntryRepository_GetJournalEntryByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJournalEntryByID'
+type JournalEntryRepository_GetJournalEntryByID_Call struct {
+	*mock.Call
+}
+
+// GetJournalEntryByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *JournalEntryRepository_Expecter) GetJournalEntryByID(ctx interface{}, id interface{}) *JournalEntryRepository_GetJournalEntryByID_Call {
+	return &JournalEntryRepository_GetJournalEntryByID_Call{Call: _e.mock.On("GetJournalEntryByID", ctx, id)}
+}
+
+func (_c *JournalEntryRepository_GetJournalEntryByID_Call) Run(run func(ctx context.Context, id int64)) *JournalEntryRepository_GetJournalEntryByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *JournalEntryRepository_GetJournalEntryByID_Call) Return(_a0 model.JournalEntry, _a1 error) *JournalEntryRepository_GetJournalEntryByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *JournalEntryRepository_GetJournalEntryByID_Call) RunAndReturn(run func(context.Context, int64) (model.JournalEntry, error)) *JournalEntryRepository_GetJournalEntryByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewJournalEntryRepository creates a new instance of JournalEntryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewJournalEntryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *JournalEntryRepository {
+	mock := &JournalEntryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,225 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	jwt_manager "ebank/pkg/jwt_manager"
+
+	jwt "github.com/dgrijalva/jwt-go"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeySet is an autogenerated mock type for the KeySet type
+type KeySet struct {
+	mock.Mock
+}
+
+type KeySet_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *KeySet) EXPECT() *KeySet_Expecter {
+	return &KeySet_Expecter{mock: &_m.Mock}
+}
+
+// PublicKeys provides a mock function with given fields:
+func (_m *KeySet) PublicKeys() []jwt_manager.JSONWebKey {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 []jwt_manager.JSONWebKey
+	if rf, ok := ret.Get(0).(func() []jwt_manager.JSONWebKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]jwt_manager.JSONWebKey)
+		}
+	}
+
+	return r0
+}
+
+// KeySet_PublicKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PublicKeys'
+type KeySet_PublicKeys_Call struct {
+	*mock.Call
+}
+
+// PublicKeys is a helper method to define mock.On call
+func (_e *KeySet_Expecter) PublicKeys() *KeySet_PublicKeys_Call {
+	return &KeySet_PublicKeys_Call{Call: _e.mock.On("PublicKeys")}
+}
+
+func (_c *KeySet_PublicKeys_Call) Run(run func()) *KeySet_PublicKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *KeySet_PublicKeys_Call) Return(_a0 []jwt_manager.JSONWebKey) *KeySet_PublicKeys_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *KeySet_PublicKeys_Call) RunAndReturn(run func() []jwt_manager.JSONWebKey) *KeySet_PublicKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SigningKey provides a mock function with given fields:
+func (_m *KeySet) SigningKey() (string, jwt.SigningMethod, interface{}, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SigningKey")
+	}
+
+	var r0 string
+	var r1 jwt.SigningMethod
+	var r2 interface{}
+	var r3 error
+	if rf, ok := ret.Get(0).(func() (string, jwt.SigningMethod, interface{}, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() jwt.SigningMethod); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(jwt.SigningMethod)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func() interface{}); ok {
+		r2 = rf()
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func() error); ok {
+		r3 = rf()
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// KeySet_SigningKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SigningKey'
+type KeySet_SigningKey_Call struct {
+	*mock.Call
+}
+
+// SigningKey is a helper method to define mock.On call
+func (_e *KeySet_Expecter) SigningKey() *KeySet_SigningKey_Call {
+	return &KeySet_SigningKey_Call{Call: _e.mock.On("SigningKey")}
+}
+
+func (_c *KeySet_SigningKey_Call) Run(run func()) *KeySet_SigningKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *KeySet_SigningKey_Call) Return(kid string, method jwt.SigningMethod, key interface{}, err error) *KeySet_SigningKey_Call {
+	_c.Call.Return(kid, method, key, err)
+	return _c
+}
+
+func (_c *KeySet_SigningKey_Call) RunAndReturn(run func() (string, jwt.SigningMethod, interface{}, error)) *KeySet_SigningKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerificationKey provides a mock function with given fields: kid
+func (_m *KeySet) VerificationKey(kid string) (jwt.SigningMethod, interface{}, error) {
+	ret := _m.Called(kid)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerificationKey")
+	}
+
+	var r0 jwt.SigningMethod
+	var r1 interface{}
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (jwt.SigningMethod, interface{}, error)); ok {
+		return rf(kid)
+	}
+	if rf, ok := ret.Get(0).(func(string) jwt.SigningMethod); ok {
+		r0 = rf(kid)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(jwt.SigningMethod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) interface{}); ok {
+		r1 = rf(kid)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(interface{})
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(kid)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// KeySet_VerificationKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerificationKey'
+type KeySet_VerificationKey_Call struct {
+	*mock.Call
+}
+
+// VerificationKey is a helper method to define mock.On call
+//   - kid string
+func (_e *KeySet_Expecter) VerificationKey(kid interface{}) *KeySet_VerificationKey_Call {
+	return &KeySet_VerificationKey_Call{Call: _e.mock.On("VerificationKey", kid)}
+}
+
+func (_c *KeySet_VerificationKey_Call) Run(run func(kid string)) *KeySet_VerificationKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *KeySet_VerificationKey_Call) Return(method jwt.SigningMethod, key interface{}, err error) *KeySet_VerificationKey_Call {
+	_c.Call.Return(method, key, err)
+	return _c
+}
+
+func (_c *KeySet_VerificationKey_Call) RunAndReturn(run func(string) (jwt.SigningMethod, interface{}, error)) *KeySet_VerificationKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewKeySet creates a new instance of KeySet. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeySet(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeySet {
+	mock := &KeySet{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,375 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "ebank/services/ledger/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	money "ebank/pkg/money"
+)
+
+// Ledger is an autogenerated mock type for the Ledger type
+type Ledger struct {
+	mock.Mock
+}
+
+type Ledger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Ledger) EXPECT() *Ledger_Expecter {
+	return &Ledger_Expecter{mock: &_m.Mock}
+}
+
+// GetBalance provides a mock function with given fields: ctx, accountCode, currency
+func (_m *Ledger) GetBalance(ctx context.Context, accountCode string, currency string) (money.Money, error) {
+	ret := _m.Called(ctx, accountCode, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalance")
+	}
+
+	var r0 money.Money
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (money.Money, error)); ok {
+		return rf(ctx, accountCode, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) money.Money); ok {
+		r0 = rf(ctx, accountCode, currency)
+	} else {
+		r0 = ret.Get(0).(money.Money)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accountCode, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ledger_GetBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetBalance'
+type Ledger_GetBalance_Call struct {
+	*mock.Call
+}
+
+// GetBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountCode string
+//   - currency string
+func (_e *Ledger_Expecter) GetBalance(ctx interface{}, accountCode interface{}, currency interface{}) *Ledger_GetBalance_Call {
+	return &Ledger_GetBalance_Call{Call: _e.mock.On("GetBalance", ctx, accountCode, currency)}
+}
+
+func (_c *Ledger_GetBalance_Call) Run(run func(ctx context.Context, accountCode string, currency string)) *Ledger_GetBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Ledger_GetBalance_Call) Return(_a0 money.Money, _a1 error) *Ledger_GetBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Ledger_GetBalance_Call) RunAndReturn(run func(context.Context, string, string) (money.Money, error)) *Ledger_GetBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCustomerBalance provides a mock function with given fields: ctx, accountID, currency
+func (_m *Ledger) GetCustomerBalance(ctx context.Context, accountID int64, currency string) (money.Money, error) {
+	ret := _m.Called(ctx, accountID, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCustomerBalance")
+	}
+
+	var r0 money.Money
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (money.Money, error)); ok {
+		return rf(ctx, accountID, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) money.Money); ok {
+		r0 = rf(ctx, accountID, currency)
+	} else {
+		r0 = ret.Get(0).(money.Money)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, accountID, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ledger_GetCustomerBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCustomerBalance'
+type Ledger_GetCustomerBalance_Call struct {
+	*mock.Call
+}
+
+// GetCustomerBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID int64
+//   - currency string
+func (_e *Ledger_Expecter) GetCustomerBalance(ctx interface{}, accountID interface{}, currency interface{}) *Ledger_GetCustomerBalance_Call {
+	return &Ledger_GetCustomerBalance_Call{Call: _e.mock.On("GetCustomerBalance", ctx, accountID, currency)}
+}
+
+func (_c *Ledger_GetCustomerBalance_Call) Run(run func(ctx context.Context, accountID int64, currency string)) *Ledger_GetCustomerBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Ledger_GetCustomerBalance_Call) Return(_a0 money.Money, _a1 error) *Ledger_GetCustomerBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Ledger_GetCustomerBalance_Call) RunAndReturn(run func(context.Context, int64, string) (money.Money, error)) *Ledger_GetCustomerBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Post provides a mock function with given fields: ctx, entry
+func (_m *Ledger) Post(ctx context.Context, entry model.JournalEntry) (model.JournalEntry, error) {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Post")
+	}
+
+	var r0 model.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.JournalEntry) (model.JournalEntry, error)); ok {
+		return rf(ctx, entry)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.JournalEntry) model.JournalEntry); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Get(0).(model.JournalEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.JournalEntry) error); ok {
+		r1 = rf(ctx, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ledger_Post_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Post'
+type Ledger_Post_Call struct {
+	*mock.Call
+}
+
+// Post is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry model.JournalEntry
+func (_e *Ledger_Expecter) Post(ctx interface{}, entry interface{}) *Ledger_Post_Call {
+	return &Ledger_Post_Call{Call: _e.mock.On("Post", ctx, entry)}
+}
+
+func (_c *Ledger_Post_Call) Run(run func(ctx context.Context, entry model.JournalEntry)) *Ledger_Post_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.JournalEntry))
+	})
+	return _c
+}
+
+func (_c *Ledger_Post_Call) Return(_a0 model.JournalEntry, _a1 error) *Ledger_Post_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Ledger_Post_Call) RunAndReturn(run func(context.Context, model.JournalEntry) (model.JournalEntry, error)) *Ledger_Post_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reconcile provides a mock function with given fields: ctx, balances
+func (_m *Ledger) Reconcile(ctx context.Context, balances map[int64]money.Money) error {
+	ret := _m.Called(ctx, balances)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reconcile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[int64]money.Money) error); ok {
+		r0 = rf(ctx, balances)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Ledger_Reconcile_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reconcile'
+type Ledger_Reconcile_Call struct {
+	*mock.Call
+}
+
+// Reconcile is a helper method to define mock.On call
+//   - ctx context.Context
+//   - balances map[int64]money.Money
+func (_e *Ledger_Expecter) Reconcile(ctx interface{}, balances interface{}) *Ledger_Reconcile_Call {
+	return &Ledger_Reconcile_Call{Call: _e.mock.On("Reconcile", ctx, balances)}
+}
+
+func (_c *Ledger_Reconcile_Call) Run(run func(ctx context.Context, balances map[int64]money.Money)) *Ledger_Reconcile_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(map[int64]money.Money))
+	})
+	return _c
+}
+
+func (_c *Ledger_Reconcile_Call) Return(_a0 error) *Ledger_Reconcile_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Ledger_Reconcile_Call) RunAndReturn(run func(context.Context, map[int64]money.Money) error) *Ledger_Reconcile_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reverse provides a mock function with given fields: ctx, entryID, description
+func (_m *Ledger) Reverse(ctx context.Context, entryID int64, description string) (model.JournalEntry, error) {
+	ret := _m.Called(ctx, entryID, description)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reverse")
+	}
+
+	var r0 model.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (model.JournalEntry, error)); ok {
+		return rf(ctx, entryID, description)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) model.JournalEntry); ok {
+		r0 = rf(ctx, entryID, description)
+	} else {
+		r0 = ret.Get(0).(model.JournalEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, entryID, description)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ledger_Reverse_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reverse'
+type Ledger_Reverse_Call struct {
+	*mock.Call
+}
+
+// Reverse is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entryID int64
+//   - description string
+func (_e *Ledger_Expecter) Reverse(ctx interface{}, entryID interface{}, description interface{}) *Ledger_Reverse_Call {
+	return &Ledger_Reverse_Call{Call: _e.mock.On("Reverse", ctx, entryID, description)}
+}
+
+func (_c *Ledger_Reverse_Call) Run(run func(ctx context.Context, entryID int64, description string)) *Ledger_Reverse_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Ledger_Reverse_Call) Return(_a0 model.JournalEntry, _a1 error) *Ledger_Reverse_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Ledger_Reverse_Call) RunAndReturn(run func(context.Context, int64, string) (model.JournalEntry, error)) *Ledger_Reverse_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TrialBalance provides a mock function with given fields: ctx
+func (_m *Ledger) TrialBalance(ctx context.Context) (map[string]money.Money, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TrialBalance")
+	}
+
+	var r0 map[string]money.Money
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (map[string]money.Money, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]money.Money); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]money.Money)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Ledger_TrialBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TrialBalance'
+type Ledger_TrialBalance_Call struct {
+	*mock.Call
+}
+
+// TrialBalance is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Ledger_Expecter) TrialBalance(ctx interface{}) *Ledger_TrialBalance_Call {
+	return &Ledger_TrialBalance_Call{Call: _e.mock.On("TrialBalance", ctx)}
+}
+
+func (_c *Ledger_TrialBalance_Call) Run(run func(ctx context.Context)) *Ledger_TrialBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Ledger_TrialBalance_Call) Return(_a0 map[string]money.Money, _a1 error) *Ledger_TrialBalance_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Ledger_TrialBalance_Call) RunAndReturn(run func(context.Context) (map[string]money.Money, error)) *Ledger_TrialBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLedger creates a new instance of Ledger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Ledger {
+	mock := &Ledger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,239 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "ebank/services/user/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoginAttemptRepository is an autogenerated mock type for the LoginAttemptRepository type
+type LoginAttemptRepository struct {
+	mock.Mock
+}
+
+type LoginAttemptRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LoginAttemptRepository) EXPECT() *LoginAttemptRepository_Expecter {
+	return &LoginAttemptRepository_Expecter{mock: &_m.Mock}
+}
+
+// DeleteLoginAttempt provides a mock function with given fields: ctx, key
+func (_m *LoginAttemptRepository) DeleteLoginAttempt(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LoginAttemptRepository_DeleteLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteLoginAttempt'
+type LoginAttemptRepository_DeleteLoginAttempt_Call struct {
+	*mock.Call
+}
+
+// DeleteLoginAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *LoginAttemptRepository_Expecter) DeleteLoginAttempt(ctx interface{}, key interface{}) *LoginAttemptRepository_DeleteLoginAttempt_Call {
+	return &LoginAttemptRepository_DeleteLoginAttempt_Call{Call: _e.mock.On("DeleteLoginAttempt", ctx, key)}
+}
+
+func (_c *LoginAttemptRepository_DeleteLoginAttempt_Call) Run(run func(ctx context.Context, key string)) *LoginAttemptRepository_DeleteLoginAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptRepository_DeleteLoginAttempt_Call) Return(_a0 error) *LoginAttemptRepository_DeleteLoginAttempt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LoginAttemptRepository_DeleteLoginAttempt_Call) RunAndReturn(run func(context.Context, string) error) *LoginAttemptRepository_DeleteLoginAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteLoginAttemptsBefore provides a mock function with given fields: ctx, before
+func (_m *LoginAttemptRepository) DeleteLoginAttemptsBefore(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLoginAttemptsBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LoginAttemptRepository_DeleteLoginAttemptsBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteLoginAttemptsBefore'
+type LoginAttemptRepository_DeleteLoginAttemptsBefore_Call struct {
+	*mock.Call
+}
+
+// DeleteLoginAttemptsBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *LoginAttemptRepository_Expecter) DeleteLoginAttemptsBefore(ctx interface{}, before interface{}) *LoginAttemptRepository_DeleteLoginAttemptsBefore_Call {
+	return &LoginAttemptRepository_DeleteLoginAttemptsBefore_Call{Call: _e.mock.On("DeleteLoginAttemptsBefore", ctx, before)}
+}
+
+func (_c *LoginAttemptRepository_DeleteLoginAttemptsBefore_Call) Run(run func(ctx context.Context, before time.Time)) *LoginAttemptRepository_DeleteLoginAttemptsBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptRepository_DeleteLoginAttemptsBefore_Call) Return(_a0 error) *LoginAttemptRepository_DeleteLoginAttemptsBefore_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LoginAttemptRepository_DeleteLoginAttemptsBefore_Call) RunAndReturn(run func(context.Context, time.Time) error) *LoginAttemptRepository_DeleteLoginAttemptsBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginAttempt provides a mock function with given fields: ctx, key
+func (_m *LoginAttemptRepository) GetLoginAttempt(ctx context.Context, key string) (*model.LoginAttempt, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginAttempt")
+	}
+
+	var r0 *model.LoginAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.LoginAttempt, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.LoginAttempt); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginAttemptRepository_GetLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginAttempt'
+type LoginAttemptRepository_GetLoginAttempt_Call struct {
+	*mock.Call
+}
+
+// GetLoginAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *LoginAttemptRepository_Expecter) GetLoginAttempt(ctx interface{}, key interface{}) *LoginAttemptRepository_GetLoginAttempt_Call {
+	return &LoginAttemptRepository_GetLoginAttempt_Call{Call: _e.mock.On("GetLoginAttempt", ctx, key)}
+}
+
+func (_c *LoginAttemptRepository_GetLoginAttempt_Call) Run(run func(ctx context.Context, key string)) *LoginAttemptRepository_GetLoginAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptRepository_GetLoginAttempt_Call) Return(_a0 *model.LoginAttempt, _a1 error) *LoginAttemptRepository_GetLoginAttempt_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LoginAttemptRepository_GetLoginAttempt_Call) RunAndReturn(run func(context.Context, string) (*model.LoginAttempt, error)) *LoginAttemptRepository_GetLoginAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveLoginAttempt provides a mock function with given fields: ctx, attempt
+func (_m *LoginAttemptRepository) SaveLoginAttempt(ctx context.Context, attempt model.LoginAttempt) error {
+	ret := _m.Called(ctx, attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveLoginAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.LoginAttempt) error); ok {
+		r0 = rf(ctx, attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LoginAttemptRepository_SaveLoginAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveLoginAttempt'
+type LoginAttemptRepository_SaveLoginAttempt_Call struct {
+	*mock.Call
+}
+
+// SaveLoginAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - attempt model.LoginAttempt
+func (_e *LoginAttemptRepository_Expecter) SaveLoginAttempt(ctx interface{}, attempt interface{}) *LoginAttemptRepository_SaveLoginAttempt_Call {
+	return &LoginAttemptRepository_SaveLoginAttempt_Call{Call: _e.mock.On("SaveLoginAttempt", ctx, attempt)}
+}
+
+func (_c *LoginAttemptRepository_SaveLoginAttempt_Call) Run(run func(ctx context.Context, attempt model.LoginAttempt)) *LoginAttemptRepository_SaveLoginAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.LoginAttempt))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptRepository_SaveLoginAttempt_Call) Return(_a0 error) *LoginAttemptRepository_SaveLoginAttempt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LoginAttemptRepository_SaveLoginAttempt_Call) RunAndReturn(run func(context.Context, model.LoginAttempt) error) *LoginAttemptRepository_SaveLoginAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLoginAttemptRepository creates a new instance of LoginAttemptRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginAttemptRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginAttemptRepository {
+	mock := &LoginAttemptRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,300 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "ebank/services/user/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// SessionRepository is an autogenerated mock type for the SessionRepository type
+type SessionRepository struct {
+	mock.Mock
+}
+
+type SessionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SessionRepository) EXPECT() *SessionRepository_Expecter {
+	return &SessionRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateSession provides a mock function with given fields: ctx, session
+func (_m *SessionRepository) CreateSession(ctx context.Context, session model.Session) error {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Session) error); ok {
+		r0 = rf(ctx, session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionRepository_CreateSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSession'
+type SessionRepository_CreateSession_Call struct {
+	*mock.Call
+}
+
+// CreateSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session model.Session
+func (_e *SessionRepository_Expecter) CreateSession(ctx interface{}, session interface{}) *SessionRepository_CreateSession_Call {
+	return &SessionRepository_CreateSession_Call{Call: _e.mock.On("CreateSession", ctx, session)}
+}
+
+func (_c *SessionRepository_CreateSession_Call) Run(run func(ctx context.Context, session model.Session)) *SessionRepository_CreateSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.Session))
+	})
+	return _c
+}
+
+func (_c *SessionRepository_CreateSession_Call) Return(_a0 error) *SessionRepository_CreateSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionRepository_CreateSession_Call) RunAndReturn(run func(context.Context, model.Session) error) *SessionRepository_CreateSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSession provides a mock function with given fields: ctx, id
+func (_m *SessionRepository) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSession")
+	}
+
+	var r0 *model.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Session, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Session); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionRepository_GetSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSession'
+type SessionRepository_GetSession_Call struct {
+	*mock.Call
+}
+
+// GetSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *SessionRepository_Expecter) GetSession(ctx interface{}, id interface{}) *SessionRepository_GetSession_Call {
+	return &SessionRepository_GetSession_Call{Call: _e.mock.On("GetSession", ctx, id)}
+}
+
+func (_c *SessionRepository_GetSession_Call) Run(run func(ctx context.Context, id string)) *SessionRepository_GetSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *SessionRepository_GetSession_Call) Return(_a0 *model.Session, _a1 error) *SessionRepository_GetSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionRepository_GetSession_Call) RunAndReturn(run func(context.Context, string) (*model.Session, error)) *SessionRepository_GetSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function with given fields: ctx, id, revokedAt
+func (_m *SessionRepository) RevokeSession(ctx context.Context, id string, revokedAt time.Time) error {
+	ret := _m.Called(ctx, id, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionRepository_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type SessionRepository_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - revokedAt time.Time
+func (_e *SessionRepository_Expecter) RevokeSession(ctx interface{}, id interface{}, revokedAt interface{}) *SessionRepository_RevokeSession_Call {
+	return &SessionRepository_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, id, revokedAt)}
+}
+
+func (_c *SessionRepository_RevokeSession_Call) Run(run func(ctx context.Context, id string, revokedAt time.Time)) *SessionRepository_RevokeSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionRepository_RevokeSession_Call) Return(_a0 error) *SessionRepository_RevokeSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionRepository_RevokeSession_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *SessionRepository_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserSessions provides a mock function with given fields: ctx, userID, revokedAt
+func (_m *SessionRepository) RevokeUserSessions(ctx context.Context, userID int64, revokedAt time.Time) error {
+	ret := _m.Called(ctx, userID, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, userID, revokedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SessionRepository_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type SessionRepository_RevokeUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - revokedAt time.Time
+func (_e *SessionRepository_Expecter) RevokeUserSessions(ctx interface{}, userID interface{}, revokedAt interface{}) *SessionRepository_RevokeUserSessions_Call {
+	return &SessionRepository_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, userID, revokedAt)}
+}
+
+func (_c *SessionRepository_RevokeUserSessions_Call) Run(run func(ctx context.Context, userID int64, revokedAt time.Time)) *SessionRepository_RevokeUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *SessionRepository_RevokeUserSessions_Call) Return(_a0 error) *SessionRepository_RevokeUserSessions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *SessionRepository_RevokeUserSessions_Call) RunAndReturn(run func(context.Context, int64, time.Time) error) *SessionRepository_RevokeUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, id, oldTokenID, newTokenID
+func (_m *SessionRepository) RotateRefreshToken(ctx context.Context, id string, oldTokenID string, newTokenID string) (bool, error) {
+	ret := _m.Called(ctx, id, oldTokenID, newTokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, id, oldTokenID, newTokenID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, id, oldTokenID, newTokenID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, id, oldTokenID, newTokenID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionRepository_RotateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RotateRefreshToken'
+type SessionRepository_RotateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// RotateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - oldTokenID string
+//   - newTokenID string
+func (_e *SessionRepository_Expecter) RotateRefreshToken(ctx interface{}, id interface{}, oldTokenID interface{}, newTokenID interface{}) *SessionRepository_RotateRefreshToken_Call {
+	return &SessionRepository_RotateRefreshToken_Call{Call: _e.mock.On("RotateRefreshToken", ctx, id, oldTokenID, newTokenID)}
+}
+
+func (_c *SessionRepository_RotateRefreshToken_Call) Run(run func(ctx context.Context, id string, oldTokenID string, newTokenID string)) *SessionRepository_RotateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *SessionRepository_RotateRefreshToken_Call) Return(_a0 bool, _a1 error) *SessionRepository_RotateRefreshToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionRepository_RotateRefreshToken_Call) RunAndReturn(run func(context.Context, string, string, string) (bool, error)) *SessionRepository_RotateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSessionRepository creates a new instance of SessionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionRepository {
+	mock := &SessionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
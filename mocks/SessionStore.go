@@ -0,0 +1,97 @@
+// Code generated by mockery v2.42.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "ebank/services/user/model"
+)
+
+// SessionStore is an autogenerated mock type for the SessionStore type
+type SessionStore struct {
+	mock.Mock
+}
+
+type SessionStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *SessionStore) EXPECT() *SessionStore_Expecter {
+	return &SessionStore_Expecter{mock: &_m.Mock}
+}
+
+// GetSession provides a mock function with given fields: ctx, id
+func (_m *SessionStore) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSession")
+	}
+
+	var r0 *model.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Session, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Session); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SessionStore_GetSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSession'
+type SessionStore_GetSession_Call struct {
+	*mock.Call
+}
+
+// GetSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *SessionStore_Expecter) GetSession(ctx interface{}, id interface{}) *SessionStore_GetSession_Call {
+	return &SessionStore_GetSession_Call{Call: _e.mock.On("GetSession", ctx, id)}
+}
+
+func (_c *SessionStore_GetSession_Call) Run(run func(ctx context.Context, id string)) *SessionStore_GetSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *SessionStore_GetSession_Call) Return(_a0 *model.Session, _a1 error) *SessionStore_GetSession_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SessionStore_GetSession_Call) RunAndReturn(run func(context.Context, string) (*model.Session, error)) *SessionStore_GetSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewSessionStore creates a new instance of SessionStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionStore {
+	mock := &SessionStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
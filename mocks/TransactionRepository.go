@@ -4,7 +4,7 @@ package mocks
 
 import (
 	context "context"
-	model "ebank/internal/model"
+	model "ebank/services/transaction/model"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -300,6 +300,65 @@ func (_c *TransactionRepository_GetTransactionsByAccountID_Call) RunAndReturn(ru
 	return _c
 }
 
+// ListTransactions provides a mock function with given fields: ctx, query
+func (_m *TransactionRepository) ListTransactions(ctx context.Context, query model.TransactionQuery) ([]model.Transaction, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactions")
+	}
+
+	var r0 []model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TransactionQuery) ([]model.Transaction, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TransactionQuery) []model.Transaction); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TransactionQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionRepository_ListTransactions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTransactions'
+type TransactionRepository_ListTransactions_Call struct {
+	*mock.Call
+}
+
+// ListTransactions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query model.TransactionQuery
+func (_e *TransactionRepository_Expecter) ListTransactions(ctx interface{}, query interface{}) *TransactionRepository_ListTransactions_Call {
+	return &TransactionRepository_ListTransactions_Call{Call: _e.mock.On("ListTransactions", ctx, query)}
+}
+
+func (_c *TransactionRepository_ListTransactions_Call) Run(run func(ctx context.Context, query model.TransactionQuery)) *TransactionRepository_ListTransactions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(model.TransactionQuery))
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_ListTransactions_Call) Return(_a0 []model.Transaction, _a1 error) *TransactionRepository_ListTransactions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionRepository_ListTransactions_Call) RunAndReturn(run func(context.Context, model.TransactionQuery) ([]model.Transaction, error)) *TransactionRepository_ListTransactions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // UpdateTransaction provides a mock function with given fields: ctx, transaction
 func (_m *TransactionRepository) UpdateTransaction(ctx context.Context, transaction model.Transaction) error {
 	ret := _m.Called(ctx, transaction)
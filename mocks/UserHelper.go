@@ -4,7 +4,7 @@ package mocks
 
 import (
 	context "context"
-	model "ebank/internal/model"
+	model "ebank/services/user/model"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -79,6 +79,54 @@ func (_c *UserHelper_ValidateUser_Call) RunAndReturn(run func(context.Context, i
 	return _c
 }
 
+// VerifyOTP provides a mock function with given fields: ctx, userID, code
+func (_m *UserHelper) VerifyOTP(ctx context.Context, userID int64, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserHelper_VerifyOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyOTP'
+type UserHelper_VerifyOTP_Call struct {
+	*mock.Call
+}
+
+// VerifyOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID int64
+//   - code string
+func (_e *UserHelper_Expecter) VerifyOTP(ctx interface{}, userID interface{}, code interface{}) *UserHelper_VerifyOTP_Call {
+	return &UserHelper_VerifyOTP_Call{Call: _e.mock.On("VerifyOTP", ctx, userID, code)}
+}
+
+func (_c *UserHelper_VerifyOTP_Call) Run(run func(ctx context.Context, userID int64, code string)) *UserHelper_VerifyOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserHelper_VerifyOTP_Call) Return(_a0 error) *UserHelper_VerifyOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserHelper_VerifyOTP_Call) RunAndReturn(run func(context.Context, int64, string) error) *UserHelper_VerifyOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewUserHelper creates a new instance of UserHelper. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserHelper(t interface {
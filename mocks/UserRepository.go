@@ -4,7 +4,7 @@ package mocks
 
 import (
 	context "context"
-	model "ebank/internal/model"
+	model "ebank/services/user/model"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -0,0 +1,108 @@
+package accountnumber
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 계좌번호는 "지점(3)-상품(2)-일련번호(7)-검증(1)" 형식입니다. 예: 001-10-0000001-5
+// 검증 숫자는 앞의 12자리에 대한 Luhn 검증 숫자로, 한 자리를 잘못 쓰거나 이웃한 두 자리를 바꿔 쓴 경우 대부분을 잡아냅니다.
+const (
+	BranchDigits   = 3
+	ProductDigits  = 2
+	SequenceDigits = 7
+	Length         = BranchDigits + ProductDigits + SequenceDigits + 1
+
+	// MaxSequence 지점과 상품 조합마다 발급할 수 있는 마지막 일련번호입니다.
+	MaxSequence = 9_999_999
+)
+
+// Prefix 같은 일련번호를 공유하는 지점과 상품 코드입니다.
+func Prefix(branch string, product string) (string, error) {
+	if !isDigits(branch, BranchDigits) {
+		return "", fmt.Errorf("branch code must be %d digits, got %q", BranchDigits, branch)
+	}
+	if !isDigits(product, ProductDigits) {
+		return "", fmt.Errorf("product code must be %d digits, got %q", ProductDigits, product)
+	}
+
+	return branch + product, nil
+}
+
+// Generate prefix의 sequence번째 계좌번호를 만듭니다.
+func Generate(prefix string, sequence int64) (string, error) {
+	if !isDigits(prefix, BranchDigits+ProductDigits) {
+		return "", fmt.Errorf("invalid account number prefix %q", prefix)
+	}
+	if sequence < 1 || sequence > MaxSequence {
+		return "", fmt.Errorf("account number sequence %d out of range", sequence)
+	}
+
+	digits := fmt.Sprintf("%s%0*d", prefix, SequenceDigits, sequence)
+	return format(digits + string(checkDigit(digits))), nil
+}
+
+// Normalize 하이픈과 공백을 무시하고 계좌번호를 검증한 뒤 표준 형식으로 반환합니다.
+func Normalize(number string) (string, error) {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(number)
+	if !isDigits(digits, Length) {
+		return "", fmt.Errorf("account number must be %d digits", Length)
+	}
+	if checkDigit(digits[:Length-1]) != digits[Length-1] {
+		return "", fmt.Errorf("account number check digit does not match")
+	}
+
+	return format(digits), nil
+}
+
+// Parse 표준 형식의 계좌번호에서 일련번호를 공유하는 prefix와 일련번호를 꺼냅니다.
+func Parse(number string) (prefix string, sequence int64, ok bool) {
+	normalized, err := Normalize(number)
+	if err != nil || normalized != number {
+		return "", 0, false
+	}
+
+	digits := strings.ReplaceAll(normalized, "-", "")
+	prefix = digits[:BranchDigits+ProductDigits]
+	for _, c := range digits[BranchDigits+ProductDigits : Length-1] {
+		sequence = sequence*10 + int64(c-'0')
+	}
+
+	return prefix, sequence, true
+}
+
+// checkDigit Luhn 검증 숫자입니다. 오른쪽 끝 숫자부터 하나 걸러 두 배로 더합니다.
+func checkDigit(digits string) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func format(digits string) string {
+	branch := digits[:BranchDigits]
+	product := digits[BranchDigits : BranchDigits+ProductDigits]
+	sequence := digits[BranchDigits+ProductDigits : Length-1]
+	return branch + "-" + product + "-" + sequence + "-" + digits[Length-1:]
+}
+
+func isDigits(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package accountnumber
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		sequence int64
+		want     string
+	}{
+		{prefix: "00110", sequence: 1, want: "001-10-0000001-5"},
+		{prefix: "00110", sequence: 1234567, want: "001-10-1234567-1"},
+	}
+	for _, tt := range tests {
+		got, err := Generate(tt.prefix, tt.sequence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Generate(%s, %d) = %s, want %s", tt.prefix, tt.sequence, got, tt.want)
+		}
+	}
+
+	if _, err := Generate("00110", MaxSequence+1); err == nil {
+		t.Error("Generate() accepted a sequence out of range")
+	}
+	if _, err := Prefix("1", "10"); err == nil {
+		t.Error("Prefix() accepted a short branch code")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got, err := Normalize(" 00110 0000001 5")
+	if err != nil || got != "001-10-0000001-5" {
+		t.Errorf("Normalize() = %q, %v, want 001-10-0000001-5", got, err)
+	}
+
+	for _, number := range []string{"", "001-10-0000001", "001-10-000000a-5", "001-10-0000001-55"} {
+		if _, err := Normalize(number); err == nil {
+			t.Errorf("Normalize(%q) error = nil", number)
+		}
+	}
+}
+
+// 한 자리를 잘못 쓰면 항상, 이웃한 두 자리를 바꿔 쓰면 09와 90을 제외하고 잡아냅니다.
+func TestNormalize_Typos(t *testing.T) {
+	number, err := Generate("00110", 4718290)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digits := strings.ReplaceAll(number, "-", "")
+
+	for i := 0; i < len(digits); i++ {
+		for d := byte('0'); d <= '9'; d++ {
+			if d == digits[i] {
+				continue
+			}
+			typo := digits[:i] + string(d) + digits[i+1:]
+			if _, err := Normalize(typo); err == nil {
+				t.Errorf("Normalize(%s) accepted a single digit typo of %s", typo, digits)
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(digits); i++ {
+		a, b := digits[i], digits[i+1]
+		if a == b || (a == '0' && b == '9') || (a == '9' && b == '0') {
+			continue
+		}
+		swapped := digits[:i] + string(b) + string(a) + digits[i+2:]
+		if _, err := Normalize(swapped); err == nil {
+			t.Errorf("Normalize(%s) accepted a transposition of %s", swapped, digits)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	prefix, sequence, ok := Parse("001-10-1234567-1")
+	if !ok || prefix != "00110" || sequence != 1234567 {
+		t.Errorf("Parse() = %s, %d, %v", prefix, sequence, ok)
+	}
+
+	// 예전에 자유롭게 입력한 계좌번호는 일련번호로 보지 않습니다.
+	if _, _, ok := Parse("1234567890"); ok {
+		t.Error("Parse() accepted a free text account number")
+	}
+}
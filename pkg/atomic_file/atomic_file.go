@@ -0,0 +1,86 @@
+package atomic_file
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+const tempSuffix = ".tmp"
+
+// WriteFile 임시 파일에 쓰고 fsync한 뒤 rename으로 교체합니다.
+// 쓰는 도중 프로세스가 죽어도 기존 파일은 온전히 남습니다.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tempPath := path + tempSuffix
+
+	f, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tempPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// ReadFile 이전 WriteFile이 중단되어 남은 임시 파일을 정리한 뒤 파일을 읽습니다.
+// 원본이 있으면 원본이 마지막으로 완료된 쓰기이므로 임시 파일을 버리고,
+// 원본이 없으면 온전한 JSON인 임시 파일만 원본으로 복구합니다.
+func ReadFile(path string) ([]byte, error) {
+	if err := recoverTempFile(path); err != nil {
+		return nil, err
+	}
+
+	return os.ReadFile(path)
+}
+
+func recoverTempFile(path string) error {
+	tempPath := path + tempSuffix
+
+	tempData, err := os.ReadFile(tempPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil || !json.Valid(tempData) {
+		return os.Remove(tempPath)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
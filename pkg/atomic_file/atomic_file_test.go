@@ -0,0 +1,75 @@
+package atomic_file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "account.json")
+
+	if err := WriteFile(path, []byte(`[{"ID":1}]`), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := WriteFile(path, []byte(`[{"ID":2}]`), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, err := ReadFile(path)
+	if err != nil || string(data) != `[{"ID":2}]` {
+		t.Fatalf("ReadFile() = %s, %v", data, err)
+	}
+	if _, err := os.Stat(path + tempSuffix); !os.IsNotExist(err) {
+		t.Errorf("temp file should not remain after WriteFile, stat error = %v", err)
+	}
+}
+
+func TestReadFile_RecoverTempFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string // 비어 있으면 원본 파일 없음
+		temp     string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "원본이 있으면 중단된 임시 파일을 버림",
+			original: `[{"ID":1}]`,
+			temp:     `[{"ID":1},{"I`,
+			want:     `[{"ID":1}]`,
+		},
+		{
+			name: "원본이 없고 임시 파일이 온전하면 복구",
+			temp: `[{"ID":1}]`,
+			want: `[{"ID":1}]`,
+		},
+		{
+			name:    "원본이 없고 임시 파일이 잘려 있으면 버림",
+			temp:    `[{"ID":1},{"I`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "account.json")
+			if tt.original != "" {
+				os.WriteFile(path, []byte(tt.original), 0644)
+			}
+			os.WriteFile(path+tempSuffix, []byte(tt.temp), 0644)
+
+			data, err := ReadFile(path)
+			if tt.wantErr {
+				if !os.IsNotExist(err) {
+					t.Fatalf("ReadFile() error = %v, want not exist", err)
+				}
+			} else if err != nil || string(data) != tt.want {
+				t.Fatalf("ReadFile() = %s, %v, want %s", data, err, tt.want)
+			}
+
+			if _, err := os.Stat(path + tempSuffix); !os.IsNotExist(err) {
+				t.Errorf("temp file should be cleaned up, stat error = %v", err)
+			}
+		})
+	}
+}
@@ -2,55 +2,180 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"strings"
 	"time"
+
+	"ebank/pkg/accountnumber"
+	"ebank/pkg/money"
 )
 
 type Config struct {
-	DB     DBConfig
-	Jwt    JwtConfig
-	Server ServerConfig
+	DB          DBConfig
+	Jwt         JwtConfig
+	Server      ServerConfig
+	Auth        AuthConfig
+	Account     AccountConfig
+	Transaction TransactionConfig
 }
 
+const (
+	DBDriverFile   = "file"
+	DBDriverSQLite = "sqlite"
+)
+
 type DBConfig struct {
-	UserTablePath        string
-	AccountTablePath     string
-	TransactionTablePath string
+	Driver                string // DBDriverFile 또는 DBDriverSQLite
+	SQLitePath            string
+	UserTablePath         string
+	SessionTablePath      string
+	LoginAttemptTablePath string
+	AccountTablePath      string
+	TransactionTablePath  string
+	IdempotencyTablePath  string
+	LedgerTablePath       string
+	TxLogPath             string // 파일 저장소에서 계좌, 거래, 원장에 함께 반영할 변경을 먼저 기록하는 로그
 }
 
+const (
+	JwtAlgorithmHS256 = "HS256"
+	JwtAlgorithmRS256 = "RS256"
+	JwtAlgorithmES256 = "ES256"
+)
+
 type JwtConfig struct {
-	SecretKey string
-	Duration  time.Duration
+	Algorithm       string        // JwtAlgorithmRS256, JwtAlgorithmES256 또는 JwtAlgorithmHS256
+	SecretKey       string        // HS256 전용
+	KeyDir          string        // RS256/ES256 키 링 디렉토리
+	KeyRotation     time.Duration // 서명 키 교체 주기, 0이면 교체하지 않음
+	JWKSURL         string        // 비어 있지 않으면 개인 키 없이 이 JWKS로 검증 (cmd/account, cmd/transaction)
+	Duration        time.Duration // 액세스 토큰
+	RefreshDuration time.Duration // 리프레시 토큰, 로그인 세션
+}
+
+type AuthConfig struct {
+	AdminPhoneNumber      string        // 비어 있지 않으면 시작 시 이 사용자에게 admin 역할 부여
+	MaxLoginFailures      int           // 전화번호별 연속 실패 허용 횟수, 넘으면 LockoutDuration 동안 잠금
+	MaxLoginFailuresPerIP int           // 접속 IP별 연속 실패 허용 횟수
+	LoginBackoff          time.Duration // 실패할 때마다 두 배로 늘어나는 전화번호별 재시도 대기 시간의 시작값
+	LockoutDuration       time.Duration
+	StepUpThresholds      map[string]money.Money // 통화별로 이 금액을 넘는 출금과 이체는 OTP 코드가 필요
+}
+
+type AccountConfig struct {
+	BranchCode       string        // 발급하는 계좌번호의 지점 코드 (숫자 3자리)
+	MaturityInterval time.Duration // 만기가 된 정기예금을 처리하는 간격, 0이면 처리하지 않음
+}
+
+type TransactionConfig struct {
+	IdempotencyKeyTTL time.Duration // 멱등성 키를 보관하는 기간, 0이면 지우지 않음
 }
 
 type ServerConfig struct {
-	Port string
+	Port     string // gRPC
+	HTTPPort string // grpc-gateway REST, OpenAPI
+}
+
+// Defaults 실행 파일마다 다른 플래그 기본값입니다.
+type Defaults struct {
+	DBDriver string
+	Port     string // gRPC
+	HTTPPort string
 }
 
-func New() Config {
+// 실행 파일별 기본값. 나누어 실행하는 서비스는 프로세스끼리 공유할 수 있는 SQLite를 쓰고,
+// 한 호스트에서 함께 띄울 수 있도록 서로 다른 포트를 씁니다.
+var (
+	EbankDefaults       = Defaults{DBDriver: DBDriverFile, Port: ":50051", HTTPPort: ":8081"}
+	UserDefaults        = Defaults{DBDriver: DBDriverSQLite, Port: ":50051", HTTPPort: ":8081"}
+	AccountDefaults     = Defaults{DBDriver: DBDriverSQLite, Port: ":50052", HTTPPort: ":8082"}
+	TransactionDefaults = Defaults{DBDriver: DBDriverSQLite, Port: ":50053", HTTPPort: ":8083"}
+)
+
+func New(defaults Defaults) Config {
+	dbDriverPtr := flag.String("db_driver", defaults.DBDriver, "storage driver (file or sqlite)")
+	sqlitePathPtr := flag.String("sqlite_path", "data/ebank.db", "sqlite_path")
 	userFilePathPtr := flag.String("user_file_path", "data/user.json", "user_file_path")
+	sessionFilePathPtr := flag.String("session_file_path", "data/session.json", "session_file_path")
+	loginAttemptFilePathPtr := flag.String("login_attempt_file_path", "data/login_attempt.json", "login_attempt_file_path")
 	accountFilePathPtr := flag.String("account_file_path", "data/account.json", "account_file_path")
 	transactionFilePathPtr := flag.String("transaction_file_path", "data/transaction.json", "transaction_file_path")
+	idempotencyFilePathPtr := flag.String("idempotency_file_path", "data/idempotency.json", "idempotency_file_path")
+	ledgerFilePathPtr := flag.String("ledger_file_path", "data/ledger.json", "ledger_file_path")
+	txLogFilePathPtr := flag.String("tx_log_file_path", "data/tx.json", "tx_log_file_path")
 
-	secretPtr := flag.String("secret", "happy_coding", "secret key")
+	jwtAlgorithmPtr := flag.String("jwt_algorithm", JwtAlgorithmRS256, "jwt signing algorithm (RS256, ES256 or HS256)")
+	secretPtr := flag.String("secret", "happy_coding", "secret key for HS256")
+	jwtKeyDirPtr := flag.String("jwt_key_dir", "data/keys", "directory of RS256/ES256 signing keys")
+	jwtKeyRotationPtr := flag.Duration("jwt_key_rotation", 30*24*time.Hour, "signing key rotation interval (0 disables rotation)")
+	jwksURLPtr := flag.String("jwks_url", "", "JWKS URL of the token issuer, e.g. http://localhost:8081/.well-known/jwks.json")
 	durationPtr := flag.Duration("duration", 15*time.Minute, "token duration")
+	refreshDurationPtr := flag.Duration("refresh_duration", 14*24*time.Hour, "refresh token duration")
+
+	portPtr := flag.String("port", defaults.Port, "port number")
+	httpPortPtr := flag.String("http_port", defaults.HTTPPort, "http port number")
+
+	adminPhoneNumberPtr := flag.String("admin_phone_number", "", "phone number of the user promoted to admin at startup")
+	maxLoginFailuresPtr := flag.Int("max_login_failures", 5, "failed logins per phone number before lockout")
+	maxLoginFailuresPerIPPtr := flag.Int("max_login_failures_per_ip", 20, "failed logins per client IP before lockout")
+	loginBackoffPtr := flag.Duration("login_backoff", time.Second, "initial delay after a failed login, doubled on each failure")
+	lockoutDurationPtr := flag.Duration("lockout_duration", 15*time.Minute, "login lockout duration")
+	stepUpThresholdPtr := flag.String("step_up_threshold", "KRW=1000000,USD=1000,EUR=1000,JPY=100000,CNY=5000",
+		"withdrawals and transfers above these amounts need an OTP code, e.g. KRW=1000000,USD=1000")
 
-	portPtr := flag.String("port", ":50051", "port number")
+	branchCodePtr := flag.String("branch_code", "001", "3 digit branch code of issued account numbers")
+	maturityIntervalPtr := flag.Duration("maturity_interval", time.Hour, "interval of moving matured fixed-term deposits to their linked accounts (0 disables)")
+
+	idempotencyKeyTTLPtr := flag.Duration("idempotency_key_ttl", 7*24*time.Hour, "how long idempotency keys and their responses are kept (0 keeps them forever)")
 
 	flag.Parse()
 
+	stepUpThresholds, err := parseThresholds(*stepUpThresholdPtr)
+	if err != nil {
+		log.Fatalf("Invalid step_up_threshold: %v", err)
+	}
+
 	config := Config{
 		DB: DBConfig{
-			UserTablePath:        *userFilePathPtr,
-			AccountTablePath:     *accountFilePathPtr,
-			TransactionTablePath: *transactionFilePathPtr,
+			Driver:                *dbDriverPtr,
+			SQLitePath:            *sqlitePathPtr,
+			UserTablePath:         *userFilePathPtr,
+			SessionTablePath:      *sessionFilePathPtr,
+			LoginAttemptTablePath: *loginAttemptFilePathPtr,
+			AccountTablePath:      *accountFilePathPtr,
+			TransactionTablePath:  *transactionFilePathPtr,
+			IdempotencyTablePath:  *idempotencyFilePathPtr,
+			LedgerTablePath:       *ledgerFilePathPtr,
+			TxLogPath:             *txLogFilePathPtr,
 		},
 		Jwt: JwtConfig{
-			SecretKey: *secretPtr,
-			Duration:  *durationPtr,
+			Algorithm:       *jwtAlgorithmPtr,
+			SecretKey:       *secretPtr,
+			KeyDir:          *jwtKeyDirPtr,
+			KeyRotation:     *jwtKeyRotationPtr,
+			JWKSURL:         *jwksURLPtr,
+			Duration:        *durationPtr,
+			RefreshDuration: *refreshDurationPtr,
 		},
 		Server: ServerConfig{
-			Port: *portPtr,
+			Port:     *portPtr,
+			HTTPPort: *httpPortPtr,
+		},
+		Auth: AuthConfig{
+			AdminPhoneNumber:      *adminPhoneNumberPtr,
+			MaxLoginFailures:      *maxLoginFailuresPtr,
+			MaxLoginFailuresPerIP: *maxLoginFailuresPerIPPtr,
+			LoginBackoff:          *loginBackoffPtr,
+			LockoutDuration:       *lockoutDurationPtr,
+			StepUpThresholds:      stepUpThresholds,
+		},
+		Account: AccountConfig{
+			BranchCode:       *branchCodePtr,
+			MaturityInterval: *maturityIntervalPtr,
+		},
+		Transaction: TransactionConfig{
+			IdempotencyKeyTTL: *idempotencyKeyTTLPtr,
 		},
 	}
 
@@ -59,17 +184,72 @@ func New() Config {
 	return config
 }
 
+// parseThresholds "KRW=1000000,USD=1000" 형태의 통화별 금액을 해석합니다. 금액은 주 화폐 단위입니다.
+func parseThresholds(s string) (map[string]money.Money, error) {
+	thresholds := make(map[string]money.Money)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		currency, amount, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected CURRENCY=AMOUNT, got %q", item)
+		}
+		threshold, err := money.Parse(strings.TrimSpace(amount), strings.ToUpper(strings.TrimSpace(currency)))
+		if err != nil {
+			return nil, err
+		}
+		thresholds[threshold.Currency] = threshold
+	}
+
+	return thresholds, nil
+}
+
+// RequireSharedDB 서비스를 나누어 실행하는 실행 파일에서 호출합니다. 파일 저장소는 프로세스마다 시작할 때 읽은 복사본을 쓰므로
+// 다른 프로세스가 만든 사용자, 세션 폐기, 계좌를 보지 못하고 오래된 내용으로 덮어쓸 수 있어 SQLite만 허용합니다.
+func (r Config) RequireSharedDB() {
+	if r.DB.Driver != DBDriverSQLite {
+		log.Fatalf("db driver %q cannot be shared between processes, run the split services with -db_driver=%s or use cmd/ebank", r.DB.Driver, DBDriverSQLite)
+	}
+}
+
 func (r Config) Validate() {
-	if r.DB.UserTablePath == "" || r.DB.AccountTablePath == "" || r.DB.TransactionTablePath == "" {
-		log.Fatal("File paths cannot be empty")
+	switch r.DB.Driver {
+	case DBDriverFile:
+		if r.DB.UserTablePath == "" || r.DB.SessionTablePath == "" || r.DB.LoginAttemptTablePath == "" || r.DB.AccountTablePath == "" || r.DB.TransactionTablePath == "" || r.DB.IdempotencyTablePath == "" || r.DB.LedgerTablePath == "" || r.DB.TxLogPath == "" {
+			log.Fatal("File paths cannot be empty")
+		}
+	case DBDriverSQLite:
+		if r.DB.SQLitePath == "" {
+			log.Fatal("SQLite path cannot be empty")
+		}
+	default:
+		log.Fatalf("Unknown db driver %q", r.DB.Driver)
 	}
-	if r.Jwt.SecretKey == "" {
-		log.Fatal("Secret key cannot be empty")
+	switch r.Jwt.Algorithm {
+	case JwtAlgorithmHS256:
+		if r.Jwt.SecretKey == "" {
+			log.Fatal("Secret key cannot be empty")
+		}
+	case JwtAlgorithmRS256, JwtAlgorithmES256:
+		if r.Jwt.KeyDir == "" && r.Jwt.JWKSURL == "" {
+			log.Fatal("Key directory cannot be empty")
+		}
+	default:
+		log.Fatalf("Unknown jwt algorithm %q", r.Jwt.Algorithm)
 	}
-	if r.Jwt.Duration == 0 {
+	if r.Jwt.Duration == 0 || r.Jwt.RefreshDuration == 0 {
 		log.Fatal("Duration cannot be 0")
 	}
-	if r.Server.Port == "" {
+	if r.Auth.MaxLoginFailures <= 0 || r.Auth.MaxLoginFailuresPerIP <= 0 || r.Auth.LockoutDuration <= 0 {
+		log.Fatal("Login lockout settings must be positive")
+	}
+	if _, err := accountnumber.Prefix(r.Account.BranchCode, "00"); err != nil {
+		log.Fatalf("Invalid branch_code: %v", err)
+	}
+	if r.Server.Port == "" || r.Server.HTTPPort == "" {
 		log.Fatal("Port number cannot be empty")
 	}
 }
@@ -0,0 +1,57 @@
+package feed
+
+import "sync"
+
+// Feed 계좌 ID 같은 키별로 변경을 알리는 프로세스 내 알림 채널입니다.
+// 알림에는 내용이 없으므로 구독자는 알림을 받으면 저장소에서 변경된 상태를 다시 읽습니다.
+// 구독자가 처리하는 동안 온 알림은 하나로 합쳐지므로 Publish는 막히지 않습니다.
+type Feed struct {
+	mutex       sync.Mutex
+	subscribers map[int64]map[chan struct{}]struct{}
+}
+
+func New() *Feed {
+	return &Feed{subscribers: make(map[int64]map[chan struct{}]struct{})}
+}
+
+// Subscribe key의 변경 알림을 받을 채널과 구독을 끝내는 함수를 반환합니다.
+func (f *Feed) Subscribe(key int64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	f.mutex.Lock()
+	if f.subscribers[key] == nil {
+		f.subscribers[key] = make(map[chan struct{}]struct{})
+	}
+	f.subscribers[key][ch] = struct{}{}
+	f.mutex.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+
+			delete(f.subscribers[key], ch)
+			if len(f.subscribers[key]) == 0 {
+				delete(f.subscribers, key)
+			}
+		})
+	}
+}
+
+// Publish key를 구독 중인 모두에게 알립니다. nil Feed는 아무것도 하지 않습니다.
+func (f *Feed) Publish(key int64) {
+	if f == nil {
+		return
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for ch := range f.subscribers[key] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package feed
+
+import "testing"
+
+func TestFeed_PublishCoalesces(t *testing.T) {
+	f := New()
+	ch, cancel := f.Subscribe(1)
+	defer cancel()
+	other, cancelOther := f.Subscribe(2)
+	defer cancelOther()
+
+	f.Publish(1)
+	f.Publish(1)
+
+	select {
+	case <-ch:
+	default:
+		t.Fatal("subscriber was not notified")
+	}
+	select {
+	case <-ch:
+		t.Fatal("notifications were not coalesced")
+	default:
+	}
+	select {
+	case <-other:
+		t.Fatal("subscriber of another key was notified")
+	default:
+	}
+}
+
+func TestFeed_Cancel(t *testing.T) {
+	f := New()
+	ch, cancel := f.Subscribe(1)
+	cancel()
+	cancel()
+
+	f.Publish(1)
+
+	select {
+	case <-ch:
+		t.Fatal("cancelled subscriber was notified")
+	default:
+	}
+	if len(f.subscribers) != 0 {
+		t.Fatalf("subscribers = %v, want empty", f.subscribers)
+	}
+}
@@ -0,0 +1,152 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"ebank/api/v1"
+)
+
+// Service grpc-gateway에 등록할 gRPC 서비스와 그 서비스의 OpenAPI 문서입니다.
+type Service struct {
+	Register func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error
+	OpenAPI  string // ebank.OpenAPI 안의 파일 이름
+}
+
+var (
+	UserService        = Service{Register: ebank.RegisterUserServiceHandlerFromEndpoint, OpenAPI: "user.swagger.json"}
+	AuthService        = Service{Register: ebank.RegisterAuthServiceHandlerFromEndpoint, OpenAPI: "auth.swagger.json"}
+	AccountService     = Service{Register: ebank.RegisterAccountServiceHandlerFromEndpoint, OpenAPI: "account.swagger.json"}
+	TransactionService = Service{Register: ebank.RegisterTransactionServiceHandlerFromEndpoint, OpenAPI: "transaction.swagger.json"}
+)
+
+// NewHandler REST 요청을 grpcPort의 gRPC 서버로 전달하는 핸들러를 만듭니다.
+// 인터셉터를 그대로 거치도록 서비스를 직접 호출하지 않고 gRPC 엔드포인트로 연결합니다.
+// /openapi.json에는 등록한 서비스의 OpenAPI 문서를 합쳐서, /swagger/에는 Swagger UI를 제공합니다.
+func NewHandler(ctx context.Context, grpcPort string, services ...Service) (http.Handler, error) {
+	gwmux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher))
+
+	endpoint := grpcPort
+	if strings.HasPrefix(endpoint, ":") {
+		endpoint = "localhost" + endpoint
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+
+	fileNames := make([]string, 0, len(services))
+	for _, service := range services {
+		if err := service.Register(ctx, gwmux, endpoint, opts); err != nil {
+			return nil, fmt.Errorf("failed to register gateway handler: %w", err)
+		}
+		fileNames = append(fileNames, service.OpenAPI)
+	}
+
+	openAPI, err := mergeOpenAPI(fileNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", gwmux)
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPI)
+	})
+	mux.HandleFunc("/swagger/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(swaggerUI))
+	})
+
+	return mux, nil
+}
+
+// incomingHeaderMatcher 기본 헤더에 더해 멱등성 키와 추가 인증 코드 헤더를 gRPC 메타데이터로 전달합니다.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "Idempotency-Key") {
+		return "idempotency-key", true
+	}
+	if strings.EqualFold(key, "Otp-Code") {
+		return "otp-code", true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// mergeOpenAPI 서비스별 swagger 문서의 paths, definitions, tags를 하나의 문서로 합칩니다.
+func mergeOpenAPI(fileNames ...string) ([]byte, error) {
+	merged := map[string]any{
+		"swagger":     "2.0",
+		"info":        map[string]any{"title": "ebank API", "version": "v1"},
+		"consumes":    []any{"application/json"},
+		"produces":    []any{"application/json"},
+		"tags":        []any{},
+		"paths":       map[string]any{},
+		"definitions": map[string]any{},
+	}
+
+	for _, fileName := range fileNames {
+		data, err := ebank.OpenAPI.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc map[string]any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", fileName, err)
+		}
+
+		if tags, ok := doc["tags"].([]any); ok {
+			merged["tags"] = append(merged["tags"].([]any), tags...)
+		}
+
+		paths := merged["paths"].(map[string]any)
+		for path, item := range asMap(doc["paths"]) {
+			// 같은 경로를 여러 서비스가 쓰면 HTTP 메서드별로 합칩니다.
+			existing := asMap(paths[path])
+			if existing == nil {
+				existing = map[string]any{}
+			}
+			for method, operation := range asMap(item) {
+				existing[method] = operation
+			}
+			paths[path] = existing
+		}
+
+		definitions := merged["definitions"].(map[string]any)
+		for name, definition := range asMap(doc["definitions"]) {
+			definitions[name] = definition
+		}
+	}
+
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+const swaggerUI = `<!DOCTYPE html>
+<html lang="ko">
+<head>
+  <meta charset="utf-8" />
+  <title>ebank API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
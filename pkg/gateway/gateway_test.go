@@ -0,0 +1,57 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMergeOpenAPI(t *testing.T) {
+	data, err := mergeOpenAPI(AccountService.OpenAPI, TransactionService.OpenAPI)
+	if err != nil {
+		t.Fatalf("mergeOpenAPI() error = %v", err)
+	}
+
+	var doc struct {
+		Tags        []map[string]any          `json:"tags"`
+		Paths       map[string]map[string]any `json:"paths"`
+		Definitions map[string]any            `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("merged document is not JSON: %v", err)
+	}
+
+	for _, path := range []string{"/v1/accounts/{id}", "/v1/transfers"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("paths[%q] missing", path)
+		}
+	}
+	if _, ok := doc.Paths["/v1/users"]; ok {
+		t.Errorf("paths of unregistered UserService should not be merged")
+	}
+	for _, name := range []string{"protoAccountResponse", "protoTransferResponse"} {
+		if _, ok := doc.Definitions[name]; !ok {
+			t.Errorf("definitions[%q] missing", name)
+		}
+	}
+	if len(doc.Tags) != 2 {
+		t.Errorf("len(tags) = %d, want 2", len(doc.Tags))
+	}
+}
+
+func TestIncomingHeaderMatcher(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{header: "Idempotency-Key", want: "idempotency-key", ok: true},
+		{header: "Authorization", want: "grpcgateway-Authorization", ok: true},
+		{header: "X-Unknown", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := incomingHeaderMatcher(tt.header)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("incomingHeaderMatcher(%q) = %q, %v, want %q, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
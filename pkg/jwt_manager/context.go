@@ -0,0 +1,16 @@
+package jwt_manager
+
+import "context"
+
+type claimsContextKey struct{}
+
+// NewContext 인터셉터가 검증한 토큰의 클레임을 요청 컨텍스트에 담습니다.
+func NewContext(ctx context.Context, claims *UserClaims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// FromContext 요청한 사용자의 클레임을 꺼냅니다. 인증을 거치지 않은 요청이면 false를 반환합니다.
+func FromContext(ctx context.Context) (*UserClaims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*UserClaims)
+	return claims, ok && claims != nil
+}
@@ -0,0 +1,90 @@
+package jwt_manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// remoteKeySet 토큰 발급 서비스의 JWKS로 검증만 합니다. 모르는 kid가 들어오면 JWKS를 다시 받습니다.
+type remoteKeySet struct {
+	url       string
+	client    *http.Client
+	mutex     sync.Mutex
+	keys      map[string]JSONWebKey
+	lastFetch time.Time
+}
+
+func NewRemoteKeySet(url string) KeySet {
+	return &remoteKeySet{
+		url:    url,
+		client: &http.Client{Timeout: 5 * time.Second},
+		keys:   make(map[string]JSONWebKey),
+	}
+}
+
+func (r *remoteKeySet) SigningKey() (string, jwt.SigningMethod, interface{}, error) {
+	return "", nil, nil, fmt.Errorf("remote key set %s can only verify tokens", r.url)
+}
+
+func (r *remoteKeySet) VerificationKey(kid string) (jwt.SigningMethod, interface{}, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	jwk, ok := r.keys[kid]
+	if !ok && time.Since(r.lastFetch) >= reloadInterval {
+		if err := r.fetch(); err != nil {
+			return nil, nil, err
+		}
+		jwk, ok = r.keys[kid]
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return fromJSONWebKey(jwk)
+}
+
+func (r *remoteKeySet) PublicKeys() []JSONWebKey {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	publicKeys := make([]JSONWebKey, 0, len(r.keys))
+	for _, jwk := range r.keys {
+		publicKeys = append(publicKeys, jwk)
+	}
+
+	return publicKeys
+}
+
+// fetch mutex를 잡은 상태에서 호출해야 합니다.
+func (r *remoteKeySet) fetch() error {
+	r.lastFetch = time.Now()
+
+	resp, err := r.client.Get(r.url)
+	if err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch jwks: %s", resp.Status)
+	}
+
+	var keySet jsonWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := make(map[string]JSONWebKey, len(keySet.Keys))
+	for _, jwk := range keySet.Keys {
+		keys[jwk.Kid] = jwk
+	}
+	r.keys = keys
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package jwt_manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"ebank/pkg/config"
+	"ebank/services/user/model"
+)
+
+type sessionStore map[string]model.Session
+
+func (s sessionStore) GetSession(_ context.Context, id string) (*model.Session, error) {
+	session, ok := s[id]
+	if !ok {
+		return nil, fmt.Errorf("session %s not found", id)
+	}
+	return &session, nil
+}
+
+func TestVerify(t *testing.T) {
+	for _, algorithm := range []string{config.JwtAlgorithmHS256, config.JwtAlgorithmRS256, config.JwtAlgorithmES256} {
+		t.Run(algorithm, func(t *testing.T) {
+			keys, err := OpenSigningKeys(config.JwtConfig{Algorithm: algorithm, SecretKey: "secret", KeyDir: t.TempDir(), RefreshDuration: time.Hour})
+			if err != nil {
+				t.Fatal(err)
+			}
+			testVerify(t, keys)
+		})
+	}
+}
+
+func testVerify(t *testing.T, keys KeySet) {
+	sessions := sessionStore{}
+	manager := NewJWTManager(keys, time.Minute, time.Hour, sessions)
+	user := model.User{ID: 1, PhoneNumber: "01012345678", Role: model.RoleTeller}
+
+	session := manager.NewSession(user.ID)
+	sessions[session.ID] = session
+
+	accessToken, err := manager.Generate(user, session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	refreshToken, err := manager.GenerateRefreshToken(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := manager.Verify(accessToken)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.UserID != user.ID || claims.Role != model.RoleTeller || claims.SessionID != session.ID {
+		t.Errorf("Verify() claims = %+v", claims)
+	}
+
+	refreshClaims, err := manager.VerifyRefreshToken(refreshToken)
+	if err != nil {
+		t.Fatalf("VerifyRefreshToken() error = %v", err)
+	}
+	if refreshClaims.Id != session.RefreshTokenID || refreshClaims.SessionID != session.ID {
+		t.Errorf("VerifyRefreshToken() claims = %+v", refreshClaims)
+	}
+
+	if _, err := manager.Verify(refreshToken); err == nil {
+		t.Error("Verify() accepted a refresh token")
+	}
+	if _, err := manager.VerifyRefreshToken(accessToken); err == nil {
+		t.Error("VerifyRefreshToken() accepted an access token")
+	}
+
+	session.RevokedAt = time.Now()
+	sessions[session.ID] = session
+	if _, err := manager.Verify(accessToken); err == nil {
+		t.Error("Verify() accepted a token of a revoked session")
+	}
+
+	delete(sessions, session.ID)
+	if _, err := manager.Verify(accessToken); err == nil {
+		t.Error("Verify() accepted a token of an unknown session")
+	}
+}
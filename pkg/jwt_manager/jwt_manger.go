@@ -1,6 +1,9 @@
 package jwt_manager
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -9,71 +12,180 @@ import (
 	"ebank/services/user/model"
 )
 
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 type JWTManager interface {
-	Generate(user model.User) (string, error)
+	Generate(user model.User, sessionID string) (string, error)
+	GenerateRefreshToken(session model.Session) (string, error)
 	Verify(accessToken string) (*UserClaims, error)
+	VerifyRefreshToken(refreshToken string) (*RefreshClaims, error)
+	NewSession(userID int64) model.Session
+}
+
+// SessionStore Verify가 토큰의 세션이 폐기되었는지 확인할 때 사용합니다.
+type SessionStore interface {
+	GetSession(ctx context.Context, id string) (*model.Session, error)
 }
 
 type jwtManager struct {
-	secretKey        string
-	tokenDuration    time.Duration
-	userJwtExpiryMap map[string]int64 // redis 서버를 이용하여 저장
+	keys            KeySet
+	tokenDuration   time.Duration
+	refreshDuration time.Duration
+	sessions        SessionStore
 }
 
 type UserClaims struct {
 	jwt.StandardClaims
+	UserID      int64  `json:"user_id"`
 	PhoneNumber string `json:"username"`
+	Role        string `json:"role"`
+	SessionID   string `json:"sid"`
+	TokenType   string `json:"token_type"`
+}
+
+// RefreshClaims 리프레시 토큰의 클레임입니다. StandardClaims.Id가 세션의 RefreshTokenID와 같아야 합니다.
+type RefreshClaims struct {
+	jwt.StandardClaims
+	UserID    int64  `json:"user_id"`
+	SessionID string `json:"sid"`
+	TokenType string `json:"token_type"`
 }
 
-func NewJWTManager(secretKey string, tokenDuration time.Duration) JWTManager {
+func NewJWTManager(keys KeySet, tokenDuration time.Duration, refreshDuration time.Duration, sessions SessionStore) JWTManager {
 	return &jwtManager{
-		secretKey:        secretKey,
-		tokenDuration:    tokenDuration,
-		userJwtExpiryMap: make(map[string]int64),
+		keys:            keys,
+		tokenDuration:   tokenDuration,
+		refreshDuration: refreshDuration,
+		sessions:        sessions,
+	}
+}
+
+// NewTokenID 세션과 리프레시 토큰을 구분하는 임의의 ID를 만듭니다.
+func NewTokenID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (manager *jwtManager) NewSession(userID int64) model.Session {
+	now := time.Now()
+	return model.Session{
+		ID:             NewTokenID(),
+		UserID:         userID,
+		RefreshTokenID: NewTokenID(),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(manager.refreshDuration),
 	}
 }
 
-func (manager *jwtManager) Generate(user model.User) (string, error) {
+func (manager *jwtManager) Generate(user model.User, sessionID string) (string, error) {
 	claims := UserClaims{
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(manager.tokenDuration).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
+		UserID:      user.ID,
 		PhoneNumber: user.PhoneNumber,
+		Role:        user.GetRole(),
+		SessionID:   sessionID,
+		TokenType:   TokenTypeAccess,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(manager.secretKey))
+	return manager.sign(claims)
+}
+
+func (manager *jwtManager) GenerateRefreshToken(session model.Session) (string, error) {
+	claims := RefreshClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        session.RefreshTokenID,
+			ExpiresAt: session.ExpiresAt.Unix(),
+			IssuedAt:  time.Now().Unix(),
+		},
+		UserID:    session.UserID,
+		SessionID: session.ID,
+		TokenType: TokenTypeRefresh,
+	}
+
+	return manager.sign(claims)
 }
 
 func (manager *jwtManager) Verify(accessToken string) (*UserClaims, error) {
-	token, err := jwt.ParseWithClaims(
-		accessToken,
-		&UserClaims{},
+	claims := &UserClaims{}
+	if err := manager.parse(accessToken, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != TokenTypeAccess {
+		return nil, fmt.Errorf("invalid token claims - not an access token")
+	}
+
+	// 로그아웃 등으로 세션이 폐기되면 만료 전인 액세스 토큰도 거부합니다.
+	session, err := manager.sessions.GetSession(context.Background(), claims.SessionID)
+	if err != nil || session == nil {
+		return nil, fmt.Errorf("invalid token claims - unknown session")
+	}
+	if session.IsRevoked() {
+		return nil, fmt.Errorf("invalid token claims - revoked token")
+	}
+
+	return claims, nil
+}
+
+func (manager *jwtManager) VerifyRefreshToken(refreshToken string) (*RefreshClaims, error) {
+	claims := &RefreshClaims{}
+	if err := manager.parse(refreshToken, claims); err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != TokenTypeRefresh {
+		return nil, fmt.Errorf("invalid token claims - not a refresh token")
+	}
+
+	return claims, nil
+}
+
+// sign 현재 서명 키로 서명하고, 검증할 때 키를 찾을 수 있도록 헤더에 kid를 남깁니다.
+func (manager *jwtManager) sign(claims jwt.Claims) (string, error) {
+	kid, method, key, err := manager.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	return token.SignedString(key)
+}
+
+func (manager *jwtManager) parse(tokenString string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(
+		tokenString,
+		claims,
 		func(token *jwt.Token) (interface{}, error) {
-			_, ok := token.Method.(*jwt.SigningMethodHMAC)
-			if !ok {
+			kid, _ := token.Header["kid"].(string)
+			method, key, err := manager.keys.VerificationKey(kid)
+			if err != nil {
+				return nil, err
+			}
+
+			// 헤더의 alg를 믿지 않고 키에 맞는 알고리즘인지 확인합니다.
+			if token.Method.Alg() != method.Alg() {
 				return nil, fmt.Errorf("unexpected token signing method")
 			}
 
-			return []byte(manager.secretKey), nil
+			return key, nil
 		},
 	)
-
 	if err != nil {
-		return nil, fmt.Errorf("invalid token: %w", err)
-	}
-
-	claims, ok := token.Claims.(*UserClaims)
-	if !ok {
-		return nil, fmt.Errorf("invalid token claims")
+		return fmt.Errorf("invalid token: %w", err)
 	}
 
-	// 서버에서 강제로 토큰을 만료하고 싶을 때 활용
-	// if jwtExpiryDate, ok := manager.userJwtExpiryMap[claims.PhoneNumber]; ok && claims.IssuedAt < jwtExpiryDate {
-	//	// 발급한 토큰이 서버 지정 만료시간보다 더 이전 토큰이라면 만료된 토큰으로 취급
-	//	return nil, fmt.Errorf("invalid token claims - expired token")
-	// }
-
-	return claims, nil
+	return nil
 }
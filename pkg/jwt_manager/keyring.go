@@ -0,0 +1,298 @@
+package jwt_manager
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"ebank/pkg/atomic_file"
+	"ebank/pkg/config"
+)
+
+const (
+	keyFileSuffix = ".pem"
+	// reloadInterval 모르는 kid가 들어왔을 때 디렉토리를 다시 읽는 최소 간격입니다.
+	reloadInterval = 5 * time.Second
+)
+
+// KeyRing 디렉토리의 <kid>.pem 파일에서 읽은 RS256/ES256 키 묶음입니다.
+// 가장 최근 개인 키로 서명하고, 교체된 키는 그 키로 발급한 토큰이 만료될 때까지(retention) 검증에 남겨 둡니다.
+// 공개 키만 있는 파일은 검증에만 씁니다.
+type KeyRing struct {
+	dir        string
+	retention  time.Duration
+	mutex      sync.RWMutex
+	keys       map[string]ringKey
+	active     string
+	lastReload time.Time
+}
+
+type ringKey struct {
+	kid       string
+	method    jwt.SigningMethod
+	private   crypto.Signer // 공개 키만 있으면 nil
+	public    crypto.PublicKey
+	createdAt time.Time
+}
+
+func LoadKeyRing(dir string, retention time.Duration) (*KeyRing, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	ring := &KeyRing{dir: dir, retention: retention}
+	if err := ring.reload(); err != nil {
+		return nil, err
+	}
+
+	return ring, nil
+}
+
+// reload 디렉토리의 키를 다시 읽습니다. mutex를 잡은 상태에서 호출하거나 다른 고루틴이 없을 때 호출해야 합니다.
+func (r *KeyRing) reload() error {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]ringKey, len(entries))
+	var active ringKey
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), keyFileSuffix) {
+			continue
+		}
+
+		key, err := readKeyFile(filepath.Join(r.dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		keys[key.kid] = key
+
+		if key.private != nil && (active.kid == "" || key.createdAt.After(active.createdAt)) {
+			active = key
+		}
+	}
+
+	r.keys = keys
+	r.active = active.kid
+	r.lastReload = time.Now()
+
+	return nil
+}
+
+func readKeyFile(path string) (ringKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ringKey{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return ringKey{}, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return ringKey{}, fmt.Errorf("%s: no PEM data", path)
+	}
+
+	key := ringKey{
+		kid:       strings.TrimSuffix(filepath.Base(path), keyFileSuffix),
+		createdAt: info.ModTime(),
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return ringKey{}, fmt.Errorf("%s: unsupported PEM type %s", path, block.Type)
+	}
+	if err != nil {
+		return ringKey{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.private = signer
+		key.public = signer.Public()
+	} else {
+		key.public = parsed
+	}
+
+	key.method, err = signingMethod(key.public)
+	if err != nil {
+		return ringKey{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// EnsureSigningKey 서명 키가 없거나 알고리즘이 바뀌었으면 새 키를 만듭니다.
+func (r *KeyRing) EnsureSigningKey(algorithm string) error {
+	r.mutex.RLock()
+	active, ok := r.keys[r.active]
+	r.mutex.RUnlock()
+
+	if ok && active.method.Alg() == algorithm {
+		return nil
+	}
+
+	return r.Rotate(algorithm)
+}
+
+// Rotate 새 개인 키를 만들어 서명 키로 바꾸고, 보관 기간이 지난 옛 키를 지웁니다.
+func (r *KeyRing) Rotate(algorithm string) error {
+	var private crypto.Signer
+	var err error
+	switch algorithm {
+	case config.JwtAlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case config.JwtAlgorithmES256:
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	default:
+		return fmt.Errorf("unsupported jwt algorithm %s", algorithm)
+	}
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	kid := now.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := atomic_file.WriteFile(filepath.Join(r.dir, kid+keyFileSuffix), data, 0o600); err != nil {
+		return err
+	}
+
+	method, err := signingMethod(private)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.keys[kid] = ringKey{kid: kid, method: method, private: private, public: private.Public(), createdAt: now}
+	r.active = kid
+	r.prune(now)
+
+	return nil
+}
+
+// prune 다음 키로 교체된 지 retention이 지난 키를 지웁니다. mutex를 잡은 상태에서 호출해야 합니다.
+func (r *KeyRing) prune(now time.Time) {
+	keys := make([]ringKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].createdAt.Before(keys[j].createdAt) })
+
+	for i := 0; i < len(keys)-1; i++ {
+		retiredAt := keys[i+1].createdAt
+		if keys[i].kid == r.active || now.Sub(retiredAt) <= r.retention {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(r.dir, keys[i].kid+keyFileSuffix)); err != nil && !os.IsNotExist(err) {
+			log.Printf("failed to remove retired jwt key %s: %v", keys[i].kid, err)
+			continue
+		}
+		delete(r.keys, keys[i].kid)
+	}
+}
+
+// RotateEvery interval마다 Rotate를 호출합니다. 고루틴으로 실행합니다.
+func (r *KeyRing) RotateEvery(algorithm string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := r.Rotate(algorithm); err != nil {
+			log.Printf("failed to rotate jwt key: %v", err)
+		}
+	}
+}
+
+func (r *KeyRing) SigningKey() (string, jwt.SigningMethod, interface{}, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	key, ok := r.keys[r.active]
+	if !ok {
+		return "", nil, nil, fmt.Errorf("no signing key in %s", r.dir)
+	}
+
+	return key.kid, key.method, key.private, nil
+}
+
+func (r *KeyRing) VerificationKey(kid string) (jwt.SigningMethod, interface{}, error) {
+	r.mutex.RLock()
+	key, ok := r.keys[kid]
+	r.mutex.RUnlock()
+	if ok {
+		return key.method, key.public, nil
+	}
+
+	// 다른 프로세스가 교체한 키일 수 있으므로 디렉토리를 다시 읽습니다.
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if time.Since(r.lastReload) >= reloadInterval {
+		if err := r.reload(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	key, ok = r.keys[kid]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	return key.method, key.public, nil
+}
+
+func (r *KeyRing) PublicKeys() []JSONWebKey {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	publicKeys := make([]JSONWebKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		jwk, err := toJSONWebKey(key.kid, key.method, key.public)
+		if err != nil {
+			continue
+		}
+		publicKeys = append(publicKeys, jwk)
+	}
+	sort.Slice(publicKeys, func(i, j int) bool { return publicKeys[i].Kid < publicKeys[j].Kid })
+
+	return publicKeys
+}
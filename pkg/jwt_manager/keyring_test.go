@@ -0,0 +1,155 @@
+package jwt_manager
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"ebank/pkg/config"
+	"ebank/services/user/model"
+)
+
+func TestKeyRing_RotateKeepsOldKeys(t *testing.T) {
+	dir := t.TempDir()
+	ring, err := LoadKeyRing(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ring.EnsureSigningKey(config.JwtAlgorithmES256); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := sessionStore{}
+	manager := NewJWTManager(ring, time.Minute, time.Hour, sessions)
+	session := manager.NewSession(1)
+	sessions[session.ID] = session
+
+	oldToken, err := manager.Generate(model.User{ID: 1}, session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKid, _, _, _ := ring.SigningKey()
+
+	if err := ring.Rotate(config.JwtAlgorithmRS256); err != nil {
+		t.Fatal(err)
+	}
+	newKid, method, _, _ := ring.SigningKey()
+	if newKid == oldKid || method != jwt.SigningMethodRS256 {
+		t.Fatalf("SigningKey() = %s %s after rotation", newKid, method.Alg())
+	}
+
+	if _, err := manager.Verify(oldToken); err != nil {
+		t.Errorf("Verify() of a token signed by the rotated key: %v", err)
+	}
+
+	// 다시 읽어도 가장 최근 개인 키로 서명합니다.
+	reloaded, err := LoadKeyRing(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid, _, _, _ := reloaded.SigningKey(); kid != newKid {
+		t.Errorf("reloaded SigningKey() = %s, want %s", kid, newKid)
+	}
+	if len(reloaded.PublicKeys()) != 2 {
+		t.Errorf("PublicKeys() = %v, want 2 keys", reloaded.PublicKeys())
+	}
+}
+
+func TestKeyRing_PruneRetiredKeys(t *testing.T) {
+	dir := t.TempDir()
+	ring, err := LoadKeyRing(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 3시간 전에 만든 키가 2시간 전에 교체되어 보관 기간(1시간)이 지난 상태로 만듭니다.
+	kids := make([]string, 0, 2)
+	for _, age := range []time.Duration{3 * time.Hour, 2 * time.Hour} {
+		if err := ring.Rotate(config.JwtAlgorithmES256); err != nil {
+			t.Fatal(err)
+		}
+		kid, _, _, _ := ring.SigningKey()
+		kids = append(kids, kid)
+
+		createdAt := time.Now().Add(-age)
+		ring.mutex.Lock()
+		key := ring.keys[kid]
+		key.createdAt = createdAt
+		ring.keys[kid] = key
+		ring.mutex.Unlock()
+	}
+
+	if err := ring.Rotate(config.JwtAlgorithmES256); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, kids[0]+keyFileSuffix)); !os.IsNotExist(err) {
+		t.Errorf("retired key %s was not removed: %v", kids[0], err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, kids[1]+keyFileSuffix)); err != nil {
+		t.Errorf("key %s in retention was removed: %v", kids[1], err)
+	}
+	if len(ring.PublicKeys()) != 2 {
+		t.Errorf("PublicKeys() = %v, want 2 keys", ring.PublicKeys())
+	}
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	for _, algorithm := range []string{config.JwtAlgorithmRS256, config.JwtAlgorithmES256} {
+		t.Run(algorithm, func(t *testing.T) {
+			signingKeys, err := OpenSigningKeys(config.JwtConfig{Algorithm: algorithm, KeyDir: t.TempDir(), RefreshDuration: time.Hour})
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := httptest.NewServer(JWKSHandler(signingKeys))
+			defer server.Close()
+
+			sessions := sessionStore{}
+			issuer := NewJWTManager(signingKeys, time.Minute, time.Hour, sessions)
+			verifier := NewJWTManager(NewRemoteKeySet(server.URL), time.Minute, time.Hour, sessions)
+
+			session := issuer.NewSession(1)
+			sessions[session.ID] = session
+			token, err := issuer.Generate(model.User{ID: 1}, session.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := verifier.Verify(token); err != nil {
+				t.Errorf("Verify() with JWKS: %v", err)
+			}
+			if _, err := verifier.Generate(model.User{ID: 1}, session.ID); err == nil {
+				t.Error("Generate() with a remote key set should fail")
+			}
+		})
+	}
+}
+
+// 공개 키를 HMAC 비밀 키로 써서 만든 토큰(alg 혼동 공격)은 거부합니다.
+func TestVerify_RejectsAlgorithmConfusion(t *testing.T) {
+	ring, err := LoadKeyRing(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ring.EnsureSigningKey(config.JwtAlgorithmRS256); err != nil {
+		t.Fatal(err)
+	}
+	kid, _, _, _ := ring.SigningKey()
+	jwk := ring.PublicKeys()[0]
+
+	sessions := sessionStore{"session": {ID: "session"}}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, UserClaims{SessionID: "session", TokenType: TokenTypeAccess})
+	token.Header["kid"] = kid
+	forged, err := token.SignedString([]byte(jwk.N))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewJWTManager(ring, time.Minute, time.Hour, sessions).Verify(forged); err == nil {
+		t.Error("Verify() accepted an HS256 token for an RS256 key")
+	}
+}
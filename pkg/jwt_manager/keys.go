@@ -0,0 +1,196 @@
+package jwt_manager
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"ebank/pkg/config"
+)
+
+// JWKSPath 공개 키 목록(JWKS)을 제공하는 HTTP 경로입니다.
+const JWKSPath = "/.well-known/jwks.json"
+
+// KeySet 토큰 서명과 검증에 쓸 키를 제공합니다.
+type KeySet interface {
+	// SigningKey 새 토큰에 서명할 키입니다. 검증만 하는 키 집합이면 에러를 반환합니다.
+	SigningKey() (kid string, method jwt.SigningMethod, key interface{}, err error)
+	// VerificationKey 토큰 헤더의 kid에 해당하는 검증 키입니다.
+	VerificationKey(kid string) (method jwt.SigningMethod, key interface{}, err error)
+	// PublicKeys JWKS로 공개할 키입니다. 대칭 키는 공개하지 않습니다.
+	PublicKeys() []JSONWebKey
+}
+
+// OpenSigningKeys 토큰을 발급하는 서비스(cmd/user, cmd/ebank)의 키를 엽니다.
+// 비대칭 알고리즘이면 KeyDir의 키 링을 읽고, 서명 키가 없으면 만들며, KeyRotation마다 새 키로 교체합니다.
+func OpenSigningKeys(cfg config.JwtConfig) (KeySet, error) {
+	if cfg.Algorithm == config.JwtAlgorithmHS256 {
+		return hmacKeySet{secretKey: []byte(cfg.SecretKey)}, nil
+	}
+
+	ring, err := LoadKeyRing(cfg.KeyDir, cfg.RefreshDuration)
+	if err != nil {
+		return nil, err
+	}
+	if err := ring.EnsureSigningKey(cfg.Algorithm); err != nil {
+		return nil, err
+	}
+	if cfg.KeyRotation > 0 {
+		go ring.RotateEvery(cfg.Algorithm, cfg.KeyRotation)
+	}
+
+	return ring, nil
+}
+
+// OpenVerificationKeys 토큰을 검증만 하는 서비스(cmd/account, cmd/transaction)의 키를 엽니다.
+// JWKSURL이 있으면 개인 키 없이 발급 서비스의 JWKS로 검증합니다.
+func OpenVerificationKeys(cfg config.JwtConfig) (KeySet, error) {
+	if cfg.Algorithm == config.JwtAlgorithmHS256 {
+		return hmacKeySet{secretKey: []byte(cfg.SecretKey)}, nil
+	}
+
+	if cfg.JWKSURL != "" {
+		return NewRemoteKeySet(cfg.JWKSURL), nil
+	}
+
+	return LoadKeyRing(cfg.KeyDir, cfg.RefreshDuration)
+}
+
+// hmacKeySet 발급과 검증에 같은 비밀 키를 쓰는 HS256 키입니다.
+type hmacKeySet struct {
+	secretKey []byte
+}
+
+func (k hmacKeySet) SigningKey() (string, jwt.SigningMethod, interface{}, error) {
+	return "", jwt.SigningMethodHS256, k.secretKey, nil
+}
+
+func (k hmacKeySet) VerificationKey(string) (jwt.SigningMethod, interface{}, error) {
+	return jwt.SigningMethodHS256, k.secretKey, nil
+}
+
+func (k hmacKeySet) PublicKeys() []JSONWebKey {
+	return nil
+}
+
+// JSONWebKey RFC 7517의 공개 키 하나입니다. RSA(n, e)와 P-256 EC(crv, x, y) 키만 다룹니다.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type jsonWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKSHandler keys의 공개 키를 JWKS 문서로 제공합니다.
+func JWKSHandler(keys KeySet) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		publicKeys := keys.PublicKeys()
+		if publicKeys == nil {
+			publicKeys = []JSONWebKey{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "max-age=60")
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: publicKeys})
+	})
+}
+
+// signingMethod 키 종류에 맞는 서명 알고리즘입니다.
+func signingMethod(key interface{}) (jwt.SigningMethod, error) {
+	switch key := key.(type) {
+	case *rsa.PublicKey, *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("unsupported ecdsa curve %s", key.Curve.Params().Name)
+		}
+		return jwt.SigningMethodES256, nil
+	case *ecdsa.PrivateKey:
+		return signingMethod(&key.PublicKey)
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+func toJSONWebKey(kid string, method jwt.SigningMethod, key interface{}) (JSONWebKey, error) {
+	jwk := JSONWebKey{Kid: kid, Use: "sig", Alg: method.Alg()}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = key.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+	default:
+		return JSONWebKey{}, fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	return jwk, nil
+}
+
+func fromJSONWebKey(jwk JSONWebKey) (jwt.SigningMethod, interface{}, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	var key interface{}
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, nil, err
+		}
+		e, err := decode(jwk.E)
+		if err != nil {
+			return nil, nil, err
+		}
+		key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	case "EC":
+		if jwk.Crv != elliptic.P256().Params().Name {
+			return nil, nil, fmt.Errorf("unsupported ecdsa curve %s", jwk.Crv)
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, nil, err
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, nil, err
+		}
+		key = &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	default:
+		return nil, nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+	}
+
+	method, err := signingMethod(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return method, key, nil
+}
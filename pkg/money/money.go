@@ -0,0 +1,209 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency 통화 코드가 없는 기존 데이터와 요청에 사용하는 기본 통화입니다.
+const DefaultCurrency = "KRW"
+
+// minorUnits 통화별 소수 자릿수 (ISO 4217)
+var minorUnits = map[string]int{
+	"KRW": 0,
+	"JPY": 0,
+	"USD": 2,
+	"EUR": 2,
+	"CNY": 2,
+}
+
+// Money 금액을 최소 화폐 단위의 정수와 통화 코드로 표현합니다.
+// 부동소수점 오차 없이 더하고 뺄 수 있습니다.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func Zero(currency string) Money {
+	return Money{Currency: currency}
+}
+
+func IsSupportedCurrency(currency string) bool {
+	_, ok := minorUnits[currency]
+	return ok
+}
+
+// fromMajorTolerance FromMajor가 부동소수점 오차로 보고 반올림하는 최소 단위 차이의 한도입니다.
+const fromMajorTolerance = 1e-6
+
+// FromMajor 주 화폐 단위의 실수 금액(예: 12.34 USD)을 최소 단위로 변환합니다.
+// 기존 float64 데이터를 옮길 때만 사용합니다. 부동소수점 오차만 바로잡고,
+// 최소 단위로 나누어떨어지지 않는 금액(예: 1000.5 KRW)은 말없이 반올림하지 않고 거부합니다.
+func FromMajor(amount float64, currency string) (Money, error) {
+	exp, ok := minorUnits[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("unsupported currency %q", currency)
+	}
+
+	scaled := amount * math.Pow10(exp)
+	minor := math.Round(scaled)
+	if math.IsNaN(minor) || minor > math.MaxInt64 || minor < math.MinInt64 {
+		return Money{}, fmt.Errorf("amount %v out of range", amount)
+	}
+	if math.Abs(scaled-minor) > fromMajorTolerance {
+		return Money{}, fmt.Errorf("amount %v has more than %d decimal places for %s", amount, exp, currency)
+	}
+
+	return Money{Amount: int64(minor), Currency: currency}, nil
+}
+
+// Parse "12.34" 형태의 10진수 문자열을 해석합니다. 통화의 소수 자릿수를 넘는 값은 거부합니다.
+// 부호는 맨 앞의 '-' 하나만 허용하며 "+5", "--5" 같은 값은 거부합니다.
+func Parse(s string, currency string) (Money, error) {
+	exp, ok := minorUnits[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("unsupported currency %q", currency)
+	}
+
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" || len(frac) > exp || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("invalid amount %q for %s", s, currency)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("currency mismatch: %s and %s", m.Currency, other.Currency)
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("amount overflow")
+	}
+
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("amount overflow")
+	}
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+// Cmp m이 other보다 작으면 -1, 같으면 0, 크면 1을 반환합니다.
+func (m Money) Cmp(other Money) (int, error) {
+	if m.Currency != other.Currency {
+		return 0, fmt.Errorf("currency mismatch: %s and %s", m.Currency, other.Currency)
+	}
+
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// String 주 화폐 단위로 표기합니다. (예: "12.34 USD")
+func (m Money) String() string {
+	exp := minorUnits[m.Currency]
+	if exp == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+	}
+	digits := strconv.FormatUint(absUint(amount), 10)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+
+	return fmt.Sprintf("%s%s.%s %s", sign, digits[:len(digits)-exp], digits[len(digits)-exp:], m.Currency)
+}
+
+// UnmarshalJSON 기존 파일에 float64로 저장된 금액(예: 1000)도 DefaultCurrency 금액으로 읽어 들입니다.
+// 원화로 나누어떨어지지 않는 금액(예: 1000.5)은 반올림하지 않고 오류를 반환합니다.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] != '{' {
+		var legacy float64
+		if err := json.Unmarshal(trimmed, &legacy); err != nil {
+			return err
+		}
+
+		migrated, err := FromMajor(legacy, DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = migrated
+		return nil
+	}
+
+	type plain Money
+	var decoded plain
+	if err := json.Unmarshal(trimmed, &decoded); err != nil {
+		return err
+	}
+	if decoded.Currency == "" {
+		decoded.Currency = DefaultCurrency
+	}
+	*m = Money(decoded)
+
+	return nil
+}
+
+// isDigits s가 0-9 숫자로만 이루어졌는지 확인합니다. 빈 문자열은 true입니다.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func absUint(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
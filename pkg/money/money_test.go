@@ -0,0 +1,124 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMoney_AddDoesNotDrift(t *testing.T) {
+	tenCents := New(10, "USD")
+	total := Zero("USD")
+	for i := 0; i < 1000; i++ {
+		var err error
+		if total, err = total.Add(tenCents); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	if want := New(10000, "USD"); total != want {
+		t.Errorf("total = %v, want %v", total, want)
+	}
+}
+
+func TestMoney_CurrencyMismatch(t *testing.T) {
+	if _, err := New(100, "KRW").Add(New(1, "USD")); err == nil {
+		t.Error("Add() with different currencies should fail")
+	}
+	if _, err := New(100, "KRW").Cmp(New(1, "USD")); err == nil {
+		t.Error("Cmp() with different currencies should fail")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		currency string
+		want     Money
+		wantErr  bool
+	}{
+		{name: "달러 소수", input: "12.34", currency: "USD", want: New(1234, "USD")},
+		{name: "달러 소수 한 자리", input: "0.1", currency: "USD", want: New(10, "USD")},
+		{name: "음수", input: "-5.05", currency: "EUR", want: New(-505, "EUR")},
+		{name: "원화 정수", input: "1000", currency: "KRW", want: New(1000, "KRW")},
+		{name: "원화 소수 거부", input: "1000.5", currency: "KRW", wantErr: true},
+		{name: "자릿수 초과", input: "1.234", currency: "USD", wantErr: true},
+		{name: "미지원 통화", input: "1", currency: "XXX", wantErr: true},
+		{name: "더하기 부호 거부", input: "+5", currency: "KRW", wantErr: true},
+		{name: "이중 음수 부호 거부", input: "--5", currency: "KRW", wantErr: true},
+		{name: "소수부 부호 거부", input: "1.-5", currency: "USD", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: New(1234, "USD"), want: "12.34 USD"},
+		{money: New(5, "USD"), want: "0.05 USD"},
+		{money: New(-5, "USD"), want: "-0.05 USD"},
+		{money: New(1000, "KRW"), want: "1000 KRW"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestMoney_UnmarshalLegacyFloat(t *testing.T) {
+	var account struct {
+		Balance Money
+	}
+
+	if err := json.Unmarshal([]byte(`{"Balance":1000}`), &account); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if want := New(1000, DefaultCurrency); account.Balance != want {
+		t.Errorf("Balance = %v, want %v", account.Balance, want)
+	}
+
+	data, err := json.Marshal(account)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `{"Balance":{"amount":1000,"currency":"KRW"}}` {
+		t.Errorf("Marshal() = %s", data)
+	}
+}
+
+func TestMoney_UnmarshalLegacyFloatRejectsFraction(t *testing.T) {
+	var account struct {
+		Balance Money
+	}
+
+	if err := json.Unmarshal([]byte(`{"Balance":1000.4}`), &account); err == nil {
+		t.Fatalf("Unmarshal() = %v, want error", account.Balance)
+	}
+}
+
+func TestFromMajor(t *testing.T) {
+	got, err := FromMajor(12.34, "USD")
+	if err != nil {
+		t.Fatalf("FromMajor() error = %v", err)
+	}
+	if want := New(1234, "USD"); got != want {
+		t.Errorf("FromMajor() = %v, want %v", got, want)
+	}
+
+	if _, err := FromMajor(1.005, "USD"); err == nil {
+		t.Error("FromMajor(1.005 USD) error = nil, want error")
+	}
+}
@@ -0,0 +1,31 @@
+package money
+
+import (
+	"fmt"
+
+	ebank "ebank/api/v1"
+)
+
+func ToProto(m Money) *ebank.Money {
+	return &ebank.Money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+// FromProto 요청의 금액을 변환합니다. 통화가 비어 있으면 DefaultCurrency로 간주합니다.
+func FromProto(m *ebank.Money) (Money, error) {
+	if m == nil {
+		return Money{}, fmt.Errorf("amount is required")
+	}
+
+	currency := m.GetCurrency()
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if !IsSupportedCurrency(currency) {
+		return Money{}, fmt.Errorf("unsupported currency %q", currency)
+	}
+
+	return Money{Amount: m.GetAmount(), Currency: currency}, nil
+}
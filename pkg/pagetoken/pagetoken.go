@@ -0,0 +1,55 @@
+package pagetoken
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// token 다음 페이지를 이어서 조회할 위치입니다. 조회 조건이 바뀌면 이어 볼 수 없도록 조건의 해시를 함께 담습니다.
+type token struct {
+	Cursor json.RawMessage `json:"c"`
+	Filter string          `json:"f"`
+}
+
+// Encode cursor를 filter 조건과 묶어 URL에 그대로 쓸 수 있는 토큰으로 만듭니다.
+func Encode(cursor any, filter string) string {
+	data, _ := json.Marshal(cursor)
+	data, _ = json.Marshal(token{Cursor: data, Filter: filter})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode 토큰의 위치를 cursor에 읽어 들입니다.
+// 토큰이 손상되었거나 filter와 다른 조건으로 만든 토큰이면 false를 반환합니다.
+func Decode(value string, filter string, cursor any) bool {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return false
+	}
+
+	var decoded token
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Filter != filter {
+		return false
+	}
+
+	return json.Unmarshal(decoded.Cursor, cursor) == nil
+}
+
+// Filter excluded 필드(페이지 위치와 크기 등)를 뺀 조회 조건의 해시입니다.
+func Filter(req proto.Message, excluded ...string) string {
+	clone := proto.Clone(req)
+	fields := clone.ProtoReflect().Descriptor().Fields()
+	for _, name := range excluded {
+		if field := fields.ByName(protoreflect.Name(name)); field != nil {
+			clone.ProtoReflect().Clear(field)
+		}
+	}
+
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(clone)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,151 @@
+package sqlite
+
+// migrations 버전 순서대로 적용되는 스키마 변경입니다. 이미 배포된 항목은 수정하지 말고 새 항목을 추가합니다.
+// AUTOINCREMENT는 삭제된 ID를 재사용하지 않도록 시퀀스를 sqlite_sequence에 보관합니다.
+var migrations = [][]string{
+	// 1: 초기 스키마
+	{
+		`CREATE TABLE users (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			name         TEXT    NOT NULL,
+			birth        TEXT    NOT NULL,
+			phone_number TEXT    NOT NULL UNIQUE,
+			password     TEXT    NOT NULL,
+			is_deleted   INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE accounts (
+			id               INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_number   TEXT    NOT NULL,
+			customer_id      INTEGER NOT NULL,
+			balance_amount   INTEGER NOT NULL,
+			balance_currency TEXT    NOT NULL,
+			created_at       INTEGER NOT NULL
+		)`,
+		`CREATE INDEX idx_accounts_customer_id ON accounts (customer_id)`,
+		`CREATE INDEX idx_accounts_created_at ON accounts (created_at)`,
+		`CREATE TABLE transactions (
+			id                      INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id              INTEGER NOT NULL,
+			amount                  INTEGER NOT NULL,
+			currency                TEXT    NOT NULL,
+			transaction_type        TEXT    NOT NULL,
+			counterparty_account_id INTEGER NOT NULL DEFAULT 0,
+			memo                    TEXT    NOT NULL DEFAULT '',
+			created_at              INTEGER NOT NULL
+		)`,
+		`CREATE INDEX idx_transactions_account_id_created_at ON transactions (account_id, created_at)`,
+		`CREATE INDEX idx_transactions_created_at ON transactions (created_at)`,
+		`CREATE TABLE idempotency_keys (
+			key          TEXT    PRIMARY KEY,
+			method       TEXT    NOT NULL,
+			request_hash TEXT    NOT NULL,
+			response     BLOB    NOT NULL,
+			created_at   INTEGER NOT NULL
+		)`,
+		`CREATE TABLE journal_entries (
+			id              INTEGER PRIMARY KEY AUTOINCREMENT,
+			description     TEXT    NOT NULL,
+			transaction_ids TEXT    NOT NULL,
+			reversal_of     INTEGER NOT NULL DEFAULT 0,
+			created_at      INTEGER NOT NULL
+		)`,
+		`CREATE TABLE postings (
+			journal_entry_id INTEGER NOT NULL REFERENCES journal_entries (id),
+			seq              INTEGER NOT NULL,
+			account_code     TEXT    NOT NULL,
+			amount           INTEGER NOT NULL,
+			currency         TEXT    NOT NULL,
+			PRIMARY KEY (journal_entry_id, seq)
+		)`,
+		`CREATE INDEX idx_postings_account_code ON postings (account_code, journal_entry_id)`,
+	},
+	// 2: 사용자 역할
+	{
+		`ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'customer'`,
+	},
+	// 3: 로그인 세션 (리프레시 토큰 회전, 로그아웃)
+	{
+		`CREATE TABLE sessions (
+			id               TEXT    PRIMARY KEY,
+			user_id          INTEGER NOT NULL,
+			refresh_token_id TEXT    NOT NULL,
+			created_at       INTEGER NOT NULL,
+			expires_at       INTEGER NOT NULL,
+			revoked_at       INTEGER NOT NULL DEFAULT 0
+		)`,
+		`CREATE INDEX idx_sessions_user_id ON sessions (user_id)`,
+	},
+	// 4: 로그인 실패 횟수 (무차별 대입 방지)
+	{
+		`CREATE TABLE login_attempts (
+			key            TEXT    PRIMARY KEY,
+			failures       INTEGER NOT NULL,
+			last_failed_at INTEGER NOT NULL,
+			locked_until   INTEGER NOT NULL DEFAULT 0
+		)`,
+	},
+	// 5: TOTP 2단계 인증
+	{
+		`ALTER TABLE users ADD COLUMN totp_secret TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE users ADD COLUMN totp_enabled INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN totp_last_step INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE users ADD COLUMN recovery_codes TEXT NOT NULL DEFAULT '[]'`,
+	},
+	// 6: 거래 내역 페이지 조회. 같은 시각의 거래도 순서가 정해지도록 id까지 인덱스에 넣습니다.
+	{
+		`DROP INDEX idx_transactions_account_id_created_at`,
+		`CREATE INDEX idx_transactions_account_id_created_at_id ON transactions (account_id, created_at, id)`,
+	},
+	// 7: 계좌 상태 (목록 조회 조건)
+	{
+		`ALTER TABLE accounts ADD COLUMN status TEXT NOT NULL DEFAULT 'ACTIVE'`,
+	},
+	// 8: 서버가 발급하는 계좌번호. 예전에 자유롭게 입력해 겹친 계좌번호는 뒤에 ID를 붙여 구분합니다.
+	{
+		`UPDATE accounts SET account_number = account_number || '-' || id
+		WHERE id NOT IN (SELECT MIN(id) FROM accounts GROUP BY account_number)`,
+		`CREATE UNIQUE INDEX idx_accounts_account_number ON accounts (account_number)`,
+		`CREATE TABLE account_number_sequences (
+			prefix     TEXT    PRIMARY KEY,
+			last_value INTEGER NOT NULL
+		)`,
+	},
+	// 9: 계좌 상태를 바꾼 이유와 시각
+	{
+		`ALTER TABLE accounts ADD COLUMN status_reason TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE accounts ADD COLUMN status_changed_at INTEGER NOT NULL DEFAULT 0`,
+	},
+	// 10: 계좌의 상품, 약정 이율, 정기예금 만기일과 연결 계좌
+	{
+		`ALTER TABLE accounts ADD COLUMN product_id TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE accounts ADD COLUMN interest_rate INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE accounts ADD COLUMN matures_at INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE accounts ADD COLUMN linked_account_id INTEGER NOT NULL DEFAULT 0`,
+		`CREATE INDEX idx_accounts_matures_at ON accounts (matures_at)`,
+	},
+	// 11: 계좌 버전. 여러 프로세스가 같은 계좌를 읽고 쓸 때 나중에 저장한 쪽이 먼저 저장한 잔액을 덮어쓰지 않게 합니다.
+	{
+		`ALTER TABLE accounts ADD COLUMN version INTEGER NOT NULL DEFAULT 0`,
+	},
+	// 12: 사용자별 멱등성 키와 처리 전에 예약한 키의 상태. 예전 키는 누구의 키인지 알 수 없어 사용자 0으로 옮깁니다.
+	{
+		`CREATE TABLE idempotency_keys_by_user (
+			user_id      INTEGER NOT NULL,
+			key          TEXT    NOT NULL,
+			method       TEXT    NOT NULL,
+			request_hash TEXT    NOT NULL,
+			status       TEXT    NOT NULL,
+			response     BLOB    NOT NULL,
+			created_at   INTEGER NOT NULL,
+			PRIMARY KEY (user_id, key)
+		)`,
+		`INSERT INTO idempotency_keys_by_user (user_id, key, method, request_hash, status, response, created_at)
+		SELECT 0, key, method, request_hash, 'COMPLETED', response, created_at FROM idempotency_keys`,
+		`DROP TABLE idempotency_keys`,
+		`ALTER TABLE idempotency_keys_by_user RENAME TO idempotency_keys`,
+	},
+	// 13: 보관 기간이 지난 멱등성 키를 지울 때 쓰는 인덱스
+	{
+		`CREATE INDEX idx_idempotency_keys_created_at ON idempotency_keys (created_at)`,
+	},
+}
@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // cgo 없이 동작하는 순수 Go 드라이버
+)
+
+// DB *sql.DB와 *sql.Tx가 함께 갖는 메서드입니다. 저장소를 트랜잭션 안에서도 같은 코드로 쓰게 합니다.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Open SQLite 데이터베이스를 열고 아직 적용되지 않은 스키마 마이그레이션을 실행합니다.
+func Open(path string) (*sql.DB, error) {
+	// 트랜잭션은 시작할 때 쓰기 잠금을 잡습니다. 읽은 뒤에 쓰기로 올리다 다른 프로세스와 부딪혀
+	// busy_timeout을 기다리지 않고 SQLITE_BUSY로 실패하는 일을 막습니다.
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_txlock=immediate", path)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite는 쓰기 트랜잭션을 하나만 허용하므로 연결을 하나로 제한해 SQLITE_BUSY를 피합니다.
+	db.SetMaxOpenConns(1)
+
+	if err := Migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Migrate schema_migrations 테이블에 기록된 버전 이후의 마이그레이션을 순서대로 적용합니다.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return err
+	}
+
+	var current int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return err
+	}
+
+	for i, migration := range migrations {
+		version := i + 1
+		if version <= current {
+			continue
+		}
+
+		if err := WithTx(ctx, db, func(tx DB) error {
+			for _, statement := range migration {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, version, time.Now().Unix())
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// WithTx fn을 하나의 트랜잭션으로 실행합니다. fn이 에러를 반환하면 롤백합니다.
+// db가 이미 트랜잭션이면 새로 시작하지 않고 그 트랜잭션 안에서 실행하며, 커밋과 롤백은 바깥 트랜잭션에 맡깁니다.
+func WithTx(ctx context.Context, db DB, fn func(tx DB) error) error {
+	if tx, ok := db.(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	conn, ok := db.(*sql.DB)
+	if !ok {
+		return fmt.Errorf("cannot begin a transaction on %T", db)
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ToUnixNano 시각을 정렬 가능한 정수로 저장합니다. 0은 시각 없음입니다.
+func ToUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixNano()
+}
+
+func FromUnixNano(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nanos)
+}
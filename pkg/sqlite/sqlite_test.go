@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpen_MigratesOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ebank.db")
+
+	for i := 0; i < 2; i++ {
+		db, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+
+		var applied int
+		if err := db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+			t.Fatalf("failed to count migrations: %v", err)
+		}
+		if applied != len(migrations) {
+			t.Errorf("applied migrations = %d, want %d", applied, len(migrations))
+		}
+		db.Close()
+	}
+}
+
+func TestWithTx_RollbackOnError(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "ebank.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	err = WithTx(ctx, db, func(tx DB) error {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO users (name, birth, phone_number, password) VALUES ('a', '', '01012345678', '')`); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO users (name, birth, phone_number, password) VALUES ('b', '', '01012345678', '')`)
+		return err
+	})
+	if err == nil {
+		t.Fatal("WithTx() error = nil, want unique constraint violation")
+	}
+
+	var users int
+	db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&users)
+	if users != 0 {
+		t.Errorf("users after rollback = %d, want 0", users)
+	}
+}
+
+func TestWithTx_Nested(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "ebank.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer db.Close()
+
+	// 안쪽 WithTx는 바깥 트랜잭션에 합쳐지므로 바깥이 실패하면 함께 롤백됩니다.
+	ctx := context.Background()
+	err = WithTx(ctx, db, func(tx DB) error {
+		if err := WithTx(ctx, tx, func(tx DB) error {
+			_, err := tx.ExecContext(ctx, `INSERT INTO users (name, birth, phone_number, password) VALUES ('a', '', '01012345678', '')`)
+			return err
+		}); err != nil {
+			return err
+		}
+		return errors.New("failed after the inner transaction")
+	})
+	if err == nil {
+		t.Fatal("WithTx() error = nil, want the outer error")
+	}
+
+	var users int
+	db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`).Scan(&users)
+	if users != 0 {
+		t.Errorf("users after rollback = %d, want 0", users)
+	}
+}
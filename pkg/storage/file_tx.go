@@ -0,0 +1,384 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"ebank/pkg/wal"
+	accountModel "ebank/services/account/model"
+	accountRepository "ebank/services/account/repository"
+	accountService "ebank/services/account/service"
+	ledgerModel "ebank/services/ledger/model"
+	ledgerRepository "ebank/services/ledger/repository"
+	ledgerService "ebank/services/ledger/service"
+	transactionModel "ebank/services/transaction/model"
+	transactionRepository "ebank/services/transaction/repository"
+	transactionService "ebank/services/transaction/service"
+)
+
+// fileTxRecord 파일 저장소 트랜잭션 하나로 바뀐 계좌, 거래, 분개입니다. 트랜잭션 로그에 한 줄로 기록하므로 쓰는 도중에 멈추면
+// 다시 시작할 때 통째로 버려지고, 다 쓴 뒤에 각 저장소에 반영하다 멈추면 다시 시작할 때 마저 반영합니다.
+type fileTxRecord struct {
+	Accounts       []accountModel.Account         `json:"accounts,omitempty"`
+	Transactions   []transactionModel.Transaction `json:"transactions,omitempty"`
+	JournalEntries []ledgerModel.JournalEntry     `json:"journal_entries,omitempty"`
+}
+
+// fileAccountStore 파일 계좌 저장소가 트랜잭션을 반영하려고 더 제공하는 메서드입니다.
+type fileAccountStore interface {
+	accountService.AccountRepository
+	CommitAccounts(accounts []accountModel.Account, commit func() error) error
+	ApplyAccounts(accounts []accountModel.Account) error
+}
+
+type fileTransactionStore interface {
+	transactionService.TransactionRepository
+	ReserveTransactionID() int64
+	ApplyTransactions(transactions []transactionModel.Transaction) error
+}
+
+type fileJournalEntryStore interface {
+	ledgerService.JournalEntryRepository
+	ReserveJournalEntryID() int64
+	ApplyJournalEntries(entries []ledgerModel.JournalEntry) error
+}
+
+// openTxLog 트랜잭션 로그를 열고, 기록했지만 저장소에 반영했는지 모르는 변경을 pendingTx로 읽습니다.
+func (s *Storage) openTxLog() error {
+	txLog, err := wal.Open(s.cfg.TxLogPath, wal.DefaultSnapshotInterval)
+	if err != nil {
+		return err
+	}
+
+	err = txLog.Replay(func(int64, []byte) error {
+		return nil
+	}, func(record wal.Record) error {
+		if record.Op != wal.OpPut {
+			return fmt.Errorf("unknown log operation %q", record.Op)
+		}
+
+		var tx fileTxRecord
+		if err := json.Unmarshal(record.Data, &tx); err != nil {
+			return err
+		}
+		s.pendingTx = append(s.pendingTx, tx)
+		return nil
+	})
+	if err != nil {
+		txLog.Close()
+		return err
+	}
+
+	s.txLog = txLog
+	return nil
+}
+
+// fileAccounts 파일 계좌 저장소를 한 번만 열고, 열 때 트랜잭션 로그에 남은 계좌 변경을 반영합니다.
+func (s *Storage) fileAccounts() (fileAccountStore, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.accounts != nil {
+		return s.accounts, nil
+	}
+
+	repository, err := accountRepository.NewAccountFileRepository(s.cfg.AccountTablePath)
+	if err != nil {
+		return nil, err
+	}
+	store, ok := repository.(fileAccountStore)
+	if !ok {
+		return nil, fmt.Errorf("%T cannot apply file transactions", repository)
+	}
+	for _, tx := range s.pendingTx {
+		if err := store.ApplyAccounts(tx.Accounts); err != nil {
+			return nil, err
+		}
+	}
+
+	s.accounts = store
+	return store, nil
+}
+
+// fileTransactions 파일 거래 저장소를 한 번만 열고, 열 때 트랜잭션 로그에 남은 거래를 반영합니다.
+func (s *Storage) fileTransactions() (fileTransactionStore, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.transactions != nil {
+		return s.transactions, nil
+	}
+
+	repository, err := transactionRepository.NewTransactionFileRepository(s.cfg.TransactionTablePath)
+	if err != nil {
+		return nil, err
+	}
+	store, ok := repository.(fileTransactionStore)
+	if !ok {
+		return nil, fmt.Errorf("%T cannot apply file transactions", repository)
+	}
+	for _, tx := range s.pendingTx {
+		if err := store.ApplyTransactions(tx.Transactions); err != nil {
+			return nil, err
+		}
+	}
+
+	s.transactions = store
+	return store, nil
+}
+
+// fileJournalEntries 파일 원장 저장소를 한 번만 열고, 열 때 트랜잭션 로그에 남은 분개를 반영합니다.
+func (s *Storage) fileJournalEntries() (fileJournalEntryStore, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.journalEntries != nil {
+		return s.journalEntries, nil
+	}
+
+	repository, err := ledgerRepository.NewJournalEntryFileRepository(s.cfg.LedgerTablePath)
+	if err != nil {
+		return nil, err
+	}
+	store, ok := repository.(fileJournalEntryStore)
+	if !ok {
+		return nil, fmt.Errorf("%T cannot apply file transactions", repository)
+	}
+	for _, tx := range s.pendingTx {
+		if err := store.ApplyJournalEntries(tx.JournalEntries); err != nil {
+			return nil, err
+		}
+	}
+
+	s.journalEntries = store
+	return store, nil
+}
+
+// runFileTx fn이 쓴 내용을 모아 두었다가 트랜잭션 로그에 레코드 하나로 기록하고 각 저장소에 반영합니다.
+func (s *Storage) runFileTx(ctx context.Context, fn func(repos transactionService.Repositories) error) error {
+	if s.txLog == nil {
+		return errors.New("file storage transactions need a transaction log path")
+	}
+	accounts, err := s.fileAccounts()
+	if err != nil {
+		return err
+	}
+	transactions, err := s.fileTransactions()
+	if err != nil {
+		return err
+	}
+	journalEntries, err := s.fileJournalEntries()
+	if err != nil {
+		return err
+	}
+
+	tx := &fileTx{accounts: make(map[int64]accountModel.Account)}
+	if err := fn(transactionService.Repositories{
+		Accounts:     &fileTxAccountRepository{AccountRepository: accounts, tx: tx},
+		Transactions: &fileTxTransactionRepository{TransactionRepository: transactions, store: transactions, tx: tx},
+		Ledger:       ledgerService.NewLedger(&fileTxJournalEntryRepository{JournalEntryRepository: journalEntries, store: journalEntries, tx: tx}),
+	}); err != nil {
+		return err
+	}
+
+	return s.commitFileTx(tx.record())
+}
+
+// commitFileTx 계좌가 그사이 바뀌지 않았으면 record를 트랜잭션 로그에 기록하고 각 저장소에 반영합니다.
+// 로그에 기록한 뒤로는 커밋된 것이므로, 저장소에 반영하지 못해도 에러를 반환하지 않고 다음 커밋이나 다시 시작할 때 마저 반영합니다.
+func (s *Storage) commitFileTx(record fileTxRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// 앞서 반영하지 못한 변경이 있으면 그 위에 새 변경을 쌓지 않습니다.
+	if err := s.applyPendingTx(); err != nil {
+		return err
+	}
+
+	committed := false
+	err := s.accounts.CommitAccounts(record.Accounts, func() error {
+		if err := s.txLog.Append(wal.OpPut, record); err != nil {
+			return err
+		}
+		committed = true
+		s.pendingTx = append(s.pendingTx, record)
+		return nil
+	})
+	if !committed {
+		return err
+	}
+
+	if err == nil {
+		err = s.applyPendingTx()
+	}
+	if err != nil {
+		log.Printf("failed to apply committed file transaction: %v", err)
+	}
+
+	return nil
+}
+
+// applyPendingTx pendingTx를 모든 저장소에 반영하고 트랜잭션 로그를 비웁니다. 이미 반영한 변경은 저장소가 건너뜁니다.
+// s.mutex를 잡은 상태에서 호출해야 합니다.
+func (s *Storage) applyPendingTx() error {
+	if len(s.pendingTx) == 0 {
+		return nil
+	}
+
+	for _, tx := range s.pendingTx {
+		if err := s.accounts.ApplyAccounts(tx.Accounts); err != nil {
+			return err
+		}
+		if err := s.transactions.ApplyTransactions(tx.Transactions); err != nil {
+			return err
+		}
+		if err := s.journalEntries.ApplyJournalEntries(tx.JournalEntries); err != nil {
+			return err
+		}
+	}
+
+	if err := s.txLog.Reset(); err != nil {
+		return err
+	}
+
+	s.pendingTx = nil
+	return nil
+}
+
+// fileTx 커밋할 때까지 트랜잭션에서 쓴 내용을 모아 둡니다. RunInTx의 fn을 실행하는 고루틴만 씁니다.
+type fileTx struct {
+	accounts       map[int64]accountModel.Account
+	accountIDs     []int64
+	transactions   []transactionModel.Transaction
+	journalEntries []ledgerModel.JournalEntry
+}
+
+func (tx *fileTx) putAccount(account accountModel.Account) {
+	if _, exists := tx.accounts[account.ID]; !exists {
+		tx.accountIDs = append(tx.accountIDs, account.ID)
+	}
+	tx.accounts[account.ID] = account
+}
+
+func (tx *fileTx) record() fileTxRecord {
+	record := fileTxRecord{
+		Transactions:   tx.transactions,
+		JournalEntries: tx.journalEntries,
+	}
+	for _, id := range tx.accountIDs {
+		record.Accounts = append(record.Accounts, tx.accounts[id])
+	}
+
+	return record
+}
+
+// errNotInFileTx 파일 저장소 트랜잭션에서 지원하지 않는 쓰기입니다.
+func errNotInFileTx(method string) error {
+	return fmt.Errorf("%s is not supported in a file storage transaction", method)
+}
+
+// fileTxAccountRepository 계좌 저장을 트랜잭션에 모아 둡니다. ID로 읽으면 모아 둔 변경이 보이지만 목록 조회에는 커밋한 내용만 보입니다.
+type fileTxAccountRepository struct {
+	accountService.AccountRepository
+	tx *fileTx
+}
+
+func (r *fileTxAccountRepository) GetAccountByID(ctx context.Context, id int64) (*accountModel.Account, error) {
+	if account, exists := r.tx.accounts[id]; exists {
+		return &account, nil
+	}
+
+	return r.AccountRepository.GetAccountByID(ctx, id)
+}
+
+// UpdateAccount 버전은 여기서 한 번, 커밋할 때 저장된 계좌와 한 번 더 확인합니다.
+// 한 트랜잭션에서 같은 계좌를 여러 번 저장해도 버전은 한 번만 올려, 커밋할 때 저장된 버전의 다음 버전인지로 확인할 수 있게 합니다.
+func (r *fileTxAccountRepository) UpdateAccount(ctx context.Context, account *accountModel.Account) error {
+	current, err := r.GetAccountByID(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	if current.Version != account.Version {
+		return fmt.Errorf("account with ID %d: %w", account.ID, accountService.ErrAccountChanged)
+	}
+
+	updated := *account
+	if _, staged := r.tx.accounts[account.ID]; !staged {
+		updated.Version++
+	}
+	r.tx.putAccount(updated)
+	account.Version = updated.Version
+
+	return nil
+}
+
+func (r *fileTxAccountRepository) CreateAccount(context.Context, accountModel.Account) (accountModel.Account, error) {
+	return accountModel.Account{}, errNotInFileTx("CreateAccount")
+}
+
+func (r *fileTxAccountRepository) DeleteAccount(context.Context, int64) error {
+	return errNotInFileTx("DeleteAccount")
+}
+
+func (r *fileTxAccountRepository) NextAccountNumberSequence(context.Context, string) (int64, error) {
+	return 0, errNotInFileTx("NextAccountNumberSequence")
+}
+
+// fileTxTransactionRepository 새 거래를 트랜잭션에 모아 둡니다. ID는 미리 발급하므로 커밋하지 못한 ID는 건너뜁니다.
+type fileTxTransactionRepository struct {
+	transactionService.TransactionRepository
+	store fileTransactionStore
+	tx    *fileTx
+}
+
+func (r *fileTxTransactionRepository) CreateTransaction(ctx context.Context, transaction transactionModel.Transaction) (transactionModel.Transaction, error) {
+	transaction.ID = r.store.ReserveTransactionID()
+	r.tx.transactions = append(r.tx.transactions, transaction)
+
+	return transaction, nil
+}
+
+func (r *fileTxTransactionRepository) GetTransactionByID(ctx context.Context, id int64) (transactionModel.Transaction, error) {
+	for _, transaction := range r.tx.transactions {
+		if transaction.ID == id {
+			return transaction, nil
+		}
+	}
+
+	return r.TransactionRepository.GetTransactionByID(ctx, id)
+}
+
+func (r *fileTxTransactionRepository) UpdateTransaction(context.Context, transactionModel.Transaction) error {
+	return errNotInFileTx("UpdateTransaction")
+}
+
+func (r *fileTxTransactionRepository) DeleteTransaction(context.Context, int64) error {
+	return errNotInFileTx("DeleteTransaction")
+}
+
+// fileTxJournalEntryRepository 새 분개를 트랜잭션에 모아 둡니다.
+type fileTxJournalEntryRepository struct {
+	ledgerService.JournalEntryRepository
+	store fileJournalEntryStore
+	tx    *fileTx
+}
+
+func (r *fileTxJournalEntryRepository) CreateJournalEntry(ctx context.Context, entry ledgerModel.JournalEntry) (ledgerModel.JournalEntry, error) {
+	entry.ID = r.store.ReserveJournalEntryID()
+	r.tx.journalEntries = append(r.tx.journalEntries, entry)
+
+	return entry, nil
+}
+
+func (r *fileTxJournalEntryRepository) GetJournalEntryByID(ctx context.Context, id int64) (ledgerModel.JournalEntry, error) {
+	for _, entry := range r.tx.journalEntries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+
+	return r.JournalEntryRepository.GetJournalEntryByID(ctx, id)
+}
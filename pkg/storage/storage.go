@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"ebank/pkg/config"
+	"ebank/pkg/feed"
+	"ebank/pkg/sqlite"
+	"ebank/pkg/wal"
+	accountRepository "ebank/services/account/repository"
+	accountService "ebank/services/account/service"
+	ledgerRepository "ebank/services/ledger/repository"
+	ledgerService "ebank/services/ledger/service"
+	transactionRepository "ebank/services/transaction/repository"
+	transactionService "ebank/services/transaction/service"
+	userRepository "ebank/services/user/repository"
+	userService "ebank/services/user/service"
+)
+
+// Storage config.DBConfig.Driver에 따라 파일 또는 SQLite 저장소를 만듭니다.
+// 파일 저장소는 같은 파일을 두 번 열면 안 되므로 저장소마다 한 번만 호출해 공유합니다.
+type Storage struct {
+	cfg     config.DBConfig
+	db      *sql.DB
+	changes *feed.Feed
+
+	// 아래는 파일 저장소에서만 씁니다. RunInTx가 서비스와 같은 저장소에 반영하도록 계좌, 거래, 원장 저장소는 한 번만 열어 둡니다.
+	mutex          sync.Mutex
+	accounts       fileAccountStore
+	transactions   fileTransactionStore
+	journalEntries fileJournalEntryStore
+	txLog          *wal.Log
+	// pendingTx 트랜잭션 로그에 기록했지만 모든 저장소에 반영했는지 알 수 없는 변경입니다.
+	pendingTx []fileTxRecord
+}
+
+func Open(cfg config.DBConfig) (*Storage, error) {
+	storage := &Storage{cfg: cfg, changes: feed.New()}
+
+	if cfg.Driver == config.DBDriverSQLite {
+		db, err := sqlite.Open(cfg.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+		storage.db = db
+	} else if cfg.TxLogPath != "" {
+		if err := storage.openTxLog(); err != nil {
+			return nil, err
+		}
+	}
+
+	return storage, nil
+}
+
+func (s *Storage) UserRepository() (userService.UserRepository, error) {
+	if s.db != nil {
+		return userRepository.NewUserSQLiteRepository(s.db), nil
+	}
+
+	return userRepository.NewUserFileRepository(s.cfg.UserTablePath)
+}
+
+func (s *Storage) SessionRepository() (userService.SessionRepository, error) {
+	if s.db != nil {
+		return userRepository.NewSessionSQLiteRepository(s.db), nil
+	}
+
+	return userRepository.NewSessionFileRepository(s.cfg.SessionTablePath)
+}
+
+func (s *Storage) LoginAttemptRepository() (userService.LoginAttemptRepository, error) {
+	if s.db != nil {
+		return userRepository.NewLoginAttemptSQLiteRepository(s.db), nil
+	}
+
+	return userRepository.NewLoginAttemptFileRepository(s.cfg.LoginAttemptTablePath)
+}
+
+// Changes 이 저장소로 만든 계좌 저장소의 변경 알림입니다. 키는 계좌 ID입니다.
+// 거래는 저장소가 아니라 거래 서비스가 입출금과 이체를 모두 반영한 뒤에 알립니다.
+// 같은 프로세스의 변경만 알리므로, 다른 프로세스가 같은 SQLite 파일을 고치면 구독자가 직접 다시 읽어야 합니다.
+func (s *Storage) Changes() *feed.Feed {
+	return s.changes
+}
+
+func (s *Storage) AccountRepository() (accountService.AccountRepository, error) {
+	if s.db != nil {
+		return accountRepository.NewAccountFeedRepository(accountRepository.NewAccountSQLiteRepository(s.db), s.changes), nil
+	}
+
+	repository, err := s.fileAccounts()
+	if err != nil {
+		return nil, err
+	}
+
+	return accountRepository.NewAccountFeedRepository(repository, s.changes), nil
+}
+
+func (s *Storage) TransactionRepository() (transactionService.TransactionRepository, error) {
+	if s.db != nil {
+		return transactionRepository.NewTransactionSQLiteRepository(s.db), nil
+	}
+
+	return s.fileTransactions()
+}
+
+func (s *Storage) IdempotencyRepository() (transactionService.IdempotencyRepository, error) {
+	if s.db != nil {
+		return transactionRepository.NewIdempotencySQLiteRepository(s.db), nil
+	}
+
+	return transactionRepository.NewIdempotencyFileRepository(s.cfg.IdempotencyTablePath)
+}
+
+func (s *Storage) JournalEntryRepository() (ledgerService.JournalEntryRepository, error) {
+	if s.db != nil {
+		return ledgerRepository.NewJournalEntrySQLiteRepository(s.db), nil
+	}
+
+	return s.fileJournalEntries()
+}
+
+// RunInTx transactionService.UnitOfWork를 구현합니다. SQLite는 하나의 트랜잭션으로 실행하고,
+// 파일 저장소는 fn이 쓴 내용을 트랜잭션 로그에 레코드 하나로 기록한 뒤 각 저장소에 반영합니다.
+func (s *Storage) RunInTx(ctx context.Context, fn func(repos transactionService.Repositories) error) error {
+	if s.db != nil {
+		return sqlite.WithTx(ctx, s.db, func(tx sqlite.DB) error {
+			return fn(transactionService.Repositories{
+				Accounts:     accountRepository.NewAccountSQLiteRepository(tx),
+				Transactions: transactionRepository.NewTransactionSQLiteRepository(tx),
+				Ledger:       ledgerService.NewLedger(ledgerRepository.NewJournalEntrySQLiteRepository(tx)),
+			})
+		})
+	}
+
+	return s.runFileTx(ctx, fn)
+}
+
+func (s *Storage) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	if s.txLog != nil {
+		return s.txLog.Close()
+	}
+
+	return nil
+}
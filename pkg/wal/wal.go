@@ -0,0 +1,263 @@
+package wal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"strconv"
+	"sync"
+
+	"ebank/pkg/atomic_file"
+)
+
+const (
+	OpPut    = "put"    // 레코드 전체를 저장 (생성/수정)
+	OpDelete = "delete" // ID로 레코드 삭제
+
+	// LegacySequence 시퀀스 헤더 없이 배열만 저장된 예전 형식 스냅샷을 나타냅니다.
+	LegacySequence int64 = -1
+
+	// DefaultSnapshotInterval 이 개수만큼 로그가 쌓이면 스냅샷을 만들고 로그를 비웁니다.
+	DefaultSnapshotInterval = 1000
+
+	logSuffix = ".log"
+)
+
+// Record 로그 한 줄에 기록되는 변경 내역입니다. 같은 레코드를 여러 번 재생해도 결과가 같도록
+// 항상 레코드 전체(put) 또는 ID(delete)를 기록합니다.
+type Record struct {
+	Op   string          `json:"op"`
+	Data json.RawMessage `json:"data"`
+}
+
+// snapshotFile 스냅샷 파일 형식입니다. Sequence는 지금까지 발급한 가장 큰 ID로,
+// 레코드가 삭제되어도 줄어들지 않습니다.
+type snapshotFile struct {
+	Sequence int64           `json:"sequence"`
+	Records  json.RawMessage `json:"records"`
+}
+
+// Log 스냅샷 파일과 추가 전용 로그 파일로 이루어진 저장소입니다.
+// 로그의 각 줄은 "<crc32 16진수> <JSON 레코드>" 형식입니다.
+type Log struct {
+	mutex            sync.Mutex
+	snapshotPath     string
+	file             *os.File
+	appended         int
+	snapshotInterval int
+}
+
+// Open snapshotPath 옆에 snapshotPath + ".log" 로그 파일을 엽니다.
+func Open(snapshotPath string, snapshotInterval int) (*Log, error) {
+	file, err := os.OpenFile(snapshotPath+logSuffix, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshotInterval <= 0 {
+		snapshotInterval = DefaultSnapshotInterval
+	}
+
+	return &Log{
+		snapshotPath:     snapshotPath,
+		file:             file,
+		snapshotInterval: snapshotInterval,
+	}, nil
+}
+
+// Replay 스냅샷을 읽은 뒤 로그 레코드를 순서대로 적용합니다.
+// 예전 배열 형식 스냅샷이면 sequence로 LegacySequence를 넘깁니다.
+// 마지막 줄이 쓰다 만 상태라면 잘라내고, 중간 줄이 손상되었다면 에러를 반환합니다.
+func (l *Log) Replay(loadSnapshot func(sequence int64, records []byte) error, apply func(record Record) error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	data, err := atomic_file.ReadFile(l.snapshotPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := l.loadSnapshot(bytes.TrimSpace(data), loadSnapshot); err != nil {
+		return fmt.Errorf("failed to load snapshot %s: %w", l.snapshotPath, err)
+	}
+
+	logData, err := os.ReadFile(l.file.Name())
+	if err != nil {
+		return err
+	}
+
+	offset := 0
+	for lineNumber := 1; offset < len(logData); lineNumber++ {
+		end := bytes.IndexByte(logData[offset:], '\n')
+		if end < 0 {
+			// 개행이 없는 마지막 줄은 쓰는 도중 중단된 레코드입니다.
+			return l.truncate(int64(offset))
+		}
+
+		record, err := decodeLine(logData[offset : offset+end])
+		if err != nil {
+			if offset+end+1 == len(logData) {
+				return l.truncate(int64(offset))
+			}
+			return fmt.Errorf("%s:%d: %w", l.file.Name(), lineNumber, err)
+		}
+
+		if err := apply(record); err != nil {
+			return fmt.Errorf("%s:%d: %w", l.file.Name(), lineNumber, err)
+		}
+
+		l.appended++
+		offset += end + 1
+	}
+
+	return nil
+}
+
+// Append 레코드를 로그 끝에 기록하고 fsync합니다.
+func (l *Log) Append(op string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(Record{Op: op, Data: data})
+	if err != nil {
+		return err
+	}
+
+	line := make([]byte, 0, len(payload)+10)
+	line = append(line, fmt.Sprintf("%08x ", crc32.ChecksumIEEE(payload))...)
+	line = append(line, payload...)
+	line = append(line, '\n')
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, err := l.file.Write(line); err != nil {
+		return err
+	}
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+
+	l.appended++
+	return nil
+}
+
+// NeedsSnapshot 스냅샷 이후 쌓인 로그가 스냅샷 주기를 넘었는지 알려줍니다.
+func (l *Log) NeedsSnapshot() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.appended >= l.snapshotInterval
+}
+
+// Snapshot 전체 레코드와 시퀀스를 스냅샷 파일에 원자적으로 쓰고 로그를 비웁니다.
+// 스냅샷 후 로그를 비우기 전에 중단되어도 레코드는 재생해도 안전하므로 결과가 같습니다.
+func (l *Log) Snapshot(sequence int64, records []byte) error {
+	data, err := json.Marshal(snapshotFile{Sequence: sequence, Records: records})
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := atomic_file.WriteFile(l.snapshotPath, data, 0644); err != nil {
+		return err
+	}
+
+	if err := l.truncate(0); err != nil {
+		return err
+	}
+
+	l.appended = 0
+	return nil
+}
+
+// Reset 스냅샷을 만들지 않고 로그를 비웁니다. 기록한 레코드를 모두 다른 곳에 반영해 더는 재생할 필요가 없을 때 씁니다.
+func (l *Log) Reset() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if err := l.truncate(0); err != nil {
+		return err
+	}
+
+	l.appended = 0
+	return nil
+}
+
+func (l *Log) Close() error {
+	return l.file.Close()
+}
+
+// CheckSequence 스냅샷 레코드의 가장 큰 ID가 저장된 시퀀스를 넘지 않는지 확인하고
+// 이어서 ID를 발급할 기준 시퀀스를 돌려줍니다. 예전 형식이면 가장 큰 ID를 시퀀스로 씁니다.
+func CheckSequence(sequence, maxID int64) (int64, error) {
+	if sequence == LegacySequence {
+		return maxID, nil
+	}
+
+	if maxID > sequence {
+		return 0, fmt.Errorf("record ID %d exceeds persisted sequence %d", maxID, sequence)
+	}
+
+	return sequence, nil
+}
+
+func (l *Log) loadSnapshot(data []byte, load func(sequence int64, records []byte) error) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if data[0] == '[' {
+		return load(LegacySequence, data)
+	}
+
+	var snapshot snapshotFile
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	if snapshot.Sequence < 0 {
+		return fmt.Errorf("invalid sequence %d", snapshot.Sequence)
+	}
+
+	records := snapshot.Records
+	if len(records) == 0 {
+		records = []byte("[]")
+	}
+
+	return load(snapshot.Sequence, records)
+}
+
+func (l *Log) truncate(size int64) error {
+	if err := l.file.Truncate(size); err != nil {
+		return err
+	}
+
+	return l.file.Sync()
+}
+
+func decodeLine(line []byte) (Record, error) {
+	checksum, payload, ok := bytes.Cut(line, []byte(" "))
+	if !ok {
+		return Record{}, fmt.Errorf("malformed log record")
+	}
+
+	want, err := strconv.ParseUint(string(checksum), 16, 32)
+	if err != nil {
+		return Record{}, fmt.Errorf("malformed log checksum: %w", err)
+	}
+	if crc32.ChecksumIEEE(payload) != uint32(want) {
+		return Record{}, fmt.Errorf("log record checksum mismatch")
+	}
+
+	var record Record
+	if err := json.Unmarshal(payload, &record); err != nil {
+		return Record{}, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,264 @@
+package wal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type item struct {
+	ID   int64
+	Name string
+}
+
+// replay 스냅샷과 로그를 재생해 ID별 상태를 돌려줍니다.
+func replay(t *testing.T, l *Log) (map[int64]item, error) {
+	t.Helper()
+
+	items := make(map[int64]item)
+	err := l.Replay(func(_ int64, data []byte) error {
+		var snapshot []item
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return err
+		}
+		for _, it := range snapshot {
+			items[it.ID] = it
+		}
+		return nil
+	}, func(record Record) error {
+		switch record.Op {
+		case OpPut:
+			var it item
+			if err := json.Unmarshal(record.Data, &it); err != nil {
+				return err
+			}
+			items[it.ID] = it
+		case OpDelete:
+			var id int64
+			if err := json.Unmarshal(record.Data, &id); err != nil {
+				return err
+			}
+			delete(items, id)
+		}
+		return nil
+	})
+	return items, err
+}
+
+func TestLog_AppendAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "item.json")
+
+	l, err := Open(path, 10)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	l.Append(OpPut, item{ID: 1, Name: "a"})
+	l.Append(OpPut, item{ID: 2, Name: "b"})
+	l.Append(OpPut, item{ID: 1, Name: "c"})
+	l.Append(OpDelete, int64(2))
+	l.Close()
+
+	l, _ = Open(path, 10)
+	defer l.Close()
+
+	items, err := replay(t, l)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if len(items) != 1 || items[1].Name != "c" {
+		t.Errorf("Replay() = %v, want only {1 c}", items)
+	}
+}
+
+func TestLog_SnapshotTruncatesLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "item.json")
+
+	l, _ := Open(path, 2)
+	l.Append(OpPut, item{ID: 1, Name: "a"})
+	if l.NeedsSnapshot() {
+		t.Fatalf("NeedsSnapshot() = true after 1 record, want false")
+	}
+	l.Append(OpPut, item{ID: 2, Name: "b"})
+	if !l.NeedsSnapshot() {
+		t.Fatalf("NeedsSnapshot() = false after 2 records, want true")
+	}
+
+	if err := l.Snapshot(2, []byte(`[{"ID":1,"Name":"a"},{"ID":2,"Name":"b"}]`)); err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if info, _ := os.Stat(path + logSuffix); info.Size() != 0 {
+		t.Errorf("log size after Snapshot() = %d, want 0", info.Size())
+	}
+	l.Append(OpPut, item{ID: 3, Name: "c"})
+	l.Close()
+
+	l, _ = Open(path, 2)
+	defer l.Close()
+
+	items, err := replay(t, l)
+	if err != nil || len(items) != 3 {
+		t.Errorf("Replay() = %v, %v, want 3 items", items, err)
+	}
+}
+
+func TestLog_Replay_Corruption(t *testing.T) {
+	tests := []struct {
+		name     string
+		corrupt  func(data []byte) []byte
+		wantLen  int
+		wantErr  bool
+		wantSize bool // true면 손상된 꼬리를 잘라내 로그가 처음 두 줄만 남아야 함
+	}{
+		{
+			name: "쓰다 만 마지막 줄은 잘라냄",
+			corrupt: func(data []byte) []byte {
+				return data[:len(data)-5]
+			},
+			wantLen:  2,
+			wantSize: true,
+		},
+		{
+			name: "체크섬이 틀린 마지막 줄은 잘라냄",
+			corrupt: func(data []byte) []byte {
+				data[len(data)-3] ^= 0x01
+				return data
+			},
+			wantLen:  2,
+			wantSize: true,
+		},
+		{
+			name: "중간 줄이 손상되면 에러",
+			corrupt: func(data []byte) []byte {
+				data[12] ^= 0x01
+				return data
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "item.json")
+
+			l, _ := Open(path, 10)
+			l.Append(OpPut, item{ID: 1, Name: "a"})
+			l.Append(OpPut, item{ID: 2, Name: "b"})
+			info, _ := os.Stat(path + logSuffix)
+			twoLines := info.Size()
+			l.Append(OpPut, item{ID: 3, Name: "c"})
+			l.Close()
+
+			data, _ := os.ReadFile(path + logSuffix)
+			os.WriteFile(path+logSuffix, tt.corrupt(data), 0644)
+
+			l, _ = Open(path, 10)
+			defer l.Close()
+
+			items, err := replay(t, l)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Replay() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(items) != tt.wantLen {
+				t.Errorf("Replay() = %v, want %d items", items, tt.wantLen)
+			}
+			if info, _ := os.Stat(path + logSuffix); tt.wantSize && info.Size() != twoLines {
+				t.Errorf("log size = %d, want %d", info.Size(), twoLines)
+			}
+		})
+	}
+}
+
+func TestLog_Replay_Sequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot string
+		want     int64
+	}{
+		{
+			name:     "시퀀스 헤더를 읽음",
+			snapshot: `{"sequence":7,"records":[{"ID":3}]}`,
+			want:     7,
+		},
+		{
+			name:     "예전 배열 형식",
+			snapshot: `[{"ID":3},{"ID":1}]`,
+			want:     LegacySequence,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "item.json")
+			os.WriteFile(path, []byte(tt.snapshot), 0644)
+
+			l, _ := Open(path, 10)
+			defer l.Close()
+
+			var got int64
+			var records []item
+			err := l.Replay(func(sequence int64, data []byte) error {
+				got = sequence
+				return json.Unmarshal(data, &records)
+			}, func(Record) error { return nil })
+			if err != nil {
+				t.Fatalf("Replay() error = %v", err)
+			}
+			if got != tt.want || len(records) == 0 {
+				t.Errorf("Replay() sequence = %d, records = %v, want %d", got, records, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence int64
+		maxID    int64
+		want     int64
+		wantErr  bool
+	}{
+		{name: "삭제된 ID까지 시퀀스 유지", sequence: 10, maxID: 4, want: 10},
+		{name: "예전 형식은 가장 큰 ID 사용", sequence: LegacySequence, maxID: 4, want: 4},
+		{name: "시퀀스를 넘는 ID는 에러", sequence: 3, maxID: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CheckSequence(tt.sequence, tt.maxID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckSequence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CheckSequence() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLog_ResetDiscardsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "item.json")
+
+	l, _ := Open(path, 2)
+	l.Append(OpPut, item{ID: 1, Name: "a"})
+	if err := l.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if l.NeedsSnapshot() {
+		t.Fatalf("NeedsSnapshot() = true after Reset, want false")
+	}
+	l.Append(OpPut, item{ID: 2, Name: "b"})
+	l.Close()
+
+	l, _ = Open(path, 2)
+	defer l.Close()
+
+	items, err := replay(t, l)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if len(items) != 1 || items[2].Name != "b" {
+		t.Errorf("Replay() = %v, want only {2 b}", items)
+	}
+}
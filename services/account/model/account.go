@@ -1,21 +1,139 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"ebank/pkg/money"
+)
+
+// 계좌 상태
+const (
+	AccountStatusActive  = "ACTIVE"
+	AccountStatusFrozen  = "FROZEN"  // 압류, 사고 신고 등으로 동결. 입금만 가능
+	AccountStatusDormant = "DORMANT" // 오래 거래가 없어 휴면. 다시 사용 중으로 바꿀 때까지 입금만 가능
+	AccountStatusClosed  = "CLOSED"  // 해지. 거래할 수 없고 계좌와 거래 내역 조회만 가능
+)
+
+// accountStatusTransitions 상태별로 바꿀 수 있는 다음 상태입니다. 해지한 계좌는 되돌릴 수 없고,
+// 동결된 계좌는 동결을 푼 뒤에 해지할 수 있습니다.
+var accountStatusTransitions = map[string][]string{
+	AccountStatusActive:  {AccountStatusFrozen, AccountStatusDormant, AccountStatusClosed},
+	AccountStatusFrozen:  {AccountStatusActive},
+	AccountStatusDormant: {AccountStatusActive, AccountStatusFrozen, AccountStatusClosed},
+}
 
 type Account struct {
-	ID            int64
-	AccountNumber string
-	CustomerID    int64
-	Balance       float64
-	CreatedAt     time.Time
+	ID              int64
+	AccountNumber   string
+	CustomerID      int64
+	Balance         money.Money
+	Status          string
+	StatusReason    string    // 마지막으로 상태를 바꾼 이유
+	StatusChangedAt time.Time // 상태를 바꾼 적이 없으면 0
+	ProductID       string    // 비어 있으면(상품이 생기기 전에 만든 계좌) 통화의 입출금 상품
+	InterestRate    int64     // 개설할 때 약정한 연 이율, 0.01% 단위
+	MaturesAt       time.Time // 정기예금의 만기일, 정기예금이 아니면 0
+	LinkedAccountID int64     // 정기예금이 만기에 옮겨 갈 계좌
+	CreatedAt       time.Time
+	Version         int64 // 저장할 때마다 1씩 올라갑니다. 읽은 뒤 다른 요청이 계좌를 바꿨는지 확인하는 데 씁니다
+}
+
+// GetStatus 상태가 없는 계좌(상태가 생기기 전에 저장된 계좌)는 사용 중으로 취급합니다.
+func (r Account) GetStatus() string {
+	if r.Status == "" {
+		return AccountStatusActive
+	}
+	return r.Status
+}
+
+// GetProduct 계좌의 상품입니다. 상품이 생기기 전에 만든 계좌는 통화의 입출금 상품으로 취급합니다.
+func (r Account) GetProduct() (Product, bool) {
+	if r.ProductID == "" {
+		return DefaultProduct(r.Balance.Currency)
+	}
+	return GetProduct(r.ProductID)
+}
+
+// IsBeforeMaturity now가 정기예금의 만기 전인지 확인합니다.
+func (r Account) IsBeforeMaturity(now time.Time) bool {
+	return !r.MaturesAt.IsZero() && now.Before(r.MaturesAt)
+}
+
+// MaturityInterest 정기예금을 만기까지 맡겼을 때 지금 잔액에 붙는 이자입니다. 약정 이율을 개설일부터 만기일까지의 날짜 수로
+// 나누어 계산하고, 최소 화폐 단위 아래는 버립니다. 정기예금이 아니면 0입니다.
+func (r Account) MaturityInterest() money.Money {
+	if r.MaturesAt.IsZero() || !r.Balance.IsPositive() {
+		return money.Zero(r.Balance.Currency)
+	}
+
+	days := int64(r.MaturesAt.Sub(r.CreatedAt).Hours() / 24)
+	return money.New(r.Balance.Amount*r.InterestRate*days/(basisPoints*365), r.Balance.Currency)
+}
+
+func IsValidAccountStatus(status string) bool {
+	switch status {
+	case AccountStatusActive, AccountStatusFrozen, AccountStatusDormant, AccountStatusClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransitionTo 지금 상태에서 status로 바꿀 수 있는지 확인합니다.
+func (r Account) CanTransitionTo(status string) bool {
+	for _, next := range accountStatusTransitions[r.GetStatus()] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeStatus 허용된 전이이면 상태와 이유, 바꾼 시각을 기록합니다.
+func (r *Account) ChangeStatus(status string, reason string, at time.Time) error {
+	if !r.CanTransitionTo(status) {
+		return fmt.Errorf("account status cannot change from %s to %s", r.GetStatus(), status)
+	}
+
+	r.Status = status
+	r.StatusReason = reason
+	r.StatusChangedAt = at
+	return nil
+}
+
+// CanDebit 출금과 이체 출금을 할 수 있는 상태인지 확인합니다.
+func (r Account) CanDebit() bool {
+	return r.GetStatus() == AccountStatusActive
+}
+
+// CanCredit 입금과 이체 입금을 받을 수 있는 상태인지 확인합니다.
+func (r Account) CanCredit() bool {
+	return r.GetStatus() != AccountStatusClosed
 }
 
-func (r *Account) AddBalance(amount float64) *Account {
-	r.Balance += amount
-	return r
+func (r *Account) AddBalance(amount money.Money) error {
+	balance, err := r.Balance.Add(amount)
+	if err != nil {
+		return err
+	}
+
+	r.Balance = balance
+	return nil
+}
+
+func (r *Account) SubtractBalance(amount money.Money) error {
+	balance, err := r.Balance.Sub(amount)
+	if err != nil {
+		return err
+	}
+
+	r.Balance = balance
+	return nil
 }
 
-func (r *Account) SubtractBalance(amount float64) *Account {
-	r.Balance -= amount
-	return r
+// HasSufficientBalance 계좌 통화와 같은 금액에 대해서만 true를 반환할 수 있습니다.
+func (r *Account) HasSufficientBalance(amount money.Money) bool {
+	cmp, err := r.Balance.Cmp(amount)
+	return err == nil && cmp >= 0
 }
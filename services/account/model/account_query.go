@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// AccountQuery 계좌 목록 조회 조건입니다. 결과는 ID 오름차순, 즉 개설한 순서입니다.
+// 값이 0이거나 비어 있는 조건은 적용하지 않습니다.
+type AccountQuery struct {
+	CustomerID int64
+	Statuses   []string
+	StartDate  time.Time // 개설 시각, 포함
+	EndDate    time.Time // 개설 시각, 포함
+	MaturesBy  time.Time // 이 시각까지 만기가 된 정기예금만, 포함
+	AfterID    int64     // 이 ID 다음의 계좌만
+	Limit      int
+}
+
+// Matches 계좌가 위치 조건을 뺀 나머지 조건에 맞는지 확인합니다.
+func (q AccountQuery) Matches(account Account) bool {
+	if q.CustomerID != 0 && account.CustomerID != q.CustomerID {
+		return false
+	}
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, status := range q.Statuses {
+			if account.GetStatus() == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !q.StartDate.IsZero() && account.CreatedAt.Before(q.StartDate) {
+		return false
+	}
+	if !q.EndDate.IsZero() && account.CreatedAt.After(q.EndDate) {
+		return false
+	}
+	if !q.MaturesBy.IsZero() && (account.MaturesAt.IsZero() || account.MaturesAt.After(q.MaturesBy)) {
+		return false
+	}
+	return true
+}
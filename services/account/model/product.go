@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"ebank/pkg/money"
+)
+
+// 상품 종류
+const (
+	ProductTypeChecking  = "CHECKING"   // 입출금
+	ProductTypeSavings   = "SAVINGS"    // 적립식 저축. 최소 잔액과 월 출금 횟수 제한
+	ProductTypeFixedTerm = "FIXED_TERM" // 정기예금. 만기가 되면 연결 계좌로 옮기고 해지
+)
+
+// 정기예금의 만기 전 출금 규칙
+const (
+	EarlyWithdrawalBlocked = "BLOCKED" // 만기 전에는 출금, 이체, 해지 불가
+	EarlyWithdrawalPenalty = "PENALTY" // 출금액에 중도 해지 수수료를 더해 뺌
+)
+
+// basisPoints 이율과 수수료율의 단위(0.01%)입니다.
+const basisPoints = 10_000
+
+// Product 계좌를 개설할 때 고르는 상품입니다. 이율과 수수료율은 0.01% 단위입니다.
+type Product struct {
+	ID                     string
+	Name                   string
+	Type                   string
+	Code                   string // 계좌번호의 상품 코드 두 자리
+	Currency               string
+	InterestRate           int64       // 연 이율
+	MinimumBalance         money.Money // 출금 후 남아야 하는 잔액
+	MaxMonthlyWithdrawals  int         // 한 달(UTC)에 할 수 있는 출금과 이체 출금 횟수, 0이면 제한 없음
+	TermMonths             int         // 정기예금의 만기까지 개월 수
+	EarlyWithdrawal        string      // 정기예금의 만기 전 출금 규칙
+	EarlyWithdrawalPenalty int64       // 만기 전 출금액에 대한 수수료율
+}
+
+// products 상품 목록입니다. 입출금 상품은 지원하는 통화마다 있어, 상품 없이 통화만 고른 개설 요청과 예전 계좌에 씁니다.
+var products = []Product{
+	{ID: "CHECKING_KRW", Name: "입출금 통장", Type: ProductTypeChecking, Code: "10", Currency: "KRW", InterestRate: 10, MinimumBalance: money.Zero("KRW")},
+	{ID: "CHECKING_USD", Name: "외화 입출금 통장 (USD)", Type: ProductTypeChecking, Code: "11", Currency: "USD", MinimumBalance: money.Zero("USD")},
+	{ID: "CHECKING_EUR", Name: "외화 입출금 통장 (EUR)", Type: ProductTypeChecking, Code: "12", Currency: "EUR", MinimumBalance: money.Zero("EUR")},
+	{ID: "CHECKING_JPY", Name: "외화 입출금 통장 (JPY)", Type: ProductTypeChecking, Code: "13", Currency: "JPY", MinimumBalance: money.Zero("JPY")},
+	{ID: "CHECKING_CNY", Name: "외화 입출금 통장 (CNY)", Type: ProductTypeChecking, Code: "14", Currency: "CNY", MinimumBalance: money.Zero("CNY")},
+	{ID: "SAVINGS_KRW", Name: "자유 적금", Type: ProductTypeSavings, Code: "20", Currency: "KRW", InterestRate: 250, MinimumBalance: money.New(10_000, "KRW"), MaxMonthlyWithdrawals: 3},
+	{ID: "FIXED_6M_KRW", Name: "6개월 정기예금", Type: ProductTypeFixedTerm, Code: "30", Currency: "KRW", InterestRate: 300, MinimumBalance: money.Zero("KRW"), TermMonths: 6, EarlyWithdrawal: EarlyWithdrawalBlocked},
+	{ID: "FIXED_12M_KRW", Name: "12개월 정기예금", Type: ProductTypeFixedTerm, Code: "31", Currency: "KRW", InterestRate: 350, MinimumBalance: money.Zero("KRW"), TermMonths: 12, EarlyWithdrawal: EarlyWithdrawalPenalty, EarlyWithdrawalPenalty: 100},
+}
+
+// Products 상품 목록을 반환합니다.
+func Products() []Product {
+	return append([]Product(nil), products...)
+}
+
+func GetProduct(id string) (Product, bool) {
+	for _, product := range products {
+		if product.ID == id {
+			return product, true
+		}
+	}
+	return Product{}, false
+}
+
+// DefaultProduct 상품을 고르지 않았을 때 쓰는 currency 통화의 입출금 상품입니다.
+func DefaultProduct(currency string) (Product, bool) {
+	for _, product := range products {
+		if product.Type == ProductTypeChecking && product.Currency == currency {
+			return product, true
+		}
+	}
+	return Product{}, false
+}
+
+func (p Product) IsFixedTerm() bool {
+	return p.Type == ProductTypeFixedTerm
+}
+
+// MaturityDate openedAt에 개설한 정기예금의 만기일입니다. 정기예금이 아니면 0입니다.
+func (p Product) MaturityDate(openedAt time.Time) time.Time {
+	if !p.IsFixedTerm() {
+		return time.Time{}
+	}
+	return openedAt.AddDate(0, p.TermMonths, 0)
+}
+
+// Penalty amount를 만기 전에 출금할 때의 수수료입니다. 최소 화폐 단위 아래는 버립니다.
+func (p Product) Penalty(amount money.Money) money.Money {
+	return money.New(amount.Amount*p.EarlyWithdrawalPenalty/basisPoints, amount.Currency)
+}
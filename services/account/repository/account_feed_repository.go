@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"ebank/pkg/feed"
+	"ebank/services/account/model"
+	"ebank/services/account/service"
+)
+
+// accountFeedRepository 계좌가 저장되면 changes에 계좌 ID로 알립니다. WatchAccount가 구독합니다.
+type accountFeedRepository struct {
+	service.AccountRepository
+	changes *feed.Feed
+}
+
+func NewAccountFeedRepository(repository service.AccountRepository, changes *feed.Feed) service.AccountRepository {
+	return &accountFeedRepository{AccountRepository: repository, changes: changes}
+}
+
+func (r *accountFeedRepository) CreateAccount(ctx context.Context, account model.Account) (model.Account, error) {
+	account, err := r.AccountRepository.CreateAccount(ctx, account)
+	if err == nil {
+		r.changes.Publish(account.ID)
+	}
+	return account, err
+}
+
+func (r *accountFeedRepository) UpdateAccount(ctx context.Context, account *model.Account) error {
+	err := r.AccountRepository.UpdateAccount(ctx, account)
+	if err == nil {
+		r.changes.Publish(account.ID)
+	}
+	return err
+}
+
+func (r *accountFeedRepository) DeleteAccount(ctx context.Context, id int64) error {
+	err := r.AccountRepository.DeleteAccount(ctx, id)
+	if err == nil {
+		r.changes.Publish(id)
+	}
+	return err
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
+	"sort"
 	"sync"
 
+	"ebank/pkg/accountnumber"
+	"ebank/pkg/wal"
 	"ebank/services/account/model"
 	"ebank/services/account/service"
 )
@@ -16,21 +17,31 @@ type accountFileRepository struct {
 	nextID           int64
 	accounts         map[int64]model.Account
 	accountsByUserID map[int64][]int64
-	accountMutex     map[int64]*sync.RWMutex
-	mapMutex         sync.RWMutex
-	fileMutex        sync.RWMutex
-	filePath         string
+	accountsByNumber map[string]int64
+	// sequences 계좌번호 prefix별로 마지막에 발급한 일련번호입니다. 불러온 계좌번호 중 가장 큰 값에서 이어 갑니다.
+	sequences    map[string]int64
+	accountMutex map[int64]*sync.RWMutex
+	mapMutex     sync.RWMutex
+	log          *wal.Log
 }
 
 func NewAccountFileRepository(filePath string) (service.AccountRepository, error) {
+	log, err := wal.Open(filePath, wal.DefaultSnapshotInterval)
+	if err != nil {
+		return nil, err
+	}
+
 	repo := &accountFileRepository{
 		accounts:         make(map[int64]model.Account),
 		accountsByUserID: make(map[int64][]int64),
+		accountsByNumber: make(map[string]int64),
+		sequences:        make(map[string]int64),
 		accountMutex:     make(map[int64]*sync.RWMutex),
-		filePath:         filePath,
+		log:              log,
 	}
 
 	if err := repo.load(); err != nil {
+		log.Close()
 		return nil, err
 	}
 
@@ -38,33 +49,139 @@ func NewAccountFileRepository(filePath string) (service.AccountRepository, error
 }
 
 func (r *accountFileRepository) load() error {
-	data, err := ioutil.ReadFile(r.filePath)
-	if os.IsNotExist(err) {
-		return nil // 파일이 없으면 새로 시작
-	} else if err != nil {
+	err := r.log.Replay(func(sequence int64, data []byte) error {
+		var accounts []model.Account
+		if err := json.Unmarshal(data, &accounts); err != nil {
+			return err
+		}
+
+		var maxID int64
+		for _, account := range accounts {
+			r.put(account)
+			if account.ID > maxID {
+				maxID = account.ID
+			}
+		}
+
+		nextID, err := wal.CheckSequence(sequence, maxID)
+		if err != nil {
+			return err
+		}
+		r.nextID = nextID
+
+		return nil
+	}, func(record wal.Record) error {
+		switch record.Op {
+		case wal.OpPut:
+			var account model.Account
+			if err := json.Unmarshal(record.Data, &account); err != nil {
+				return err
+			}
+			r.put(account)
+			if account.ID > r.nextID {
+				r.nextID = account.ID
+			}
+		case wal.OpDelete:
+			var id int64
+			if err := json.Unmarshal(record.Data, &id); err != nil {
+				return err
+			}
+			r.remove(id)
+		default:
+			return fmt.Errorf("unknown log operation %q", record.Op)
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+	r.dedupeAccountNumbers()
 
-	var accounts []model.Account
-	if err := json.Unmarshal(data, &accounts); err != nil {
-		return err
+	// 재생한 로그를 스냅샷으로 합쳐 다음 시작을 빠르게 합니다.
+	return r.snapshot()
+}
+
+// dedupeAccountNumbers 계좌번호를 서버가 발급하기 전에 직접 입력해 겹친 계좌번호(빈 번호 포함)는
+// SQLite 마이그레이션과 같이 ID가 가장 작은 계좌만 남기고 뒤에 ID를 붙여 구분합니다.
+// 그대로 두면 나중에 불러온 계좌가 번호 인덱스를 차지해 가려진 계좌를 저장할 수 없습니다.
+func (r *accountFileRepository) dedupeAccountNumbers() {
+	ids := make([]int64, 0, len(r.accounts))
+	for id := range r.accounts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	r.accountsByNumber = make(map[string]int64, len(ids))
+	for _, id := range ids {
+		account := r.accounts[id]
+		if _, exists := r.accountsByNumber[account.AccountNumber]; exists {
+			account.AccountNumber = fmt.Sprintf("%s-%d", account.AccountNumber, id)
+			r.accounts[id] = account
+		}
+		r.accountsByNumber[account.AccountNumber] = id
+	}
+}
+
+// put 계정을 맵과 인덱스에 반영합니다. mapMutex를 잡은 상태에서 호출해야 합니다.
+func (r *accountFileRepository) put(account model.Account) {
+	old, exists := r.accounts[account.ID]
+	r.accounts[account.ID] = account
+
+	if exists && old.AccountNumber != account.AccountNumber && r.accountsByNumber[old.AccountNumber] == account.ID {
+		delete(r.accountsByNumber, old.AccountNumber)
+	}
+	r.accountsByNumber[account.AccountNumber] = account.ID
+	if prefix, sequence, ok := accountnumber.Parse(account.AccountNumber); ok && sequence > r.sequences[prefix] {
+		r.sequences[prefix] = sequence
 	}
 
-	for i, account := range accounts {
-		r.accounts[account.ID] = account
-		r.accountsByUserID[account.CustomerID] = append(r.accountsByUserID[account.CustomerID], account.ID)
-		if i == len(accounts)-1 {
-			r.nextID = account.ID
+	if exists && old.CustomerID == account.CustomerID {
+		return
+	}
+	if exists {
+		r.removeFromUserIndex(old.CustomerID, account.ID)
+	}
+	r.accountsByUserID[account.CustomerID] = append(r.accountsByUserID[account.CustomerID], account.ID)
+}
+
+// remove 계정을 맵과 인덱스에서 제거합니다. mapMutex를 잡은 상태에서 호출해야 합니다.
+func (r *accountFileRepository) remove(id int64) {
+	account, exists := r.accounts[id]
+	if !exists {
+		return
+	}
+
+	delete(r.accounts, id)
+	if r.accountsByNumber[account.AccountNumber] == id {
+		delete(r.accountsByNumber, account.AccountNumber)
+	}
+	r.removeFromUserIndex(account.CustomerID, id)
+}
+
+func (r *accountFileRepository) removeFromUserIndex(userID, id int64) {
+	userAccounts := r.accountsByUserID[userID]
+	for i, accID := range userAccounts {
+		if accID == id {
+			r.accountsByUserID[userID] = append(userAccounts[:i], userAccounts[i+1:]...)
+			break
 		}
 	}
+}
+
+// append 변경 내역을 로그에 기록하고, 로그가 충분히 쌓였으면 스냅샷을 만듭니다.
+func (r *accountFileRepository) append(op string, v any) error {
+	if err := r.log.Append(op, v); err != nil {
+		return err
+	}
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
 
 	return nil
 }
 
-func (r *accountFileRepository) save() error {
-	r.fileMutex.Lock()
-	defer r.fileMutex.Unlock()
-
+func (r *accountFileRepository) snapshot() error {
 	accounts := make([]model.Account, 0, len(r.accounts))
 	for _, account := range r.accounts {
 		accounts = append(accounts, account)
@@ -75,7 +192,7 @@ func (r *accountFileRepository) save() error {
 		return err
 	}
 
-	return ioutil.WriteFile(r.filePath, data, 0644)
+	return r.log.Snapshot(r.nextID, data)
 }
 
 func (r *accountFileRepository) LockAccountByID(ctx context.Context, id int64) error {
@@ -102,20 +219,28 @@ func (r *accountFileRepository) UnlockAccountByID(ctx context.Context, id int64)
 }
 
 func (r *accountFileRepository) CreateAccount(ctx context.Context, account model.Account) (model.Account, error) {
-	r.nextID++
-	account.ID = r.nextID
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
 
-	r.accounts[account.ID] = account
-	r.accountsByUserID[account.CustomerID] = append(r.accountsByUserID[account.CustomerID], account.ID)
+	if _, exists := r.accountsByNumber[account.AccountNumber]; exists {
+		return model.Account{}, fmt.Errorf("account with number %s already exists", account.AccountNumber)
+	}
 
-	if err := r.save(); err != nil {
+	account.ID = r.nextID + 1
+	if err := r.append(wal.OpPut, account); err != nil {
 		return model.Account{}, err
 	}
 
+	r.nextID = account.ID
+	r.put(account)
+
 	return account, nil
 }
 
 func (r *accountFileRepository) GetAccountByID(ctx context.Context, id int64) (*model.Account, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
 	account, exists := r.accounts[id]
 	if !exists {
 		return nil, fmt.Errorf("account with ID %d not found", id)
@@ -124,37 +249,122 @@ func (r *accountFileRepository) GetAccountByID(ctx context.Context, id int64) (*
 	return &account, nil
 }
 
-func (r *accountFileRepository) UpdateAccount(ctx context.Context, account model.Account) error {
-	if _, exists := r.accounts[account.ID]; !exists {
+// UpdateAccount 저장된 계좌의 버전이 account.Version과 다르면 저장하지 않습니다.
+func (r *accountFileRepository) UpdateAccount(ctx context.Context, account *model.Account) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	stored, exists := r.accounts[account.ID]
+	if !exists {
 		return fmt.Errorf("account with ID %d not found", account.ID)
 	}
+	if stored.Version != account.Version {
+		return fmt.Errorf("account with ID %d: %w", account.ID, service.ErrAccountChanged)
+	}
+	if id, exists := r.accountsByNumber[account.AccountNumber]; exists && id != account.ID {
+		return fmt.Errorf("account with number %s already exists", account.AccountNumber)
+	}
 
-	r.accounts[account.ID] = account
+	updated := *account
+	updated.Version++
+	if err := r.append(wal.OpPut, updated); err != nil {
+		return err
+	}
 
-	return r.save()
+	r.put(updated)
+	account.Version = updated.Version
+
+	return nil
 }
 
-func (r *accountFileRepository) DeleteAccount(ctx context.Context, id int64) error {
-	account, exists := r.accounts[id]
-	if !exists {
-		return fmt.Errorf("account with ID %d not found", id)
+// CommitAccounts 다른 저장소와 한 트랜잭션으로 묶은 계좌 변경을 반영합니다. accounts의 Version은 저장한 뒤의 버전입니다.
+// 읽은 뒤에 다른 요청이 저장한 계좌가 있으면 commit을 부르지 않고 ErrAccountChanged를 감싼 오류를 반환하며,
+// commit이 성공해야 계좌를 로그와 맵에 반영합니다.
+func (r *accountFileRepository) CommitAccounts(accounts []model.Account, commit func() error) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	for _, account := range accounts {
+		stored, exists := r.accounts[account.ID]
+		if !exists {
+			return fmt.Errorf("account with ID %d not found", account.ID)
+		}
+		if stored.Version != account.Version-1 {
+			return fmt.Errorf("account with ID %d: %w", account.ID, service.ErrAccountChanged)
+		}
+		if id, exists := r.accountsByNumber[account.AccountNumber]; exists && id != account.ID {
+			return fmt.Errorf("account with number %s already exists", account.AccountNumber)
+		}
 	}
 
-	delete(r.accounts, id)
+	if err := commit(); err != nil {
+		return err
+	}
 
-	// Remove account from accountsByUserID
-	userAccounts := r.accountsByUserID[account.CustomerID]
-	for i, accID := range userAccounts {
-		if accID == id {
-			r.accountsByUserID[account.CustomerID] = append(userAccounts[:i], userAccounts[i+1:]...)
-			break
+	return r.putAll(accounts)
+}
+
+// ApplyAccounts 트랜잭션 로그에 남은 계좌 변경을 다시 반영합니다.
+// 이미 반영했거나 그 뒤에 다시 바뀐 계좌, 그 뒤에 지운 계좌는 건너뛰므로 여러 번 반영해도 됩니다.
+func (r *accountFileRepository) ApplyAccounts(accounts []model.Account) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	var pending []model.Account
+	for _, account := range accounts {
+		if stored, exists := r.accounts[account.ID]; exists && stored.Version < account.Version {
+			pending = append(pending, account)
 		}
 	}
 
-	return r.save()
+	return r.putAll(pending)
+}
+
+// putAll 계좌를 로그에 기록하고 맵에 반영합니다. 마지막 계좌까지 맵에 반영한 뒤에 스냅샷을 만들어야 스냅샷에서 빠지지 않습니다.
+func (r *accountFileRepository) putAll(accounts []model.Account) error {
+	for _, account := range accounts {
+		if err := r.log.Append(wal.OpPut, account); err != nil {
+			return err
+		}
+		r.put(account)
+	}
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
+}
+
+func (r *accountFileRepository) DeleteAccount(ctx context.Context, id int64) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	if _, exists := r.accounts[id]; !exists {
+		return fmt.Errorf("account with ID %d not found", id)
+	}
+
+	if err := r.append(wal.OpDelete, id); err != nil {
+		return err
+	}
+
+	r.remove(id)
+
+	return nil
+}
+
+func (r *accountFileRepository) NextAccountNumberSequence(ctx context.Context, prefix string) (int64, error) {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	r.sequences[prefix]++
+	return r.sequences[prefix], nil
 }
 
 func (r *accountFileRepository) GetAccountsByUserID(ctx context.Context, userID int64) ([]model.Account, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
 	accountIDs, exists := r.accountsByUserID[userID]
 	if !exists {
 		return []model.Account{}, nil
@@ -169,6 +379,9 @@ func (r *accountFileRepository) GetAccountsByUserID(ctx context.Context, userID
 }
 
 func (r *accountFileRepository) GetAllAccounts(ctx context.Context) ([]model.Account, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
 	accounts := make([]model.Account, 0, len(r.accounts))
 	for _, account := range r.accounts {
 		accounts = append(accounts, account)
@@ -176,3 +389,32 @@ func (r *accountFileRepository) GetAllAccounts(ctx context.Context) ([]model.Acc
 
 	return accounts, nil
 }
+
+func (r *accountFileRepository) ListAccounts(ctx context.Context, query model.AccountQuery) ([]model.Account, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
+	var ids []int64
+	if query.CustomerID != 0 {
+		ids = append(ids, r.accountsByUserID[query.CustomerID]...)
+	} else {
+		ids = make([]int64, 0, len(r.accounts))
+		for id := range r.accounts {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	accounts := []model.Account{}
+	for _, id := range ids {
+		if query.Limit > 0 && len(accounts) == query.Limit {
+			break
+		}
+		account := r.accounts[id]
+		if id > query.AfterID && query.Matches(account) {
+			accounts = append(accounts, account)
+		}
+	}
+
+	return accounts, nil
+}
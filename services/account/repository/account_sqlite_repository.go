@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"ebank/pkg/money"
+	"ebank/pkg/sqlite"
+	"ebank/services/account/model"
+	"ebank/services/account/service"
+)
+
+const accountColumns = `id, account_number, customer_id, balance_amount, balance_currency, status, status_reason, status_changed_at,
+	product_id, interest_rate, matures_at, linked_account_id, created_at, version`
+
+type accountSQLiteRepository struct {
+	db           sqlite.DB
+	accountMutex map[int64]*sync.Mutex
+	mapMutex     sync.Mutex
+}
+
+func NewAccountSQLiteRepository(db sqlite.DB) service.AccountRepository {
+	return &accountSQLiteRepository{
+		db:           db,
+		accountMutex: make(map[int64]*sync.Mutex),
+	}
+}
+
+func scanAccount(row interface{ Scan(...any) error }) (model.Account, error) {
+	var account model.Account
+	var amount, statusChangedAt, maturesAt, createdAt int64
+	var currency string
+	if err := row.Scan(&account.ID, &account.AccountNumber, &account.CustomerID, &amount, &currency,
+		&account.Status, &account.StatusReason, &statusChangedAt,
+		&account.ProductID, &account.InterestRate, &maturesAt, &account.LinkedAccountID, &createdAt, &account.Version); err != nil {
+		return model.Account{}, err
+	}
+
+	account.Balance = money.New(amount, currency)
+	account.StatusChangedAt = sqlite.FromUnixNano(statusChangedAt)
+	account.MaturesAt = sqlite.FromUnixNano(maturesAt)
+	account.CreatedAt = sqlite.FromUnixNano(createdAt)
+
+	return account, nil
+}
+
+func (r *accountSQLiteRepository) queryAccounts(ctx context.Context, query string, args ...any) ([]model.Account, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []model.Account{}
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// LockAccountByID 잔액을 읽고 쓰는 사이에 다른 요청이 끼어들지 않도록 프로세스 안에서 계좌를 잠급니다.
+func (r *accountSQLiteRepository) LockAccountByID(ctx context.Context, id int64) error {
+	r.mapMutex.Lock()
+	mutex, ok := r.accountMutex[id]
+	if !ok {
+		r.accountMutex[id] = &sync.Mutex{}
+		mutex = r.accountMutex[id]
+	}
+	r.mapMutex.Unlock()
+
+	mutex.Lock()
+
+	return nil
+}
+
+func (r *accountSQLiteRepository) UnlockAccountByID(ctx context.Context, id int64) error {
+	r.mapMutex.Lock()
+	mutex := r.accountMutex[id]
+	r.mapMutex.Unlock()
+
+	mutex.Unlock()
+	return nil
+}
+
+func (r *accountSQLiteRepository) CreateAccount(ctx context.Context, account model.Account) (model.Account, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO accounts (account_number, customer_id, balance_amount, balance_currency, status, status_reason, status_changed_at,
+			product_id, interest_rate, matures_at, linked_account_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (account_number) DO NOTHING`,
+		account.AccountNumber, account.CustomerID, account.Balance.Amount, account.Balance.Currency,
+		account.GetStatus(), account.StatusReason, sqlite.ToUnixNano(account.StatusChangedAt),
+		account.ProductID, account.InterestRate, sqlite.ToUnixNano(account.MaturesAt), account.LinkedAccountID, sqlite.ToUnixNano(account.CreatedAt),
+	)
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return model.Account{}, err
+	} else if affected == 0 {
+		return model.Account{}, fmt.Errorf("account with number %s already exists", account.AccountNumber)
+	}
+
+	account.ID, err = result.LastInsertId()
+	if err != nil {
+		return model.Account{}, err
+	}
+
+	return account, nil
+}
+
+func (r *accountSQLiteRepository) GetAccountByID(ctx context.Context, id int64) (*model.Account, error) {
+	account, err := scanAccount(r.db.QueryRowContext(ctx, `SELECT `+accountColumns+` FROM accounts WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("account with ID %d not found", id)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// UpdateAccount 계좌가 없거나 다른 고객의 계좌로 바뀌는 경우를 같은 트랜잭션 안에서 걸러냅니다.
+// LockAccountByID는 프로세스 안에서만 잠그므로, 같은 DB를 쓰는 다른 프로세스가 읽은 뒤에 저장했으면 버전으로 알아내 덮어쓰지 않습니다.
+func (r *accountSQLiteRepository) UpdateAccount(ctx context.Context, account *model.Account) error {
+	return sqlite.WithTx(ctx, r.db, func(tx sqlite.DB) error {
+		var currency string
+		var version int64
+		err := tx.QueryRowContext(ctx, `SELECT balance_currency, version FROM accounts WHERE id = ?`, account.ID).Scan(&currency, &version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("account with ID %d not found", account.ID)
+		} else if err != nil {
+			return err
+		}
+		if version != account.Version {
+			return fmt.Errorf("account with ID %d: %w", account.ID, service.ErrAccountChanged)
+		}
+		if currency != account.Balance.Currency {
+			return fmt.Errorf("account %d currency cannot change from %s to %s", account.ID, currency, account.Balance.Currency)
+		}
+
+		var duplicated bool
+		err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM accounts WHERE account_number = ? AND id != ?)`, account.AccountNumber, account.ID).Scan(&duplicated)
+		if err != nil {
+			return err
+		}
+		if duplicated {
+			return fmt.Errorf("account with number %s already exists", account.AccountNumber)
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`UPDATE accounts SET account_number = ?, customer_id = ?, balance_amount = ?, status = ?, status_reason = ?, status_changed_at = ?,
+				product_id = ?, interest_rate = ?, matures_at = ?, linked_account_id = ?, created_at = ?, version = version + 1
+			WHERE id = ? AND version = ?`,
+			account.AccountNumber, account.CustomerID, account.Balance.Amount, account.GetStatus(), account.StatusReason,
+			sqlite.ToUnixNano(account.StatusChangedAt), account.ProductID, account.InterestRate, sqlite.ToUnixNano(account.MaturesAt),
+			account.LinkedAccountID, sqlite.ToUnixNano(account.CreatedAt), account.ID, account.Version,
+		)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return fmt.Errorf("account with ID %d: %w", account.ID, service.ErrAccountChanged)
+		}
+
+		account.Version++
+		return nil
+	})
+}
+
+func (r *accountSQLiteRepository) DeleteAccount(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM accounts WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("account with ID %d not found", id)
+	}
+
+	return nil
+}
+
+func (r *accountSQLiteRepository) NextAccountNumberSequence(ctx context.Context, prefix string) (int64, error) {
+	var sequence int64
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO account_number_sequences (prefix, last_value) VALUES (?, 1)
+		ON CONFLICT (prefix) DO UPDATE SET last_value = last_value + 1
+		RETURNING last_value`,
+		prefix,
+	).Scan(&sequence)
+	if err != nil {
+		return 0, err
+	}
+
+	return sequence, nil
+}
+
+func (r *accountSQLiteRepository) GetAccountsByUserID(ctx context.Context, userID int64) ([]model.Account, error) {
+	return r.queryAccounts(ctx, `SELECT `+accountColumns+` FROM accounts WHERE customer_id = ? ORDER BY id`, userID)
+}
+
+func (r *accountSQLiteRepository) GetAllAccounts(ctx context.Context) ([]model.Account, error) {
+	return r.queryAccounts(ctx, `SELECT `+accountColumns+` FROM accounts ORDER BY id`)
+}
+
+func (r *accountSQLiteRepository) ListAccounts(ctx context.Context, query model.AccountQuery) ([]model.Account, error) {
+	conditions := []string{`id > ?`}
+	args := []any{query.AfterID}
+
+	if query.CustomerID != 0 {
+		conditions = append(conditions, `customer_id = ?`)
+		args = append(args, query.CustomerID)
+	}
+	if len(query.Statuses) > 0 {
+		conditions = append(conditions, `status IN (?`+strings.Repeat(`, ?`, len(query.Statuses)-1)+`)`)
+		for _, status := range query.Statuses {
+			args = append(args, status)
+		}
+	}
+	if !query.StartDate.IsZero() {
+		conditions = append(conditions, `created_at >= ?`)
+		args = append(args, sqlite.ToUnixNano(query.StartDate))
+	}
+	if !query.EndDate.IsZero() {
+		conditions = append(conditions, `created_at <= ?`)
+		args = append(args, sqlite.ToUnixNano(query.EndDate))
+	}
+	if !query.MaturesBy.IsZero() {
+		conditions = append(conditions, `matures_at != 0 AND matures_at <= ?`)
+		args = append(args, sqlite.ToUnixNano(query.MaturesBy))
+	}
+
+	statement := `SELECT ` + accountColumns + ` FROM accounts WHERE ` + strings.Join(conditions, ` AND `) + ` ORDER BY id`
+	if query.Limit > 0 {
+		statement += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	return r.queryAccounts(ctx, statement, args...)
+}
@@ -2,17 +2,28 @@ package service
 
 import (
 	"context"
+	"errors"
 
 	"ebank/services/account/model"
 )
 
+// ErrAccountChanged 계좌를 읽은 뒤 다른 요청(다른 프로세스 포함)이 먼저 저장해 UpdateAccount가 덮어쓰지 않았음을 뜻합니다.
+// 계좌를 다시 읽어 처음부터 다시 시도해야 합니다.
+var ErrAccountChanged = errors.New("account was changed by another request")
+
 type AccountRepository interface {
 	CreateAccount(ctx context.Context, account model.Account) (model.Account, error)
 	GetAccountByID(ctx context.Context, id int64) (*model.Account, error)
-	UpdateAccount(ctx context.Context, account model.Account) error
+	// UpdateAccount account.Version이 저장된 계좌와 같을 때만 저장하고 account.Version을 올립니다.
+	// 그 사이 다른 요청이 계좌를 저장했으면 ErrAccountChanged를 감싼 오류를 반환합니다.
+	UpdateAccount(ctx context.Context, account *model.Account) error
 	DeleteAccount(ctx context.Context, id int64) error
 	GetAccountsByUserID(ctx context.Context, userID int64) ([]model.Account, error)
 	GetAllAccounts(ctx context.Context) ([]model.Account, error)
+	// ListAccounts 조건에 맞는 계좌를 ID 순으로 query.Limit개까지 반환합니다.
+	ListAccounts(ctx context.Context, query model.AccountQuery) ([]model.Account, error)
+	// NextAccountNumberSequence prefix의 다음 계좌번호 일련번호를 발급합니다. 계좌에 쓰인 번호는 재시작한 뒤에도 다시 발급하지 않습니다.
+	NextAccountNumberSequence(ctx context.Context, prefix string) (int64, error)
 	LockAccountByID(ctx context.Context, id int64) error
 	UnlockAccountByID(ctx context.Context, id int64) error
 }
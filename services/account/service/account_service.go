@@ -2,51 +2,189 @@ package service
 
 import (
 	"context"
-	"sync"
+	"errors"
+	"strings"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"ebank/api/v1"
+	"ebank/pkg/accountnumber"
+	"ebank/pkg/feed"
+	"ebank/pkg/jwt_manager"
+	"ebank/pkg/money"
+	"ebank/pkg/pagetoken"
 	"ebank/services/account/model"
 )
 
 type accountService struct {
 	ebank.UnimplementedAccountServiceServer
-	// userHelper        UserHelper
+	userHelper        UserHelper
 	accountRepository AccountRepository
-	mutex             sync.RWMutex
+	branchCode        string
+	changes           *feed.Feed
+}
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	// deleteAccountReason DeleteAccount로 해지한 계좌에 남기는 이유입니다.
+	deleteAccountReason = "Closed by DeleteAccount"
+	// watchPollInterval 변경 알림이 없어도 계좌를 다시 읽는 간격입니다.
+	// 다른 프로세스가 같은 SQLite 파일의 계좌를 바꾸면 알림이 오지 않으므로 이 간격으로 확인합니다.
+	watchPollInterval = 5 * time.Second
+)
+
+// listPageFields 페이지 토큰이 바뀌어도 같은 조회로 보는 요청 필드입니다.
+var listPageFields = []string{"page_token", "page_size"}
+
+// accountCursor 페이지 토큰에 담는 계좌 위치입니다.
+type accountCursor struct {
+	ID int64 `json:"i"`
 }
 
 func NewAccountService(
-	// userHelper UserHelper,
+	userHelper UserHelper,
 	accountRepository AccountRepository,
+	branchCode string,
+	changes *feed.Feed,
 ) ebank.AccountServiceServer {
 	return &accountService{
-		// userHelper:            userHelper,
+		userHelper:        userHelper,
 		accountRepository: accountRepository,
+		branchCode:        branchCode,
+		changes:           changes,
 	}
 }
 
 func (s *accountService) CreateAccount(ctx context.Context, req *ebank.CreateAccountRequest) (*ebank.AccountResponse, error) {
-	account, err := s.accountRepository.CreateAccount(ctx, model.Account{
-		AccountNumber: req.AccountNumber,
-		CustomerID:    req.UserId,
-		CreatedAt:     timestamppb.Now().AsTime(),
-	})
+	product, err := requestedProduct(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 계좌 주인은 요청 필드가 아니라 토큰의 사용자입니다.
+	claims, ok := jwt_manager.FromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+	user, err := s.userHelper.ValidateUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timestamppb.Now().AsTime()
+	account := model.Account{
+		CustomerID:   user.ID,
+		Balance:      money.Zero(product.Currency),
+		Status:       model.AccountStatusActive,
+		ProductID:    product.ID,
+		InterestRate: product.InterestRate,
+		MaturesAt:    product.MaturityDate(now),
+		CreatedAt:    now,
+	}
+
+	// 정기예금은 만기에 잔액을 옮길 계좌가 있어야 합니다.
+	// 연결 계좌를 확인하는 동안 해지되거나 동결되지 않도록 잠급니다.
+	if product.IsFixedTerm() {
+		if req.GetLinkedAccountId() != 0 {
+			if err := s.accountRepository.LockAccountByID(ctx, req.GetLinkedAccountId()); err != nil {
+				return nil, status.Errorf(codes.Internal, "Failed to lock account")
+			}
+			defer s.accountRepository.UnlockAccountByID(ctx, req.GetLinkedAccountId())
+		}
+		if err := s.validateLinkedAccount(ctx, user.ID, req.GetLinkedAccountId(), product.Currency); err != nil {
+			return nil, err
+		}
+		account.LinkedAccountID = req.GetLinkedAccountId()
+	} else if req.GetLinkedAccountId() != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Only fixed-term deposits have a linked account")
+	}
+
+	// 계좌번호는 요청 필드가 아니라 서버가 발급합니다.
+	account.AccountNumber, err = s.issueAccountNumber(ctx, product.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err = s.accountRepository.CreateAccount(ctx, account)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to save account data")
 	}
 
-	return &ebank.AccountResponse{Account: &ebank.Account{
-		Id:            account.ID,
-		AccountNumber: account.AccountNumber,
-		CustomerId:    account.CustomerID,
-		Balance:       account.Balance,
-		CreatedAt:     timestamppb.New(account.CreatedAt),
-	}}, nil
+	return &ebank.AccountResponse{Account: ToAccountDto(account)}, nil
+}
+
+// requestedProduct 개설할 상품입니다. 상품을 고르지 않으면 요청한 통화(기본값 KRW)의 입출금 상품입니다.
+func requestedProduct(req *ebank.CreateAccountRequest) (model.Product, error) {
+	currency := req.GetCurrency()
+	if req.GetProductId() == "" {
+		if currency == "" {
+			currency = money.DefaultCurrency
+		}
+		product, ok := model.DefaultProduct(currency)
+		if !ok || !money.IsSupportedCurrency(currency) {
+			return model.Product{}, status.Errorf(codes.InvalidArgument, "Unsupported currency")
+		}
+		return product, nil
+	}
+
+	product, ok := model.GetProduct(req.GetProductId())
+	if !ok {
+		return model.Product{}, status.Errorf(codes.InvalidArgument, "Unknown product %q", req.GetProductId())
+	}
+	if currency != "" && currency != product.Currency {
+		return model.Product{}, status.Errorf(codes.InvalidArgument, "Product %s is only available in %s", product.ID, product.Currency)
+	}
+
+	return product, nil
+}
+
+// validateLinkedAccount 정기예금의 연결 계좌가 만기에 잔액을 받을 수 있는 본인의 같은 통화 계좌인지 확인합니다.
+func (s *accountService) validateLinkedAccount(ctx context.Context, customerID int64, linkedAccountID int64, currency string) error {
+	if linkedAccountID == 0 {
+		return status.Errorf(codes.InvalidArgument, "Fixed-term deposits need a linked account")
+	}
+
+	linked, err := s.accountRepository.GetAccountByID(ctx, linkedAccountID)
+	if err != nil || linked == nil {
+		return status.Errorf(codes.NotFound, "Linked account not found")
+	}
+	if linked.CustomerID != customerID {
+		return status.Errorf(codes.PermissionDenied, "Linked account must be your own account")
+	}
+	if linked.Balance.Currency != currency {
+		return status.Errorf(codes.InvalidArgument, "Linked account must be in %s", currency)
+	}
+	if product, ok := linked.GetProduct(); !ok || product.IsFixedTerm() {
+		return status.Errorf(codes.InvalidArgument, "Linked account cannot be a fixed-term deposit")
+	}
+	if !linked.CanCredit() {
+		return status.Errorf(codes.FailedPrecondition, "Linked account is %s", strings.ToLower(linked.GetStatus()))
+	}
+
+	return nil
+}
+
+// CheckNotLinked 해지하지 않은 정기예금이 만기에 잔액을 옮길 계좌로 account를 연결했으면 FailedPrecondition을 반환합니다.
+// 이런 계좌를 해지하거나 동결하면 만기가 된 정기예금의 잔액이 옮겨 갈 곳이 없습니다.
+// 연결 계좌는 정기예금과 같은 고객의 계좌이므로 그 고객의 계좌만 찾아봅니다.
+func CheckNotLinked(ctx context.Context, accountRepository AccountRepository, account model.Account) error {
+	accounts, err := accountRepository.GetAccountsByUserID(ctx, account.CustomerID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Failed to load account data")
+	}
+	for _, deposit := range accounts {
+		if deposit.LinkedAccountID == account.ID && deposit.GetStatus() != model.AccountStatusClosed {
+			return status.Errorf(codes.FailedPrecondition, "Account receives the maturity payout of fixed-term deposit %s", deposit.AccountNumber)
+		}
+	}
+
+	return nil
 }
 
 func (s *accountService) GetAccount(ctx context.Context, req *ebank.GetAccountRequest) (*ebank.AccountResponse, error) {
@@ -59,19 +197,15 @@ func (s *accountService) GetAccount(ctx context.Context, req *ebank.GetAccountRe
 		return nil, status.Errorf(codes.NotFound, "Account not found")
 	}
 
-	// if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
-	// 	return nil, err
-	// }
+	if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
+		return nil, err
+	}
 
-	return &ebank.AccountResponse{Account: &ebank.Account{
-		Id:            account.ID,
-		AccountNumber: account.AccountNumber,
-		CustomerId:    account.CustomerID,
-		Balance:       account.Balance,
-		CreatedAt:     timestamppb.New(account.CreatedAt),
-	}}, nil
+	return &ebank.AccountResponse{Account: ToAccountDto(*account)}, nil
 }
 
+// UpdateAccount 더 이상 사용하지 않습니다. 바꿀 수 있는 필드가 없으므로 계좌를 확인한 뒤 그대로 돌려주며,
+// 다른 계좌번호를 보내면 거부합니다.
 func (s *accountService) UpdateAccount(ctx context.Context, req *ebank.UpdateAccountRequest) (*ebank.AccountResponse, error) {
 	account, err := s.accountRepository.GetAccountByID(ctx, req.GetId())
 	if err != nil {
@@ -82,26 +216,24 @@ func (s *accountService) UpdateAccount(ctx context.Context, req *ebank.UpdateAcc
 		return nil, status.Errorf(codes.NotFound, "Account not found")
 	}
 
-	// if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
-	// 	return nil, err
-	// }
-
-	account.AccountNumber = req.AccountNumber
+	if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
+		return nil, err
+	}
 
-	if err := s.accountRepository.UpdateAccount(ctx, *account); err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to save account data")
+	if req.AccountNumber != "" && req.AccountNumber != account.AccountNumber {
+		return nil, status.Errorf(codes.InvalidArgument, "Account number cannot be changed")
 	}
 
-	return &ebank.AccountResponse{Account: &ebank.Account{
-		Id:            account.ID,
-		AccountNumber: account.AccountNumber,
-		CustomerId:    account.CustomerID,
-		Balance:       account.Balance,
-		CreatedAt:     timestamppb.New(account.CreatedAt),
-	}}, nil
+	return &ebank.AccountResponse{Account: ToAccountDto(*account)}, nil
 }
 
+// DeleteAccount 기록을 지우지 않고 계좌를 해지합니다. 거래 내역은 해지한 뒤에도 조회할 수 있습니다.
 func (s *accountService) DeleteAccount(ctx context.Context, req *ebank.DeleteAccountRequest) (*emptypb.Empty, error) {
+	if err := s.accountRepository.LockAccountByID(ctx, req.GetId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to lock account")
+	}
+	defer s.accountRepository.UnlockAccountByID(ctx, req.GetId())
+
 	account, err := s.accountRepository.GetAccountByID(ctx, req.GetId())
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "Account not found")
@@ -111,13 +243,287 @@ func (s *accountService) DeleteAccount(ctx context.Context, req *ebank.DeleteAcc
 		return nil, status.Errorf(codes.NotFound, "Account not found")
 	}
 
-	// if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
-	// 	return nil, err
-	// }
+	if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
+		return nil, err
+	}
 
-	if err := s.accountRepository.DeleteAccount(ctx, req.GetId()); err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+	// 남은 잔액을 옮기려면 이체와 함께 처리하는 TransactionService.CloseAccount를 사용해야 합니다.
+	if !account.Balance.IsZero() {
+		return nil, status.Errorf(codes.FailedPrecondition, "Account balance must be zero, use CloseAccount with a payout account")
+	}
+	if err := CheckNotLinked(ctx, s.accountRepository, *account); err != nil {
+		return nil, err
+	}
+	if err := account.ChangeStatus(model.AccountStatusClosed, deleteAccountReason, timestamppb.Now().AsTime()); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if err := s.accountRepository.UpdateAccount(ctx, account); err != nil {
+		return nil, SaveAccountError(err)
 	}
 
 	return &emptypb.Empty{}, nil
 }
+
+// ChangeAccountStatus 직원이 계좌를 동결하거나 휴면 처리하고, 다시 사용 중으로 되돌립니다.
+func (s *accountService) ChangeAccountStatus(ctx context.Context, req *ebank.ChangeAccountStatusRequest) (*ebank.AccountResponse, error) {
+	if !model.IsValidAccountStatus(req.GetStatus()) {
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown account status %q", req.GetStatus())
+	}
+	if req.GetStatus() == model.AccountStatusClosed {
+		return nil, status.Errorf(codes.InvalidArgument, "Use CloseAccount to close an account")
+	}
+	if req.GetReason() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Reason is required")
+	}
+
+	if err := s.accountRepository.LockAccountByID(ctx, req.GetAccountId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to lock account")
+	}
+	defer s.accountRepository.UnlockAccountByID(ctx, req.GetAccountId())
+
+	account, err := s.accountRepository.GetAccountByID(ctx, req.GetAccountId())
+	if err != nil || account == nil {
+		return nil, status.Errorf(codes.NotFound, "Account not found")
+	}
+	if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
+		return nil, err
+	}
+	if req.GetStatus() == model.AccountStatusFrozen {
+		if err := CheckNotLinked(ctx, s.accountRepository, *account); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := account.ChangeStatus(req.GetStatus(), req.GetReason(), timestamppb.Now().AsTime()); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err := s.accountRepository.UpdateAccount(ctx, account); err != nil {
+		return nil, SaveAccountError(err)
+	}
+
+	return &ebank.AccountResponse{Account: ToAccountDto(*account)}, nil
+}
+
+func (s *accountService) ListMyAccounts(ctx context.Context, _ *ebank.ListMyAccountsRequest) (*ebank.AccountListResponse, error) {
+	claims, ok := jwt_manager.FromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+	user, err := s.userHelper.ValidateUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.accountRepository.GetAccountsByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load account data")
+	}
+
+	resp := &ebank.AccountListResponse{Accounts: make([]*ebank.Account, 0, len(accounts))}
+	for _, account := range accounts {
+		resp.Accounts = append(resp.Accounts, ToAccountDto(account))
+	}
+
+	return resp, nil
+}
+
+func (s *accountService) ListAccounts(ctx context.Context, req *ebank.ListAccountsRequest) (*ebank.AccountListResponse, error) {
+	query, err := listQuery(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 한 건 더 읽어 다음 페이지가 있는지 확인합니다.
+	pageSize := query.Limit
+	query.Limit++
+	accounts, err := s.accountRepository.ListAccounts(ctx, query)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load account data")
+	}
+
+	resp := &ebank.AccountListResponse{Accounts: make([]*ebank.Account, 0, len(accounts))}
+	if len(accounts) > pageSize {
+		accounts = accounts[:pageSize]
+		resp.NextPageToken = pagetoken.Encode(accountCursor{ID: accounts[pageSize-1].ID}, pagetoken.Filter(req, listPageFields...))
+	}
+	for _, account := range accounts {
+		resp.Accounts = append(resp.Accounts, ToAccountDto(account))
+	}
+
+	return resp, nil
+}
+
+func (s *accountService) ValidateAccountNumber(ctx context.Context, req *ebank.ValidateAccountNumberRequest) (*ebank.ValidateAccountNumberResponse, error) {
+	accountNumber, err := accountnumber.Normalize(req.GetAccountNumber())
+	if err != nil {
+		return &ebank.ValidateAccountNumberResponse{Valid: false, Reason: err.Error()}, nil
+	}
+
+	return &ebank.ValidateAccountNumberResponse{Valid: true, AccountNumber: accountNumber}, nil
+}
+
+func (s *accountService) WatchAccount(req *ebank.WatchAccountRequest, stream ebank.AccountService_WatchAccountServer) error {
+	ctx := stream.Context()
+
+	account, err := s.accountRepository.GetAccountByID(ctx, req.GetAccountId())
+	if err != nil || account == nil {
+		return status.Errorf(codes.NotFound, "Account not found")
+	}
+	if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
+		return err
+	}
+
+	// 구독한 뒤에 읽어야 그 사이의 변경을 놓치지 않습니다.
+	changes, cancel := s.changes.Subscribe(account.ID)
+	defer cancel()
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	var last *ebank.Account
+	for {
+		account, err := s.accountRepository.GetAccountByID(ctx, req.GetAccountId())
+		if err != nil || account == nil {
+			return status.Errorf(codes.NotFound, "Account not found")
+		}
+
+		if dto := ToAccountDto(*account); last == nil || !proto.Equal(last, dto) {
+			if err := stream.Send(&ebank.AccountResponse{Account: dto}); err != nil {
+				return err
+			}
+			last = dto
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changes:
+		case <-ticker.C:
+		}
+	}
+}
+
+// issueAccountNumber 지점과 상품 코드별 일련번호로 검증 숫자가 붙은 계좌번호를 발급합니다.
+func (s *accountService) issueAccountNumber(ctx context.Context, productCode string) (string, error) {
+	prefix, err := accountnumber.Prefix(s.branchCode, productCode)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Invalid account number prefix")
+	}
+
+	sequence, err := s.accountRepository.NextAccountNumberSequence(ctx, prefix)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Failed to issue account number")
+	}
+
+	accountNumber, err := accountnumber.Generate(prefix, sequence)
+	if err != nil {
+		return "", status.Errorf(codes.ResourceExhausted, "No more account numbers for %s", prefix)
+	}
+
+	return accountNumber, nil
+}
+
+// listQuery 요청을 저장소 조회 조건으로 바꿉니다.
+func listQuery(req *ebank.ListAccountsRequest) (model.AccountQuery, error) {
+	query := model.AccountQuery{
+		CustomerID: req.GetCustomerId(),
+		Statuses:   req.GetStatuses(),
+		Limit:      defaultPageSize,
+	}
+
+	if req.StartDate != nil {
+		query.StartDate = req.StartDate.AsTime()
+	}
+	if req.EndDate != nil {
+		query.EndDate = req.EndDate.AsTime()
+	}
+	if req.StartDate != nil && req.EndDate != nil && query.EndDate.Before(query.StartDate) {
+		return model.AccountQuery{}, status.Errorf(codes.InvalidArgument, "End date must not be before start date")
+	}
+
+	switch {
+	case req.GetPageSize() < 0:
+		return model.AccountQuery{}, status.Errorf(codes.InvalidArgument, "Page size must not be negative")
+	case req.GetPageSize() > maxPageSize:
+		query.Limit = maxPageSize
+	case req.GetPageSize() > 0:
+		query.Limit = int(req.GetPageSize())
+	}
+
+	for _, accountStatus := range query.Statuses {
+		if !model.IsValidAccountStatus(accountStatus) {
+			return model.AccountQuery{}, status.Errorf(codes.InvalidArgument, "Unknown account status %q", accountStatus)
+		}
+	}
+
+	if req.GetPageToken() != "" {
+		var cursor accountCursor
+		if !pagetoken.Decode(req.GetPageToken(), pagetoken.Filter(req, listPageFields...), &cursor) {
+			return model.AccountQuery{}, status.Errorf(codes.InvalidArgument, "Invalid page token")
+		}
+		query.AfterID = cursor.ID
+	}
+
+	return query, nil
+}
+
+func (s *accountService) ListProducts(ctx context.Context, _ *ebank.ListProductsRequest) (*ebank.ListProductsResponse, error) {
+	products := model.Products()
+	resp := &ebank.ListProductsResponse{Products: make([]*ebank.Product, 0, len(products))}
+	for _, product := range products {
+		resp.Products = append(resp.Products, toProductDto(product))
+	}
+
+	return resp, nil
+}
+
+func toProductDto(product model.Product) *ebank.Product {
+	return &ebank.Product{
+		Id:                        product.ID,
+		Name:                      product.Name,
+		Type:                      product.Type,
+		Currency:                  product.Currency,
+		InterestRateBps:           product.InterestRate,
+		MinimumBalance:            money.ToProto(product.MinimumBalance),
+		MaxMonthlyWithdrawals:     int32(product.MaxMonthlyWithdrawals),
+		TermMonths:                int32(product.TermMonths),
+		EarlyWithdrawal:           product.EarlyWithdrawal,
+		EarlyWithdrawalPenaltyBps: product.EarlyWithdrawalPenalty,
+	}
+}
+
+// SaveAccountError UpdateAccount의 오류를 응답 오류로 바꿉니다. 다른 요청이 먼저 계좌를 바꿨으면 다시 시도할 수 있도록 Aborted입니다.
+func SaveAccountError(err error) error {
+	if errors.Is(err, ErrAccountChanged) {
+		return status.Errorf(codes.Aborted, "Account was changed by another request, try again")
+	}
+	return status.Errorf(codes.Internal, "Failed to save account data")
+}
+
+// ToAccountDto 다른 서비스가 계좌를 응답에 담을 때도 같은 형태로 보이도록 내보냅니다.
+func ToAccountDto(account model.Account) *ebank.Account {
+	dto := &ebank.Account{
+		Id:              account.ID,
+		AccountNumber:   account.AccountNumber,
+		CustomerId:      account.CustomerID,
+		Balance:         money.ToProto(account.Balance),
+		Status:          account.GetStatus(),
+		StatusReason:    account.StatusReason,
+		ProductId:       account.ProductID,
+		InterestRateBps: account.InterestRate,
+		LinkedAccountId: account.LinkedAccountID,
+		CreatedAt:       timestamppb.New(account.CreatedAt),
+	}
+	// 상품이 생기기 전에 만든 계좌는 통화의 입출금 상품으로 보여 줍니다.
+	if product, ok := account.GetProduct(); ok {
+		dto.ProductId = product.ID
+	}
+	if !account.StatusChangedAt.IsZero() {
+		dto.StatusChangedAt = timestamppb.New(account.StatusChangedAt)
+	}
+	if !account.MaturesAt.IsZero() {
+		dto.MaturesAt = timestamppb.New(account.MaturesAt)
+	}
+
+	return dto
+}
@@ -2,23 +2,25 @@ package service
 
 import (
 	"context"
-	"log"
-	"os"
-	"path/filepath"
-	"strconv"
+	"strings"
 	"sync"
-	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"ebank/api/v1"
-	"ebank/internal/model"
-	"ebank/internal/repository/repository_impl"
 	"ebank/mocks"
-	"ebank/pkg/config"
+	"ebank/pkg/feed"
+	"ebank/pkg/jwt_manager"
+	"ebank/pkg/money"
+	"ebank/services/account/model"
+	userModel "ebank/services/user/model"
 )
 
 func TestAccountUsecaseSuite(t *testing.T) {
@@ -35,6 +37,7 @@ type AccountUsecaseTestSuite struct {
 	accountRepository     *mocks.AccountRepository
 	transactionRepository *mocks.TransactionRepository
 	userHelper            *mocks.UserHelper
+	changes               *feed.Feed
 	usecase               ebank.AccountServiceServer
 }
 
@@ -43,29 +46,36 @@ func (ts *AccountUsecaseTestSuite) SetupTest() {
 	ts.accountRepository = new(mocks.AccountRepository)
 	ts.transactionRepository = new(mocks.TransactionRepository)
 	ts.userHelper = new(mocks.UserHelper)
-	ts.usecase = NewAccountService(ts.userHelper, ts.accountRepository, ts.transactionRepository)
+	ts.changes = feed.New()
+	ts.usecase = NewAccountService(ts.userHelper, ts.accountRepository, "001", ts.changes)
 }
 
 func (ts *AccountUsecaseTestSuite) Test_accountService_CreateAccount() {
 	testAccount := model.Account{
 		ID:            1,
-		AccountNumber: "1234567890",
+		AccountNumber: "001-10-0000001-5",
 		CustomerID:    123,
-		Balance:       1000,
+		Balance:       money.New(1000, "KRW"),
 	}
 
-	ts.accountRepository.EXPECT().CreateAccount(mock.Anything, mock.Anything).Return(testAccount, nil)
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: testAccount.CustomerID})
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, testAccount.CustomerID).Return(userModel.User{ID: testAccount.CustomerID}, nil)
+	ts.accountRepository.EXPECT().NextAccountNumberSequence(mock.Anything, "00110").Return(1, nil)
+	ts.accountRepository.EXPECT().CreateAccount(mock.Anything, mock.MatchedBy(func(account model.Account) bool {
+		return account.CustomerID == testAccount.CustomerID && account.AccountNumber == testAccount.AccountNumber
+	})).Return(testAccount, nil)
 
-	account, err := ts.usecase.(*accountService).CreateAccount(context.Background(), &ebank.CreateAccountRequest{
-		AccountNumber: testAccount.AccountNumber,
-		UserId:        testAccount.CustomerID,
+	// 요청의 user_id와 account_number는 무시하고 토큰의 사용자로, 서버가 발급한 번호로 계좌를 만듭니다.
+	account, err := ts.usecase.(*accountService).CreateAccount(ctx, &ebank.CreateAccountRequest{
+		AccountNumber: "1234567890",
+		UserId:        999,
 	})
 
 	ts.NoError(err)
 	ts.Equal(testAccount.ID, account.Account.Id)
 	ts.Equal(testAccount.AccountNumber, account.Account.AccountNumber)
 	ts.Equal(testAccount.CustomerID, account.Account.CustomerId)
-	ts.Equal(testAccount.Balance, account.Account.Balance)
+	ts.Equal(money.ToProto(testAccount.Balance), account.Account.Balance)
 }
 
 func (ts *AccountUsecaseTestSuite) Test_accountService_GetAccount() {
@@ -73,11 +83,11 @@ func (ts *AccountUsecaseTestSuite) Test_accountService_GetAccount() {
 		ID:            1,
 		AccountNumber: "1234567890",
 		CustomerID:    123,
-		Balance:       1000,
+		Balance:       money.New(1000, "KRW"),
 	}
 
 	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, mock.Anything).Return(testAccount, nil)
-	ts.userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(model.User{ID: 1}, nil)
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(userModel.User{ID: 1}, nil)
 
 	account, err := ts.usecase.(*accountService).GetAccount(context.Background(), &ebank.GetAccountRequest{
 		Id: testAccount.ID,
@@ -87,39 +97,130 @@ func (ts *AccountUsecaseTestSuite) Test_accountService_GetAccount() {
 	ts.Equal(testAccount.ID, account.Account.Id)
 	ts.Equal(testAccount.AccountNumber, account.Account.AccountNumber)
 	ts.Equal(testAccount.CustomerID, account.Account.CustomerId)
-	ts.Equal(testAccount.Balance, account.Account.Balance)
+	ts.Equal(money.ToProto(testAccount.Balance), account.Account.Balance)
 }
 
-func (ts *AccountUsecaseTestSuite) Test_accountService_UpdateAccount() {
-	testAccount := model.Account{
+func (ts *AccountUsecaseTestSuite) Test_accountService_CreateAccount_Unauthenticated() {
+	_, err := ts.usecase.(*accountService).CreateAccount(context.Background(), &ebank.CreateAccountRequest{
+		AccountNumber: "1234567890",
+	})
+
+	ts.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func (ts *AccountUsecaseTestSuite) Test_accountService_CreateAccount_FixedTerm() {
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: 123})
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, int64(123)).Return(userModel.User{ID: 123}, nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, int64(2)).Return(&model.Account{ID: 2, CustomerID: 123, Balance: money.Zero("KRW")}, nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, int64(3)).Return(&model.Account{ID: 3, CustomerID: 456, Balance: money.Zero("KRW")}, nil)
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, mock.Anything).Return(nil)
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, mock.Anything).Return(nil)
+
+	// 정기예금은 만기에 옮겨 갈 본인 계좌가 있어야 하고, 상품의 통화로만 만들 수 있습니다.
+	_, err := ts.usecase.CreateAccount(ctx, &ebank.CreateAccountRequest{ProductId: "FIXED_12M_KRW"})
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = ts.usecase.CreateAccount(ctx, &ebank.CreateAccountRequest{ProductId: "FIXED_12M_KRW", LinkedAccountId: 3})
+	ts.Equal(codes.PermissionDenied, status.Code(err))
+	_, err = ts.usecase.CreateAccount(ctx, &ebank.CreateAccountRequest{ProductId: "FIXED_12M_KRW", Currency: "USD", LinkedAccountId: 2})
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = ts.usecase.CreateAccount(ctx, &ebank.CreateAccountRequest{ProductId: "UNKNOWN"})
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = ts.usecase.CreateAccount(ctx, &ebank.CreateAccountRequest{LinkedAccountId: 2})
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+
+	ts.accountRepository.EXPECT().NextAccountNumberSequence(mock.Anything, "00131").Return(1, nil)
+	ts.accountRepository.EXPECT().CreateAccount(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, account model.Account) (model.Account, error) {
+		account.ID = 4
+		return account, nil
+	})
+
+	resp, err := ts.usecase.CreateAccount(ctx, &ebank.CreateAccountRequest{ProductId: "FIXED_12M_KRW", LinkedAccountId: 2})
+	ts.Require().NoError(err)
+	ts.Equal("FIXED_12M_KRW", resp.Account.ProductId)
+	ts.True(strings.HasPrefix(resp.Account.AccountNumber, "001-31-0000001-"), resp.Account.AccountNumber)
+	ts.Equal(int64(350), resp.Account.InterestRateBps)
+	ts.Equal(int64(2), resp.Account.LinkedAccountId)
+	ts.Equal(resp.Account.CreatedAt.AsTime().AddDate(1, 0, 0), resp.Account.MaturesAt.AsTime())
+}
+
+func (ts *AccountUsecaseTestSuite) Test_accountService_ListProducts() {
+	resp, err := ts.usecase.ListProducts(context.Background(), &ebank.ListProductsRequest{})
+
+	ts.Require().NoError(err)
+	ts.Len(resp.Products, len(model.Products()))
+	for _, product := range resp.Products {
+		if product.Type == model.ProductTypeFixedTerm {
+			ts.Positive(product.TermMonths)
+			ts.NotEmpty(product.EarlyWithdrawal)
+		}
+	}
+}
+
+func (ts *AccountUsecaseTestSuite) Test_accountService_GetAccount_NotOwner() {
+	testAccount := &model.Account{
 		ID:            1,
 		AccountNumber: "1234567890",
 		CustomerID:    123,
-		Balance:       1000,
+		Balance:       money.New(1000, "KRW"),
 	}
 
-	newAccount := model.Account{
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, testAccount.ID).Return(testAccount, nil)
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, testAccount.CustomerID).Return(userModel.User{}, status.Errorf(codes.PermissionDenied, "Not allowed"))
+
+	_, err := ts.usecase.(*accountService).GetAccount(context.Background(), &ebank.GetAccountRequest{
+		Id: testAccount.ID,
+	})
+
+	ts.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func (ts *AccountUsecaseTestSuite) Test_accountService_UpdateAccount() {
+	testAccount := model.Account{
 		ID:            1,
-		AccountNumber: "234",
+		AccountNumber: "001-10-0000001-5",
 		CustomerID:    123,
-		Balance:       1000,
+		Balance:       money.New(1000, "KRW"),
 	}
 
 	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, mock.Anything).Return(&testAccount, nil)
-	ts.userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(model.User{ID: 1}, nil)
-
-	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.Anything).Return(nil)
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(userModel.User{ID: 1}, nil)
 
 	account, err := ts.usecase.(*accountService).UpdateAccount(context.Background(), &ebank.UpdateAccountRequest{
 		Id:            testAccount.ID,
-		AccountNumber: newAccount.AccountNumber,
+		AccountNumber: testAccount.AccountNumber,
+	})
+
+	ts.NoError(err)
+	ts.Equal(testAccount.ID, account.Account.Id)
+	ts.Equal(testAccount.AccountNumber, account.Account.AccountNumber)
+	ts.Equal(testAccount.CustomerID, account.Account.CustomerId)
+	ts.Equal(money.ToProto(testAccount.Balance), account.Account.Balance)
+
+	// 계좌번호는 서버가 발급하므로 바꿀 수 없습니다.
+	_, err = ts.usecase.(*accountService).UpdateAccount(context.Background(), &ebank.UpdateAccountRequest{
+		Id:            testAccount.ID,
+		AccountNumber: "234",
+	})
+
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+	ts.accountRepository.AssertNotCalled(ts.T(), "UpdateAccount", mock.Anything, mock.Anything)
+}
+
+func (ts *AccountUsecaseTestSuite) Test_accountService_ValidateAccountNumber() {
+	resp, err := ts.usecase.ValidateAccountNumber(context.Background(), &ebank.ValidateAccountNumberRequest{
+		AccountNumber: "00110 0000001 5",
 	})
+	ts.NoError(err)
+	ts.True(resp.Valid)
+	ts.Equal("001-10-0000001-5", resp.AccountNumber)
 
+	// 한 자리를 잘못 쓰면 검증 숫자가 맞지 않습니다.
+	resp, err = ts.usecase.ValidateAccountNumber(context.Background(), &ebank.ValidateAccountNumberRequest{
+		AccountNumber: "001-10-0000002-5",
+	})
 	ts.NoError(err)
-	ts.Equal(newAccount.ID, account.Account.Id)
-	ts.Equal(newAccount.AccountNumber, account.Account.AccountNumber)
-	ts.Equal(newAccount.CustomerID, account.Account.CustomerId)
-	ts.Equal(testAccount.Balance, account.Account.Balance)
+	ts.False(resp.Valid)
+	ts.NotEmpty(resp.Reason)
 }
 
 func (ts *AccountUsecaseTestSuite) Test_accountService_DeleteAccount() {
@@ -127,18 +228,107 @@ func (ts *AccountUsecaseTestSuite) Test_accountService_DeleteAccount() {
 		ID:            1,
 		AccountNumber: "234",
 		CustomerID:    123,
-		Balance:       1000,
+		Balance:       money.New(1000, "KRW"),
 	}
 
-	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, mock.Anything).Return(&testAccount, nil)
-	ts.userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(model.User{ID: 1}, nil)
-	ts.accountRepository.EXPECT().DeleteAccount(mock.Anything, mock.Anything).Return(nil)
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, int64(1)).Return(nil)
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, int64(1)).Return(nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, mock.Anything).RunAndReturn(func(context.Context, int64) (*model.Account, error) {
+		account := testAccount
+		return &account, nil
+	})
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(userModel.User{ID: 1}, nil)
 
+	// 잔액이 남은 계좌는 지울 수 없습니다.
 	_, err := ts.usecase.(*accountService).DeleteAccount(context.Background(), &ebank.DeleteAccountRequest{
 		Id: 1,
 	})
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+
+	// 해지하지 않은 정기예금이 만기에 잔액을 옮길 계좌는 해지할 수 없습니다.
+	testAccount.Balance = money.Zero("KRW")
+	deposits := []model.Account{{ID: 2, AccountNumber: "001-31-0000001-9", CustomerID: 123, LinkedAccountID: 1, Status: model.AccountStatusActive}}
+	ts.accountRepository.EXPECT().GetAccountsByUserID(mock.Anything, int64(123)).RunAndReturn(func(context.Context, int64) ([]model.Account, error) {
+		return deposits, nil
+	})
+	_, err = ts.usecase.(*accountService).DeleteAccount(context.Background(), &ebank.DeleteAccountRequest{
+		Id: 1,
+	})
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+
+	// 잔액이 0이면 기록을 지우지 않고 해지 상태로 바꿉니다. 연결한 정기예금이 해지됐으면 해지할 수 있습니다.
+	deposits[0].Status = model.AccountStatusClosed
+	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.MatchedBy(func(account *model.Account) bool {
+		return account.ID == 1 && account.Status == model.AccountStatusClosed && !account.StatusChangedAt.IsZero()
+	})).Return(nil)
+
+	_, err = ts.usecase.(*accountService).DeleteAccount(context.Background(), &ebank.DeleteAccountRequest{
+		Id: 1,
+	})
 
 	ts.NoError(err)
+	ts.accountRepository.AssertNotCalled(ts.T(), "DeleteAccount", mock.Anything, mock.Anything)
+}
+
+func (ts *AccountUsecaseTestSuite) Test_accountService_ChangeAccountStatus() {
+	testAccount := model.Account{ID: 1, CustomerID: 123, Balance: money.New(1000, "KRW")}
+
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, int64(1)).Return(nil)
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, int64(1)).Return(nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, int64(1)).RunAndReturn(func(context.Context, int64) (*model.Account, error) {
+		account := testAccount
+		return &account, nil
+	})
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, int64(123)).Return(userModel.User{ID: 123}, nil)
+	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, account *model.Account) error {
+		testAccount = *account
+		return nil
+	})
+
+	// 정기예금이 만기에 잔액을 옮길 계좌는 동결할 수 없습니다.
+	deposits := []model.Account{{ID: 2, CustomerID: 123, LinkedAccountID: 1, Status: model.AccountStatusActive}}
+	ts.accountRepository.EXPECT().GetAccountsByUserID(mock.Anything, int64(123)).RunAndReturn(func(context.Context, int64) ([]model.Account, error) {
+		return deposits, nil
+	})
+	_, err := ts.usecase.ChangeAccountStatus(context.Background(), &ebank.ChangeAccountStatusRequest{
+		AccountId: 1,
+		Status:    model.AccountStatusFrozen,
+		Reason:    "court order",
+	})
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+	deposits = nil
+
+	resp, err := ts.usecase.ChangeAccountStatus(context.Background(), &ebank.ChangeAccountStatusRequest{
+		AccountId: 1,
+		Status:    model.AccountStatusFrozen,
+		Reason:    "court order",
+	})
+	ts.Require().NoError(err)
+	ts.Equal(model.AccountStatusFrozen, resp.Account.Status)
+	ts.Equal("court order", resp.Account.StatusReason)
+	ts.NotNil(resp.Account.StatusChangedAt)
+
+	// 동결된 계좌는 휴면으로 바꿀 수 없고, 동결을 풀어야 합니다.
+	_, err = ts.usecase.ChangeAccountStatus(context.Background(), &ebank.ChangeAccountStatusRequest{
+		AccountId: 1,
+		Status:    model.AccountStatusDormant,
+		Reason:    "no activity",
+	})
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+
+	// 해지는 잔액 처리와 함께 CloseAccount로만 합니다.
+	_, err = ts.usecase.ChangeAccountStatus(context.Background(), &ebank.ChangeAccountStatusRequest{
+		AccountId: 1,
+		Status:    model.AccountStatusClosed,
+		Reason:    "customer request",
+	})
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = ts.usecase.ChangeAccountStatus(context.Background(), &ebank.ChangeAccountStatusRequest{
+		AccountId: 1,
+		Status:    model.AccountStatusActive,
+	})
+	ts.Equal(codes.InvalidArgument, status.Code(err))
 }
 
 func (ts *AccountUsecaseTestSuite) Test_accountService_GetAllAccounts() {
@@ -147,13 +337,13 @@ func (ts *AccountUsecaseTestSuite) Test_accountService_GetAllAccounts() {
 			ID:            1,
 			AccountNumber: "1234567890",
 			CustomerID:    123,
-			Balance:       1000,
+			Balance:       money.New(1000, "KRW"),
 		},
 		{
 			ID:            2,
 			AccountNumber: "1234567891",
 			CustomerID:    123,
-			Balance:       2000,
+			Balance:       money.New(2000, "KRW"),
 		},
 	}
 
@@ -161,180 +351,125 @@ func (ts *AccountUsecaseTestSuite) Test_accountService_GetAllAccounts() {
 
 }
 
-type TestServices struct {
-	accountService ebank.AccountServiceServer
-	userService    ebank.UserServiceServer
-}
-
-func testServiceGenerator(t *testing.T, dir string) TestServices {
-	_ = os.Mkdir(dir, os.ModePerm)
-	cfg := config.New()
-	cfg.DB.AccountTablePath = dir + "/account_test.json"
-	cfg.DB.UserTablePath = dir + "/user_test.json"
-	cfg.DB.TransactionTablePath = dir + "/transaction_test.json"
-
-	userFileRepository, err := repository_impl.NewUserFileRepository(cfg.DB.UserTablePath)
-	if err != nil {
-		log.Fatalf("failed to make userFileRepository: %v", err)
-	}
-
-	accountRepository, err := repository_impl.NewAccountFileRepository(cfg.DB.AccountTablePath)
-	if err != nil {
-		log.Fatalf("failed to make accountRepository: %v", err)
+func (ts *AccountUsecaseTestSuite) Test_accountService_ListMyAccounts() {
+	testAccounts := []model.Account{
+		{ID: 1, AccountNumber: "1234567890", CustomerID: 123, Balance: money.New(1000, "KRW")},
+		{ID: 3, AccountNumber: "1234567892", CustomerID: 123, Balance: money.New(10, "USD")},
 	}
 
-	transactionRepository, err := repository_impl.NewTransactionFileRepository(cfg.DB.TransactionTablePath)
-	if err != nil {
-		log.Fatalf("failed to make transactionRepository: %v", err)
-	}
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: 123})
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, int64(123)).Return(userModel.User{ID: 123}, nil)
+	ts.accountRepository.EXPECT().GetAccountsByUserID(mock.Anything, int64(123)).Return(testAccounts, nil)
 
-	userHelper := NewUserHelper(userFileRepository)
-	userService := NewUserService(userHelper, userFileRepository, accountRepository, nil)
-	accountService := NewAccountService(userHelper, accountRepository, transactionRepository)
+	resp, err := ts.usecase.ListMyAccounts(ctx, &ebank.ListMyAccountsRequest{})
 
-	return TestServices{
-		accountService: accountService,
-		userService:    userService,
-	}
+	ts.NoError(err)
+	ts.Len(resp.Accounts, 2)
+	ts.Equal(int64(3), resp.Accounts[1].Id)
+	ts.Equal(model.AccountStatusActive, resp.Accounts[1].Status)
+	ts.Empty(resp.NextPageToken)
 }
 
-func initDataset(t *testing.T, services TestServices) {
-	_, err := services.userService.CreateUser(context.Background(), &ebank.CreateUserRequest{
-		PhoneNumber: "123123123",
-		Name:        "test",
-		Birth:       "1990-01-01",
-		Password:    "123",
-	})
-	if err != nil {
-		t.Fatalf("Failed to create account: %v", err)
-	}
-
-	testAccount := model.Account{
-		ID:            1,
-		AccountNumber: "1234567890",
-		CustomerID:    1,
-		Balance:       0,
+func (ts *AccountUsecaseTestSuite) Test_accountService_ListAccounts() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := &ebank.ListAccountsRequest{
+		CustomerId: 123,
+		Statuses:   []string{model.AccountStatusActive},
+		StartDate:  timestamppb.New(start),
+		PageSize:   2,
 	}
 
-	_, err = services.accountService.CreateAccount(context.Background(), &ebank.CreateAccountRequest{
-		AccountNumber: testAccount.AccountNumber,
-		UserId:        testAccount.CustomerID,
-	})
-	if err != nil {
-		t.Fatalf("Failed to create account: %v", err)
-	}
+	// 첫 페이지는 한 건 더 읽어 다음 페이지가 있는지 확인합니다.
+	ts.accountRepository.EXPECT().ListAccounts(mock.Anything, model.AccountQuery{
+		CustomerID: 123,
+		Statuses:   []string{model.AccountStatusActive},
+		StartDate:  start,
+		Limit:      3,
+	}).Return([]model.Account{{ID: 1}, {ID: 2}, {ID: 5}}, nil).Once()
+
+	resp, err := ts.usecase.ListAccounts(context.Background(), req)
+	ts.Require().NoError(err)
+	ts.Len(resp.Accounts, 2)
+	ts.NotEmpty(resp.NextPageToken)
+
+	ts.accountRepository.EXPECT().ListAccounts(mock.Anything, mock.MatchedBy(func(query model.AccountQuery) bool {
+		return query.AfterID == 2 && query.CustomerID == 123 && query.Limit == 3
+	})).Return([]model.Account{{ID: 5}}, nil).Once()
+
+	req.PageToken = resp.NextPageToken
+	resp, err = ts.usecase.ListAccounts(context.Background(), req)
+	ts.Require().NoError(err)
+	ts.Len(resp.Accounts, 1)
+	ts.Equal(int64(5), resp.Accounts[0].Id)
+	ts.Empty(resp.NextPageToken)
+
+	// 조건을 바꾸면 이전 조건의 토큰으로 이어 볼 수 없습니다.
+	req.CustomerId = 456
+	_, err = ts.usecase.ListAccounts(context.Background(), req)
+	ts.Equal(codes.InvalidArgument, status.Code(err))
 }
 
-// 함수는 지정된 디렉토리의 모든 내용을 삭제합니다.
-func clearDirectory(dir string) error {
-	// 디렉토리 읽기
-	d, err := os.Open(dir)
-	if err != nil {
-		return err
-	}
-	defer d.Close()
+func (ts *AccountUsecaseTestSuite) Test_accountService_ListAccounts_UnknownStatus() {
+	_, err := ts.usecase.ListAccounts(context.Background(), &ebank.ListAccountsRequest{
+		Statuses: []string{"SUSPENDED"},
+	})
 
-	// 디렉토리 내용 얻기
-	names, err := d.Readdirnames(-1)
-	if err != nil {
-		return err
-	}
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+}
 
-	// 각 항목 삭제
-	for _, name := range names {
-		err = os.RemoveAll(filepath.Join(dir, name))
-		if err != nil {
-			return err
-		}
-	}
+type fakeAccountStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *ebank.AccountResponse
+}
 
-	err = os.Remove(dir)
-	if err != nil {
-		return err
-	}
+func (s *fakeAccountStream) Context() context.Context {
+	return s.ctx
+}
 
+func (s *fakeAccountStream) Send(resp *ebank.AccountResponse) error {
+	s.sent <- resp
 	return nil
 }
 
-func Test_accountService_DepositAndWithdrawConcurrently(t *testing.T) {
-	path := "tmp"
-	services := testServiceGenerator(t, path)
-	defer clearDirectory(path)
-
-	_, err := services.userService.CreateUser(context.Background(), &ebank.CreateUserRequest{
-		PhoneNumber: "01011112222",
-		Name:        "test",
-		Birth:       "1990-01-01",
-		Password:    "123",
-	})
-	if err != nil {
-		t.Fatalf("Failed to create account: %v", err)
-	}
-
-	testAccount := model.Account{
-		ID:            1,
-		AccountNumber: "1234567890",
-		CustomerID:    1,
-		Balance:       0,
-	}
-
-	_, err = services.accountService.CreateAccount(context.Background(), &ebank.CreateAccountRequest{
-		AccountNumber: testAccount.AccountNumber,
-		UserId:        testAccount.CustomerID,
-	})
-	if err != nil {
-		t.Fatalf("Failed to create account: %v", err)
-	}
+func (ts *AccountUsecaseTestSuite) Test_accountService_WatchAccount() {
+	var mutex sync.Mutex
+	current := model.Account{ID: 1, CustomerID: 123, Balance: money.New(1000, "KRW")}
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, int64(1)).
+		RunAndReturn(func(ctx context.Context, id int64) (*model.Account, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			account := current
+			return &account, nil
+		})
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, int64(123)).Return(userModel.User{ID: 123}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeAccountStream{ctx: ctx, sent: make(chan *ebank.AccountResponse, 10)}
+	done := make(chan error)
+	go func() {
+		done <- ts.usecase.WatchAccount(&ebank.WatchAccountRequest{AccountId: 1}, stream)
+	}()
+
+	ts.Equal(int64(1000), ts.receive(stream).Account.Balance.Amount)
+
+	mutex.Lock()
+	current.Balance = money.New(1500, "KRW")
+	mutex.Unlock()
+	ts.changes.Publish(1)
+
+	ts.Equal(int64(1500), ts.receive(stream).Account.Balance.Amount)
+
+	cancel()
+	ts.NoError(<-done)
+}
 
-	const amt = 10
-	const c = 1000
-	var negBal int32
-	var start, g sync.WaitGroup
-	start.Add(1)
-	g.Add(3 * c)
-	for i := 0; i < c; i++ {
-		go func() { // deposit
-			start.Wait()
-			services.accountService.Deposit(context.TODO(), &ebank.DepositRequest{
-				AccountId: testAccount.ID,
-				Amount:    amt,
-			}) // ignore return values
-			g.Done()
-		}()
-		go func() { // withdraw
-			start.Wait()
-			for {
-				_, err := services.accountService.Withdraw(context.TODO(), &ebank.WithdrawRequest{
-					AccountId: testAccount.ID,
-					Amount:    amt,
-				})
-				if err == nil {
-					break
-				}
-				// log.Println(err)
-				time.Sleep(1 * time.Millisecond)
-			}
-
-			g.Done()
-		}()
-		go func() { // watch that balance stays >= 0
-			start.Wait()
-			if p, _ := services.accountService.GetAccount(context.TODO(), &ebank.GetAccountRequest{
-				Id: testAccount.ID,
-			}); p.GetAccount().GetBalance() < 0 {
-				atomic.StoreInt32(&negBal, 1)
-			}
-			g.Done()
-		}()
+func (ts *AccountUsecaseTestSuite) receive(stream *fakeAccountStream) *ebank.AccountResponse {
+	select {
+	case resp := <-stream.sent:
+		return resp
+	case <-time.After(time.Second):
+		ts.FailNow("no account update was sent")
+		return nil
 	}
-	start.Done()
-	g.Wait()
-	if negBal == 1 {
-		t.Fatal("Balance went negative with concurrent deposits and " +
-			"withdrawals.  Want balance always >= 0.")
-	}
-	if p, err := services.accountService.GetAccount(context.TODO(), &ebank.GetAccountRequest{Id: testAccount.ID}); err != nil || p.GetAccount().Balance != 0 {
-		t.Fatalf("After equal concurrent deposits and withdrawals, a.Balance = %v, %v.  Want 0, true", strconv.Itoa(int(p.GetAccount().GetBalance())), err)
-	}
-
 }
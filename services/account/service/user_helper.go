@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	userModel "ebank/services/user/model"
+)
+
+// UserHelper 요청한 사용자가 userID 본인인지 확인합니다. user 서비스의 UserHelper가 구현합니다.
+type UserHelper interface {
+	ValidateUser(ctx context.Context, userID int64) (userModel.User, error)
+	// VerifyOTP 큰 금액의 출금과 이체에서 추가 인증으로 사용합니다.
+	VerifyOTP(ctx context.Context, userID int64, code string) error
+}
@@ -0,0 +1,167 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"ebank/pkg/money"
+)
+
+// 시스템 계정 코드
+const (
+	AccountCodeCashInVault     = "system:cash_in_vault"    // 자산: 금고 현금
+	AccountCodeFees            = "system:fees"             // 수익: 수수료
+	AccountCodeInterestExpense = "system:interest_expense" // 비용: 고객에게 지급한 이자
+	AccountCodeOpeningBalance  = "system:opening_balance"  // 자본: 원장 도입 이전 잔액
+)
+
+// CustomerAccountCode 고객 계좌의 원장 계정 코드를 반환합니다.
+func CustomerAccountCode(accountID int64) string {
+	return fmt.Sprintf("account:%d", accountID)
+}
+
+// Posting 분개의 한 쪽 다리입니다. 차변은 양수, 대변은 음수 금액으로 기록합니다.
+type Posting struct {
+	AccountCode string
+	Amount      money.Money
+}
+
+// JournalEntry 차변과 대변의 합이 통화별로 0이 되어야 하는 분개입니다.
+type JournalEntry struct {
+	ID             int64
+	Description    string
+	TransactionIDs []int64
+	ReversalOf     int64 // 역분개인 경우 원 분개 ID
+	Postings       []Posting
+	CreatedAt      time.Time
+}
+
+func Debit(accountCode string, amount money.Money) Posting {
+	return Posting{AccountCode: accountCode, Amount: amount}
+}
+
+func Credit(accountCode string, amount money.Money) Posting {
+	return Posting{AccountCode: accountCode, Amount: money.New(-amount.Amount, amount.Currency)}
+}
+
+// Validate 분개가 두 개 이상의 0이 아닌 다리를 가지고 통화별로 균형을 이루는지 확인합니다.
+func (e JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("journal entry needs at least two postings")
+	}
+
+	sums := make(map[string]money.Money)
+	for _, posting := range e.Postings {
+		if posting.AccountCode == "" {
+			return fmt.Errorf("posting without account code")
+		}
+		if posting.Amount.IsZero() {
+			return fmt.Errorf("posting to %s has zero amount", posting.AccountCode)
+		}
+
+		sum, ok := sums[posting.Amount.Currency]
+		if !ok {
+			sum = money.Zero(posting.Amount.Currency)
+		}
+		sum, err := sum.Add(posting.Amount)
+		if err != nil {
+			return err
+		}
+		sums[posting.Amount.Currency] = sum
+	}
+
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			return fmt.Errorf("journal entry is unbalanced by %v in %s", sum, currency)
+		}
+	}
+
+	return nil
+}
+
+// Reverse 원 분개를 상쇄하는 역분개를 만듭니다.
+func (e JournalEntry) Reverse(description string) JournalEntry {
+	postings := make([]Posting, len(e.Postings))
+	for i, posting := range e.Postings {
+		postings[i] = Posting{
+			AccountCode: posting.AccountCode,
+			Amount:      money.New(-posting.Amount.Amount, posting.Amount.Currency),
+		}
+	}
+
+	return JournalEntry{
+		Description:    description,
+		TransactionIDs: e.TransactionIDs,
+		ReversalOf:     e.ID,
+		Postings:       postings,
+	}
+}
+
+// NewDepositEntry 현금 입금: 금고 현금(자산) 증가, 고객 예금(부채) 증가
+func NewDepositEntry(accountID int64, amount money.Money, transactionIDs ...int64) JournalEntry {
+	return JournalEntry{
+		Description:    "DEPOSIT",
+		TransactionIDs: transactionIDs,
+		Postings: []Posting{
+			Debit(AccountCodeCashInVault, amount),
+			Credit(CustomerAccountCode(accountID), amount),
+		},
+	}
+}
+
+// NewWithdrawalEntry 현금 인출: 고객 예금(부채) 감소, 금고 현금(자산) 감소
+func NewWithdrawalEntry(accountID int64, amount money.Money, transactionIDs ...int64) JournalEntry {
+	return JournalEntry{
+		Description:    "WITHDRAWAL",
+		TransactionIDs: transactionIDs,
+		Postings: []Posting{
+			Debit(CustomerAccountCode(accountID), amount),
+			Credit(AccountCodeCashInVault, amount),
+		},
+	}
+}
+
+// NewTransferEntry 계좌 이체: 출금 계좌 예금 감소, 입금 계좌 예금 증가
+func NewTransferEntry(fromAccountID, toAccountID int64, amount money.Money, transactionIDs ...int64) JournalEntry {
+	return JournalEntry{
+		Description:    "TRANSFER",
+		TransactionIDs: transactionIDs,
+		Postings: []Posting{
+			Debit(CustomerAccountCode(fromAccountID), amount),
+			Credit(CustomerAccountCode(toAccountID), amount),
+		},
+	}
+}
+
+// WithFee 같은 분개에 수수료를 더합니다: 고객 예금(부채) 감소, 수수료(수익) 증가
+func (e JournalEntry) WithFee(accountID int64, fee money.Money, transactionID int64) JournalEntry {
+	e.TransactionIDs = append(append([]int64(nil), e.TransactionIDs...), transactionID)
+	e.Postings = append(append([]Posting(nil), e.Postings...),
+		Debit(CustomerAccountCode(accountID), fee),
+		Credit(AccountCodeFees, fee),
+	)
+	return e
+}
+
+// NewInterestEntry 이자 지급: 이자 비용 증가, 고객 예금(부채) 증가
+func NewInterestEntry(accountID int64, amount money.Money, transactionIDs ...int64) JournalEntry {
+	return JournalEntry{
+		Description:    "INTEREST",
+		TransactionIDs: transactionIDs,
+		Postings: []Posting{
+			Debit(AccountCodeInterestExpense, amount),
+			Credit(CustomerAccountCode(accountID), amount),
+		},
+	}
+}
+
+// NewOpeningBalanceEntry 원장 도입 이전부터 있던 잔액 차이를 기초 잔액 계정으로 맞춥니다.
+func NewOpeningBalanceEntry(accountID int64, difference money.Money) JournalEntry {
+	return JournalEntry{
+		Description: "OPENING_BALANCE",
+		Postings: []Posting{
+			Debit(AccountCodeOpeningBalance, difference),
+			Credit(CustomerAccountCode(accountID), difference),
+		},
+	}
+}
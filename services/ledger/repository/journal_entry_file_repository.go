@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"ebank/pkg/wal"
+	"ebank/services/ledger/model"
+	"ebank/services/ledger/service"
+)
+
+type journalEntryFileRepository struct {
+	nextID               int64
+	journalEntries       map[int64]model.JournalEntry
+	journalEntriesByCode map[string][]int64
+	mapMutex             sync.RWMutex
+	log                  *wal.Log
+}
+
+func NewJournalEntryFileRepository(filePath string) (service.JournalEntryRepository, error) {
+	log, err := wal.Open(filePath, wal.DefaultSnapshotInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &journalEntryFileRepository{
+		journalEntries:       make(map[int64]model.JournalEntry),
+		journalEntriesByCode: make(map[string][]int64),
+		log:                  log,
+	}
+
+	if err := repo.load(); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *journalEntryFileRepository) load() error {
+	err := r.log.Replay(func(sequence int64, data []byte) error {
+		var journalEntries []model.JournalEntry
+		if err := json.Unmarshal(data, &journalEntries); err != nil {
+			return err
+		}
+
+		var maxID int64
+		for _, entry := range journalEntries {
+			r.index(entry)
+			if entry.ID > maxID {
+				maxID = entry.ID
+			}
+		}
+
+		nextID, err := wal.CheckSequence(sequence, maxID)
+		if err != nil {
+			return err
+		}
+		r.nextID = nextID
+
+		return nil
+	}, func(record wal.Record) error {
+		if record.Op != wal.OpPut {
+			return fmt.Errorf("unknown log operation %q", record.Op)
+		}
+
+		var entry model.JournalEntry
+		if err := json.Unmarshal(record.Data, &entry); err != nil {
+			return err
+		}
+		// 스냅샷 직후 중단되어 이미 반영된 분개가 다시 재생될 수 있습니다.
+		if _, exists := r.journalEntries[entry.ID]; exists {
+			return nil
+		}
+		r.index(entry)
+		if entry.ID > r.nextID {
+			r.nextID = entry.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 재생한 로그를 스냅샷으로 합쳐 다음 시작을 빠르게 합니다.
+	return r.snapshot()
+}
+
+func (r *journalEntryFileRepository) index(entry model.JournalEntry) {
+	r.journalEntries[entry.ID] = entry
+
+	seen := make(map[string]bool)
+	for _, posting := range entry.Postings {
+		if seen[posting.AccountCode] {
+			continue
+		}
+		seen[posting.AccountCode] = true
+		r.journalEntriesByCode[posting.AccountCode] = append(r.journalEntriesByCode[posting.AccountCode], entry.ID)
+	}
+}
+
+func (r *journalEntryFileRepository) snapshot() error {
+	journalEntries := make([]model.JournalEntry, 0, len(r.journalEntries))
+	for _, entry := range r.journalEntries {
+		journalEntries = append(journalEntries, entry)
+	}
+
+	data, err := json.Marshal(journalEntries)
+	if err != nil {
+		return err
+	}
+
+	return r.log.Snapshot(r.nextID, data)
+}
+
+func (r *journalEntryFileRepository) CreateJournalEntry(ctx context.Context, entry model.JournalEntry) (model.JournalEntry, error) {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	entry.ID = r.nextID + 1
+	if err := r.log.Append(wal.OpPut, entry); err != nil {
+		return model.JournalEntry{}, err
+	}
+
+	r.nextID = entry.ID
+	r.index(entry)
+
+	if r.log.NeedsSnapshot() {
+		if err := r.snapshot(); err != nil {
+			return model.JournalEntry{}, err
+		}
+	}
+
+	return entry, nil
+}
+
+// ReserveJournalEntryID 다른 저장소와 한 트랜잭션으로 묶어 기록할 분개의 ID를 미리 발급합니다.
+// 커밋하지 못한 ID는 다시 발급하지 않고 건너뜁니다.
+func (r *journalEntryFileRepository) ReserveJournalEntryID() int64 {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	r.nextID++
+	return r.nextID
+}
+
+// ApplyJournalEntries 다른 저장소와 한 트랜잭션으로 묶은 분개를 로그와 맵에 반영합니다.
+// 이미 있는 분개는 건너뛰므로 트랜잭션 로그를 다시 재생해도 됩니다.
+func (r *journalEntryFileRepository) ApplyJournalEntries(entries []model.JournalEntry) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	for _, entry := range entries {
+		if _, exists := r.journalEntries[entry.ID]; exists {
+			continue
+		}
+		if err := r.log.Append(wal.OpPut, entry); err != nil {
+			return err
+		}
+		r.index(entry)
+		if entry.ID > r.nextID {
+			r.nextID = entry.ID
+		}
+	}
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
+}
+
+func (r *journalEntryFileRepository) GetJournalEntryByID(ctx context.Context, id int64) (model.JournalEntry, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
+	entry, exists := r.journalEntries[id]
+	if !exists {
+		return model.JournalEntry{}, fmt.Errorf("journal entry with ID %d not found", id)
+	}
+
+	return entry, nil
+}
+
+func (r *journalEntryFileRepository) GetJournalEntriesByAccountCode(ctx context.Context, accountCode string) ([]model.JournalEntry, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
+	entryIDs := r.journalEntriesByCode[accountCode]
+	journalEntries := make([]model.JournalEntry, len(entryIDs))
+	for i, id := range entryIDs {
+		journalEntries[i] = r.journalEntries[id]
+	}
+
+	return journalEntries, nil
+}
+
+func (r *journalEntryFileRepository) GetAllJournalEntries(ctx context.Context) ([]model.JournalEntry, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
+	journalEntries := make([]model.JournalEntry, 0, len(r.journalEntries))
+	for _, entry := range r.journalEntries {
+		journalEntries = append(journalEntries, entry)
+	}
+
+	return journalEntries, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"ebank/pkg/money"
+	"ebank/pkg/sqlite"
+	"ebank/services/ledger/model"
+	"ebank/services/ledger/service"
+)
+
+type journalEntrySQLiteRepository struct {
+	db sqlite.DB
+}
+
+func NewJournalEntrySQLiteRepository(db sqlite.DB) service.JournalEntryRepository {
+	return &journalEntrySQLiteRepository{db: db}
+}
+
+// CreateJournalEntry 분개와 모든 다리를 하나의 트랜잭션으로 기록합니다.
+func (r *journalEntrySQLiteRepository) CreateJournalEntry(ctx context.Context, entry model.JournalEntry) (model.JournalEntry, error) {
+	transactionIDs, err := json.Marshal(entry.TransactionIDs)
+	if err != nil {
+		return model.JournalEntry{}, err
+	}
+
+	err = sqlite.WithTx(ctx, r.db, func(tx sqlite.DB) error {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO journal_entries (description, transaction_ids, reversal_of, created_at) VALUES (?, ?, ?, ?)`,
+			entry.Description, string(transactionIDs), entry.ReversalOf, sqlite.ToUnixNano(entry.CreatedAt),
+		)
+		if err != nil {
+			return err
+		}
+
+		entry.ID, err = result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		for i, posting := range entry.Postings {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO postings (journal_entry_id, seq, account_code, amount, currency) VALUES (?, ?, ?, ?, ?)`,
+				entry.ID, i, posting.AccountCode, posting.Amount.Amount, posting.Amount.Currency,
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.JournalEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func (r *journalEntrySQLiteRepository) GetJournalEntryByID(ctx context.Context, id int64) (model.JournalEntry, error) {
+	journalEntries, err := r.queryJournalEntries(ctx, `WHERE id = ?`, id)
+	if err != nil {
+		return model.JournalEntry{}, err
+	}
+	if len(journalEntries) == 0 {
+		return model.JournalEntry{}, fmt.Errorf("journal entry with ID %d not found", id)
+	}
+
+	return journalEntries[0], nil
+}
+
+func (r *journalEntrySQLiteRepository) GetJournalEntriesByAccountCode(ctx context.Context, accountCode string) ([]model.JournalEntry, error) {
+	return r.queryJournalEntries(ctx, `WHERE id IN (SELECT journal_entry_id FROM postings WHERE account_code = ?)`, accountCode)
+}
+
+func (r *journalEntrySQLiteRepository) GetAllJournalEntries(ctx context.Context) ([]model.JournalEntry, error) {
+	return r.queryJournalEntries(ctx, ``)
+}
+
+// queryJournalEntries where 조건에 맞는 분개를 ID 순으로 읽고 각 분개의 다리를 채웁니다.
+func (r *journalEntrySQLiteRepository) queryJournalEntries(ctx context.Context, where string, args ...any) ([]model.JournalEntry, error) {
+	journalEntries := []model.JournalEntry{}
+	indexByID := make(map[int64]int)
+
+	err := sqlite.WithTx(ctx, r.db, func(tx sqlite.DB) error {
+		rows, err := tx.QueryContext(ctx, `SELECT id, description, transaction_ids, reversal_of, created_at FROM journal_entries `+where+` ORDER BY id`, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var entry model.JournalEntry
+			var transactionIDs string
+			var createdAt int64
+			if err := rows.Scan(&entry.ID, &entry.Description, &transactionIDs, &entry.ReversalOf, &createdAt); err != nil {
+				return err
+			}
+			if err := json.Unmarshal([]byte(transactionIDs), &entry.TransactionIDs); err != nil {
+				return err
+			}
+			entry.CreatedAt = sqlite.FromUnixNano(createdAt)
+
+			indexByID[entry.ID] = len(journalEntries)
+			journalEntries = append(journalEntries, entry)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		postingRows, err := tx.QueryContext(ctx, `SELECT journal_entry_id, account_code, amount, currency FROM postings
+			WHERE journal_entry_id IN (SELECT id FROM journal_entries `+where+`) ORDER BY journal_entry_id, seq`, args...)
+		if err != nil {
+			return err
+		}
+		defer postingRows.Close()
+
+		for postingRows.Next() {
+			var entryID, amount int64
+			var accountCode, currency string
+			if err := postingRows.Scan(&entryID, &accountCode, &amount, &currency); err != nil {
+				return err
+			}
+
+			i, ok := indexByID[entryID]
+			if !ok {
+				return errors.New("posting without journal entry")
+			}
+			journalEntries[i].Postings = append(journalEntries[i].Postings, model.Posting{AccountCode: accountCode, Amount: money.New(amount, currency)})
+		}
+
+		return postingRows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return journalEntries, nil
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"ebank/services/ledger/model"
+)
+
+type JournalEntryRepository interface {
+	CreateJournalEntry(ctx context.Context, entry model.JournalEntry) (model.JournalEntry, error)
+	GetJournalEntryByID(ctx context.Context, id int64) (model.JournalEntry, error)
+	GetJournalEntriesByAccountCode(ctx context.Context, accountCode string) ([]model.JournalEntry, error)
+	GetAllJournalEntries(ctx context.Context) ([]model.JournalEntry, error)
+}
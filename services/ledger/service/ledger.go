@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"ebank/pkg/money"
+	"ebank/services/ledger/model"
+)
+
+// ErrUnbalanced 원장의 통화별 합계가 0이 아니거나 고객 계좌의 원장 잔액이 계좌 잔액과 다릅니다.
+var ErrUnbalanced = errors.New("ledger does not match account balances")
+
+// maxReportedMismatches Reconcile이 오류에 담는 불일치의 최대 개수입니다.
+const maxReportedMismatches = 10
+
+// Ledger 모든 돈의 이동을 차변/대변이 균형을 이루는 분개로 기록하는 총계정원장입니다.
+type Ledger interface {
+	Post(ctx context.Context, entry model.JournalEntry) (model.JournalEntry, error)
+	Reverse(ctx context.Context, entryID int64, description string) (model.JournalEntry, error)
+	// GetBalance 계정의 차변 합계에서 대변 합계를 뺀 값을 반환합니다.
+	GetBalance(ctx context.Context, accountCode string, currency string) (money.Money, error)
+	// GetCustomerBalance 고객 예금은 부채 계정이므로 대변 잔액을 양수로 반환합니다.
+	GetCustomerBalance(ctx context.Context, accountID int64, currency string) (money.Money, error)
+	// TrialBalance 통화별 전체 분개 합계를 반환합니다. 원장이 정상이면 모두 0입니다.
+	TrialBalance(ctx context.Context) (map[string]money.Money, error)
+	// Reconcile balances(계좌 ID별 잔액)를 원장과 맞춰 봅니다. 원장이 비어 있으면(원장 도입 이전 데이터) 잔액을
+	// 기초 잔액 분개 하나로 옮기고, 분개가 있으면 고치지 않고 확인만 해 다르면 ErrUnbalanced를 감싼 오류를 반환합니다.
+	Reconcile(ctx context.Context, balances map[int64]money.Money) error
+}
+
+type ledger struct {
+	journalEntryRepository JournalEntryRepository
+}
+
+func NewLedger(journalEntryRepository JournalEntryRepository) Ledger {
+	return &ledger{
+		journalEntryRepository: journalEntryRepository,
+	}
+}
+
+func (l *ledger) Post(ctx context.Context, entry model.JournalEntry) (model.JournalEntry, error) {
+	if err := entry.Validate(); err != nil {
+		return model.JournalEntry{}, err
+	}
+
+	entry.CreatedAt = timestamppb.Now().AsTime()
+
+	return l.journalEntryRepository.CreateJournalEntry(ctx, entry)
+}
+
+func (l *ledger) Reverse(ctx context.Context, entryID int64, description string) (model.JournalEntry, error) {
+	entry, err := l.journalEntryRepository.GetJournalEntryByID(ctx, entryID)
+	if err != nil {
+		return model.JournalEntry{}, err
+	}
+
+	return l.Post(ctx, entry.Reverse(description))
+}
+
+func (l *ledger) GetBalance(ctx context.Context, accountCode string, currency string) (money.Money, error) {
+	entries, err := l.journalEntryRepository.GetJournalEntriesByAccountCode(ctx, accountCode)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	balance := money.Zero(currency)
+	for _, entry := range entries {
+		for _, posting := range entry.Postings {
+			if posting.AccountCode != accountCode || posting.Amount.Currency != currency {
+				continue
+			}
+			if balance, err = balance.Add(posting.Amount); err != nil {
+				return money.Money{}, err
+			}
+		}
+	}
+
+	return balance, nil
+}
+
+func (l *ledger) GetCustomerBalance(ctx context.Context, accountID int64, currency string) (money.Money, error) {
+	balance, err := l.GetBalance(ctx, model.CustomerAccountCode(accountID), currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	return money.New(-balance.Amount, balance.Currency), nil
+}
+
+func (l *ledger) TrialBalance(ctx context.Context) (map[string]money.Money, error) {
+	entries, err := l.journalEntryRepository.GetAllJournalEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make(map[string]money.Money)
+	for _, entry := range entries {
+		for _, posting := range entry.Postings {
+			if err := addTo(sums, posting.Amount.Currency, posting.Amount); err != nil {
+				return nil, fmt.Errorf("journal entry %d: %w", entry.ID, err)
+			}
+		}
+	}
+
+	return sums, nil
+}
+
+func (l *ledger) Reconcile(ctx context.Context, balances map[int64]money.Money) error {
+	entries, err := l.journalEntryRepository.GetAllJournalEntries(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int64, 0, len(balances))
+	for id := range balances {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	if len(entries) == 0 {
+		return l.openBalances(ctx, ids, balances)
+	}
+
+	type postingKey struct {
+		accountCode string
+		currency    string
+	}
+	totals := make(map[string]money.Money)
+	accountSums := make(map[postingKey]money.Money)
+	for _, entry := range entries {
+		for _, posting := range entry.Postings {
+			if err := addTo(totals, posting.Amount.Currency, posting.Amount); err != nil {
+				return fmt.Errorf("journal entry %d: %w", entry.ID, err)
+			}
+			if err := addTo(accountSums, postingKey{posting.AccountCode, posting.Amount.Currency}, posting.Amount); err != nil {
+				return fmt.Errorf("journal entry %d: %w", entry.ID, err)
+			}
+		}
+	}
+
+	var mismatches []string
+	currencies := make([]string, 0, len(totals))
+	for currency := range totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		if !totals[currency].IsZero() {
+			mismatches = append(mismatches, fmt.Sprintf("%s postings sum to %v", currency, totals[currency]))
+		}
+	}
+	for _, id := range ids {
+		balance := balances[id]
+		// 고객 예금은 부채 계정이므로 대변 잔액이 계좌 잔액입니다.
+		posted := accountSums[postingKey{model.CustomerAccountCode(id), balance.Currency}]
+		if posted.Amount != -balance.Amount {
+			mismatches = append(mismatches, fmt.Sprintf("account %d balance is %v but ledger has %v", id, balance, money.New(-posted.Amount, balance.Currency)))
+		}
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+	if len(mismatches) > maxReportedMismatches {
+		mismatches = append(mismatches[:maxReportedMismatches], fmt.Sprintf("and %d more", len(mismatches)-maxReportedMismatches))
+	}
+	return fmt.Errorf("%w: %s", ErrUnbalanced, strings.Join(mismatches, "; "))
+}
+
+// openBalances 원장이 생기기 전부터 있던 잔액을 기초 잔액 분개 하나로 기록해, 중간에 실패해도 일부 계좌만 옮겨지지 않게 합니다.
+func (l *ledger) openBalances(ctx context.Context, ids []int64, balances map[int64]money.Money) error {
+	var entry model.JournalEntry
+	for _, id := range ids {
+		if balance := balances[id]; !balance.IsZero() {
+			opening := model.NewOpeningBalanceEntry(id, balance)
+			entry.Description = opening.Description
+			entry.Postings = append(entry.Postings, opening.Postings...)
+		}
+	}
+	if len(entry.Postings) == 0 {
+		return nil
+	}
+
+	_, err := l.Post(ctx, entry)
+	return err
+}
+
+// addTo sums[key]에 amount를 더합니다.
+func addTo[K comparable](sums map[K]money.Money, key K, amount money.Money) error {
+	sum, ok := sums[key]
+	if !ok {
+		sum = money.Zero(amount.Currency)
+	}
+	sum, err := sum.Add(amount)
+	if err != nil {
+		return err
+	}
+	sums[key] = sum
+	return nil
+}
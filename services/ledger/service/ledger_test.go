@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"ebank/pkg/money"
+	"ebank/services/ledger/model"
+	"ebank/services/ledger/repository"
+	"ebank/services/ledger/service"
+)
+
+func newTestLedger(t *testing.T) service.Ledger {
+	journalEntryRepository, err := repository.NewJournalEntryFileRepository(filepath.Join(t.TempDir(), "ledger_test.json"))
+	if err != nil {
+		t.Fatalf("failed to make journalEntryRepository: %v", err)
+	}
+
+	return service.NewLedger(journalEntryRepository)
+}
+
+func Test_ledger_PostRejectsUnbalancedEntry(t *testing.T) {
+	ledger := newTestLedger(t)
+
+	tests := []struct {
+		name  string
+		entry model.JournalEntry
+	}{
+		{
+			name: "차변만 존재",
+			entry: model.JournalEntry{Postings: []model.Posting{
+				model.Debit(model.AccountCodeCashInVault, money.New(100, "KRW")),
+			}},
+		},
+		{
+			name: "합계 불일치",
+			entry: model.JournalEntry{Postings: []model.Posting{
+				model.Debit(model.AccountCodeCashInVault, money.New(100, "KRW")),
+				model.Credit(model.CustomerAccountCode(1), money.New(90, "KRW")),
+			}},
+		},
+		{
+			name: "통화별 불균형",
+			entry: model.JournalEntry{Postings: []model.Posting{
+				model.Debit(model.AccountCodeCashInVault, money.New(100, "KRW")),
+				model.Credit(model.CustomerAccountCode(1), money.New(100, "USD")),
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ledger.Post(context.Background(), tt.entry); err == nil {
+				t.Error("Post() should reject an unbalanced entry")
+			}
+		})
+	}
+}
+
+func Test_ledger_BalancesAreDerivedFromPostings(t *testing.T) {
+	ctx := context.Background()
+	ledger := newTestLedger(t)
+
+	entries := []model.JournalEntry{
+		model.NewDepositEntry(1, money.New(1000, "KRW")),
+		model.NewTransferEntry(1, 2, money.New(300, "KRW")),
+		model.NewWithdrawalEntry(2, money.New(100, "KRW")),
+	}
+	for _, entry := range entries {
+		if _, err := ledger.Post(ctx, entry); err != nil {
+			t.Fatalf("Post() error = %v", err)
+		}
+	}
+
+	deposit, err := ledger.Post(ctx, model.NewDepositEntry(2, money.New(50, "KRW")))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	if _, err := ledger.Reverse(ctx, deposit.ID, "ROLLBACK"); err != nil {
+		t.Fatalf("Reverse() error = %v", err)
+	}
+
+	for accountID, want := range map[int64]money.Money{1: money.New(700, "KRW"), 2: money.New(200, "KRW")} {
+		got, err := ledger.GetCustomerBalance(ctx, accountID, "KRW")
+		if err != nil || got != want {
+			t.Errorf("GetCustomerBalance(%d) = %v, %v, want %v", accountID, got, err, want)
+		}
+	}
+
+	vault, err := ledger.GetBalance(ctx, model.AccountCodeCashInVault, "KRW")
+	if err != nil || vault != money.New(900, "KRW") {
+		t.Errorf("cash in vault = %v, %v, want 900 KRW", vault, err)
+	}
+
+	sums, err := ledger.TrialBalance(ctx)
+	if err != nil {
+		t.Fatalf("TrialBalance() error = %v", err)
+	}
+	if !sums["KRW"].IsZero() {
+		t.Errorf("trial balance = %v, want 0", sums["KRW"])
+	}
+}
+
+func Test_ledger_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	ledger := newTestLedger(t)
+	balances := map[int64]money.Money{1: money.New(500, "KRW"), 2: money.Zero("KRW"), 3: money.New(300, "USD")}
+
+	// 원장이 비어 있으면 잔액을 기초 잔액으로 옮기고, 그 뒤에는 같은 잔액으로 다시 호출해도 기록하지 않습니다.
+	for i := 0; i < 2; i++ {
+		if err := ledger.Reconcile(ctx, balances); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	}
+	for id, want := range balances {
+		got, err := ledger.GetCustomerBalance(ctx, id, want.Currency)
+		if err != nil || got != want {
+			t.Errorf("GetCustomerBalance(%d) = %v, %v, want %v", id, got, err, want)
+		}
+	}
+
+	// 원장이 있으면 계좌 잔액과 달라도 바로잡지 않고 오류로 알립니다.
+	balances[1] = money.New(700, "KRW")
+	if err := ledger.Reconcile(ctx, balances); !errors.Is(err, service.ErrUnbalanced) {
+		t.Fatalf("Reconcile() with a changed balance error = %v, want ErrUnbalanced", err)
+	}
+	if got, _ := ledger.GetCustomerBalance(ctx, 1, "KRW"); got != money.New(500, "KRW") {
+		t.Errorf("GetCustomerBalance() after a failed Reconcile() = %v, want 500 KRW", got)
+	}
+	sums, err := ledger.TrialBalance(ctx)
+	if err != nil {
+		t.Fatalf("TrialBalance() error = %v", err)
+	}
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			t.Errorf("trial balance in %s = %v, want 0", currency, sum)
+		}
+	}
+}
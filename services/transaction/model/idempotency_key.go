@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// 멱등성 키 상태
+const (
+	IdempotencyStatusPending   = "PENDING"   // 처리 중이거나 처리 결과를 저장하지 못함
+	IdempotencyStatusCompleted = "COMPLETED" // 처리를 마치고 응답을 저장함
+)
+
+// IdempotencyKey 돈이 움직이는 요청의 첫 번째 처리 결과를 보관하여 재시도 시 그대로 돌려줍니다.
+// 키는 사용자마다 따로 관리하므로 다른 사용자가 같은 키를 보내도 서로 영향을 주지 않습니다.
+type IdempotencyKey struct {
+	UserID      int64
+	Key         string
+	Method      string
+	RequestHash string
+	Status      string
+	Response    []byte // proto로 직렬화한 최초 응답, 처리를 마치기 전에는 비어 있음
+	CreatedAt   time.Time
+}
+
+// IsCompleted 상태가 생기기 전에 저장된 키는 응답과 함께 저장했으므로 처리를 마친 것으로 취급합니다.
+func (k IdempotencyKey) IsCompleted() bool {
+	return k.Status != IdempotencyStatusPending
+}
@@ -1,11 +1,35 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"ebank/pkg/money"
+)
+
+const (
+	TransactionTypeDeposit     = "DEPOSIT"
+	TransactionTypeWithdrawal  = "WITHDRAWAL"
+	TransactionTypeTransferOut = "TRANSFER_OUT"
+	TransactionTypeTransferIn  = "TRANSFER_IN"
+	TransactionTypeFee         = "FEE"      // 정기예금의 중도 해지 수수료
+	TransactionTypeInterest    = "INTEREST" // 정기예금의 만기 이자
+)
+
+func IsValidTransactionType(transactionType string) bool {
+	switch transactionType {
+	case TransactionTypeDeposit, TransactionTypeWithdrawal, TransactionTypeTransferOut, TransactionTypeTransferIn, TransactionTypeFee, TransactionTypeInterest:
+		return true
+	default:
+		return false
+	}
+}
 
 type Transaction struct {
-	ID              int64
-	AccountID       int64
-	Amount          float64
-	TransactionType string
-	CreatedAt       time.Time
+	ID                    int64
+	AccountID             int64
+	Amount                money.Money
+	TransactionType       string
+	CounterpartyAccountID int64
+	Memo                  string
+	CreatedAt             time.Time
 }
@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// TransactionCursor 앞서 받은 마지막 거래의 위치입니다. 다음 조회는 이 위치 다음부터 시작합니다.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// TransactionQuery 한 계좌의 거래 내역 조회 조건입니다. 결과는 최신 거래부터 (CreatedAt, ID) 내림차순이고,
+// Ascending이면 오래된 거래부터 오름차순입니다.
+// 값이 0이거나 비어 있는 조건은 적용하지 않습니다.
+type TransactionQuery struct {
+	AccountID             int64
+	TransactionTypes      []string
+	MinAmount             *int64 // 최소 화폐 단위, 포함
+	MaxAmount             *int64 // 최소 화폐 단위, 포함
+	CounterpartyAccountID int64
+	StartDate             time.Time          // 포함
+	EndDate               time.Time          // 포함
+	After                 *TransactionCursor // 정렬 순서상 이 위치 다음의 거래만
+	Ascending             bool
+	Limit                 int
+}
+
+// Before c가 other보다 먼저 기록된 거래의 위치인지 비교합니다. 같은 시각이면 ID로 비교합니다.
+func (c TransactionCursor) Before(other TransactionCursor) bool {
+	if !c.CreatedAt.Equal(other.CreatedAt) {
+		return c.CreatedAt.Before(other.CreatedAt)
+	}
+	return c.ID < other.ID
+}
+
+// Cursor 거래의 정렬 위치입니다.
+func (t Transaction) Cursor() TransactionCursor {
+	return TransactionCursor{CreatedAt: t.CreatedAt, ID: t.ID}
+}
+
+// Matches 거래가 위치 조건을 뺀 나머지 조건에 맞는지 확인합니다.
+func (q TransactionQuery) Matches(t Transaction) bool {
+	if q.AccountID != 0 && t.AccountID != q.AccountID {
+		return false
+	}
+	if len(q.TransactionTypes) > 0 {
+		found := false
+		for _, transactionType := range q.TransactionTypes {
+			if t.TransactionType == transactionType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.MinAmount != nil && t.Amount.Amount < *q.MinAmount {
+		return false
+	}
+	if q.MaxAmount != nil && t.Amount.Amount > *q.MaxAmount {
+		return false
+	}
+	if q.CounterpartyAccountID != 0 && t.CounterpartyAccountID != q.CounterpartyAccountID {
+		return false
+	}
+	if !q.StartDate.IsZero() && t.CreatedAt.Before(q.StartDate) {
+		return false
+	}
+	if !q.EndDate.IsZero() && t.CreatedAt.After(q.EndDate) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"ebank/pkg/wal"
+	"ebank/services/transaction/model"
+	"ebank/services/transaction/service"
+)
+
+type idempotencyFileRepository struct {
+	idempotencyKeys map[string]model.IdempotencyKey // idempotencyMapKey로 찾습니다
+	locks           keyLocks
+	mapMutex        sync.RWMutex
+	log             *wal.Log
+}
+
+func NewIdempotencyFileRepository(filePath string) (service.IdempotencyRepository, error) {
+	log, err := wal.Open(filePath, wal.DefaultSnapshotInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &idempotencyFileRepository{
+		idempotencyKeys: make(map[string]model.IdempotencyKey),
+		log:             log,
+	}
+
+	if err := repo.load(); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *idempotencyFileRepository) load() error {
+	err := r.log.Replay(func(_ int64, data []byte) error {
+		var idempotencyKeys []model.IdempotencyKey
+		if err := json.Unmarshal(data, &idempotencyKeys); err != nil {
+			return err
+		}
+
+		for _, idempotencyKey := range idempotencyKeys {
+			r.idempotencyKeys[idempotencyMapKey(idempotencyKey.UserID, idempotencyKey.Key)] = idempotencyKey
+		}
+
+		return nil
+	}, func(record wal.Record) error {
+		var idempotencyKey model.IdempotencyKey
+		if err := json.Unmarshal(record.Data, &idempotencyKey); err != nil {
+			return err
+		}
+
+		switch record.Op {
+		case wal.OpPut:
+			r.idempotencyKeys[idempotencyMapKey(idempotencyKey.UserID, idempotencyKey.Key)] = idempotencyKey
+		case wal.OpDelete:
+			delete(r.idempotencyKeys, idempotencyMapKey(idempotencyKey.UserID, idempotencyKey.Key))
+		default:
+			return fmt.Errorf("unknown log operation %q", record.Op)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 재생한 로그를 스냅샷으로 합쳐 다음 시작을 빠르게 합니다.
+	return r.snapshot()
+}
+
+// idempotencyMapKey 사용자별로 키를 구분하는 맵 키입니다. 사용자 0은 사용자별로 나누기 전에 저장한 키입니다.
+func idempotencyMapKey(userID int64, key string) string {
+	return fmt.Sprintf("%d:%s", userID, key)
+}
+
+func (r *idempotencyFileRepository) snapshot() error {
+	idempotencyKeys := make([]model.IdempotencyKey, 0, len(r.idempotencyKeys))
+	for _, idempotencyKey := range r.idempotencyKeys {
+		idempotencyKeys = append(idempotencyKeys, idempotencyKey)
+	}
+
+	data, err := json.Marshal(idempotencyKeys)
+	if err != nil {
+		return err
+	}
+
+	return r.log.Snapshot(0, data)
+}
+
+func (r *idempotencyFileRepository) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*model.IdempotencyKey, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
+	idempotencyKey, exists := r.idempotencyKeys[idempotencyMapKey(userID, key)]
+	if !exists {
+		return nil, nil
+	}
+
+	return &idempotencyKey, nil
+}
+
+func (r *idempotencyFileRepository) CreateIdempotencyKey(ctx context.Context, idempotencyKey model.IdempotencyKey) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	mapKey := idempotencyMapKey(idempotencyKey.UserID, idempotencyKey.Key)
+	if _, exists := r.idempotencyKeys[mapKey]; exists {
+		return fmt.Errorf("idempotency key %s already exists", idempotencyKey.Key)
+	}
+
+	if err := r.log.Append(wal.OpPut, idempotencyKey); err != nil {
+		return err
+	}
+
+	r.idempotencyKeys[mapKey] = idempotencyKey
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
+}
+
+func (r *idempotencyFileRepository) CompleteIdempotencyKey(ctx context.Context, userID int64, key string, response []byte) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	mapKey := idempotencyMapKey(userID, key)
+	idempotencyKey, exists := r.idempotencyKeys[mapKey]
+	if !exists || idempotencyKey.IsCompleted() {
+		return fmt.Errorf("pending idempotency key %s not found", key)
+	}
+
+	idempotencyKey.Status = model.IdempotencyStatusCompleted
+	idempotencyKey.Response = response
+	if err := r.log.Append(wal.OpPut, idempotencyKey); err != nil {
+		return err
+	}
+
+	r.idempotencyKeys[mapKey] = idempotencyKey
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
+}
+
+func (r *idempotencyFileRepository) DeleteIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	mapKey := idempotencyMapKey(userID, key)
+	idempotencyKey, exists := r.idempotencyKeys[mapKey]
+	if !exists || idempotencyKey.IsCompleted() {
+		return nil
+	}
+
+	if err := r.log.Append(wal.OpDelete, model.IdempotencyKey{UserID: userID, Key: key}); err != nil {
+		return err
+	}
+
+	delete(r.idempotencyKeys, mapKey)
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
+}
+
+func (r *idempotencyFileRepository) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	for mapKey, idempotencyKey := range r.idempotencyKeys {
+		if !idempotencyKey.CreatedAt.Before(before) {
+			continue
+		}
+		if err := r.log.Append(wal.OpDelete, model.IdempotencyKey{UserID: idempotencyKey.UserID, Key: idempotencyKey.Key}); err != nil {
+			return err
+		}
+		delete(r.idempotencyKeys, mapKey)
+	}
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
+}
+
+func (r *idempotencyFileRepository) LockIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	r.locks.lock(idempotencyMapKey(userID, key))
+	return nil
+}
+
+func (r *idempotencyFileRepository) UnlockIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	r.locks.unlock(idempotencyMapKey(userID, key))
+	return nil
+}
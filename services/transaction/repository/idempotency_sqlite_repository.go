@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"ebank/pkg/sqlite"
+	"ebank/services/transaction/model"
+	"ebank/services/transaction/service"
+)
+
+type idempotencySQLiteRepository struct {
+	db    *sql.DB
+	locks keyLocks
+}
+
+func NewIdempotencySQLiteRepository(db *sql.DB) service.IdempotencyRepository {
+	return &idempotencySQLiteRepository{
+		db: db,
+	}
+}
+
+func (r *idempotencySQLiteRepository) GetIdempotencyKey(ctx context.Context, userID int64, key string) (*model.IdempotencyKey, error) {
+	var idempotencyKey model.IdempotencyKey
+	var createdAt int64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT user_id, key, method, request_hash, status, response, created_at FROM idempotency_keys WHERE user_id = ? AND key = ?`, userID, key,
+	).Scan(&idempotencyKey.UserID, &idempotencyKey.Key, &idempotencyKey.Method, &idempotencyKey.RequestHash, &idempotencyKey.Status, &idempotencyKey.Response, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	idempotencyKey.CreatedAt = sqlite.FromUnixNano(createdAt)
+
+	return &idempotencyKey, nil
+}
+
+func (r *idempotencySQLiteRepository) CreateIdempotencyKey(ctx context.Context, idempotencyKey model.IdempotencyKey) error {
+	response := idempotencyKey.Response
+	if response == nil {
+		response = []byte{}
+	}
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO idempotency_keys (user_id, key, method, request_hash, status, response, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, key) DO NOTHING`,
+		idempotencyKey.UserID, idempotencyKey.Key, idempotencyKey.Method, idempotencyKey.RequestHash, idempotencyKey.Status, response,
+		sqlite.ToUnixNano(idempotencyKey.CreatedAt),
+	)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("idempotency key %s already exists", idempotencyKey.Key)
+	}
+
+	return nil
+}
+
+func (r *idempotencySQLiteRepository) CompleteIdempotencyKey(ctx context.Context, userID int64, key string, response []byte) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE idempotency_keys SET status = ?, response = ? WHERE user_id = ? AND key = ? AND status = ?`,
+		model.IdempotencyStatusCompleted, response, userID, key, model.IdempotencyStatusPending,
+	)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("pending idempotency key %s not found", key)
+	}
+
+	return nil
+}
+
+func (r *idempotencySQLiteRepository) DeleteIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM idempotency_keys WHERE user_id = ? AND key = ? AND status = ?`,
+		userID, key, model.IdempotencyStatusPending,
+	)
+	return err
+}
+
+func (r *idempotencySQLiteRepository) DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE created_at < ?`, sqlite.ToUnixNano(before))
+	return err
+}
+
+func (r *idempotencySQLiteRepository) LockIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	r.locks.lock(idempotencyMapKey(userID, key))
+	return nil
+}
+
+func (r *idempotencySQLiteRepository) UnlockIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	r.locks.unlock(idempotencyMapKey(userID, key))
+	return nil
+}
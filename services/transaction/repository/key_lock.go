@@ -0,0 +1,46 @@
+package repository
+
+import "sync"
+
+// keyLock 기다리는 요청이 없으면 맵에서 지워, 임의의 멱등성 키로 요청해도 잠금이 쌓이지 않게 합니다.
+type keyLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// keyLocks 키별 잠금입니다. 잠그는 쪽과 푸는 쪽이 따로 호출하므로 풀 때 키로 잠금을 다시 찾습니다.
+type keyLocks struct {
+	mutex sync.Mutex
+	locks map[string]*keyLock
+}
+
+func (l *keyLocks) lock(key string) {
+	l.mutex.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*keyLock)
+	}
+	mutex, ok := l.locks[key]
+	if !ok {
+		mutex = &keyLock{}
+		l.locks[key] = mutex
+	}
+	mutex.waiters++
+	l.mutex.Unlock()
+
+	mutex.Lock()
+}
+
+func (l *keyLocks) unlock(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	mutex, ok := l.locks[key]
+	if !ok {
+		return
+	}
+	mutex.Unlock()
+	mutex.waiters--
+	if mutex.waiters == 0 {
+		delete(l.locks, key)
+	}
+}
@@ -4,31 +4,37 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
+	"sort"
 	"sync"
 
+	"ebank/pkg/wal"
 	"ebank/services/transaction/model"
 	"ebank/services/transaction/service"
 )
 
 type transactionFileRepository struct {
-	nextID                  int64
-	transactions            map[int64]model.Transaction
+	nextID       int64
+	transactions map[int64]model.Transaction
+	// transactionsByAccountID 계좌별 거래 ID를 (CreatedAt, ID) 오름차순으로 유지하는 인덱스입니다.
 	transactionsByAccountID map[int64][]int64
 	mapMutex                sync.RWMutex
-	fileMutex               sync.RWMutex
-	filePath                string
+	log                     *wal.Log
 }
 
 func NewTransactionFileRepository(filePath string) (service.TransactionRepository, error) {
+	log, err := wal.Open(filePath, wal.DefaultSnapshotInterval)
+	if err != nil {
+		return nil, err
+	}
+
 	repo := &transactionFileRepository{
 		transactions:            make(map[int64]model.Transaction),
 		transactionsByAccountID: make(map[int64][]int64),
-		filePath:                filePath,
+		log:                     log,
 	}
 
 	if err := repo.load(); err != nil {
+		log.Close()
 		return nil, err
 	}
 
@@ -36,33 +42,127 @@ func NewTransactionFileRepository(filePath string) (service.TransactionRepositor
 }
 
 func (r *transactionFileRepository) load() error {
-	data, err := ioutil.ReadFile(r.filePath)
-	if os.IsNotExist(err) {
-		return nil // 파일이 없으면 새로 시작
-	} else if err != nil {
-		return err
-	}
+	err := r.log.Replay(func(sequence int64, data []byte) error {
+		var transactions []model.Transaction
+		if err := json.Unmarshal(data, &transactions); err != nil {
+			return err
+		}
 
-	var transactions []model.Transaction
-	if err := json.Unmarshal(data, &transactions); err != nil {
+		var maxID int64
+		for _, transaction := range transactions {
+			r.transactions[transaction.ID] = transaction
+			r.transactionsByAccountID[transaction.AccountID] = append(r.transactionsByAccountID[transaction.AccountID], transaction.ID)
+			if transaction.ID > maxID {
+				maxID = transaction.ID
+			}
+		}
+		// 스냅샷은 순서가 없으므로 한 번에 정렬합니다.
+		for _, ids := range r.transactionsByAccountID {
+			sort.Slice(ids, func(i, j int) bool {
+				return r.transactions[ids[i]].Cursor().Before(r.transactions[ids[j]].Cursor())
+			})
+		}
+
+		nextID, err := wal.CheckSequence(sequence, maxID)
+		if err != nil {
+			return err
+		}
+		r.nextID = nextID
+
+		return nil
+	}, func(record wal.Record) error {
+		switch record.Op {
+		case wal.OpPut:
+			var transaction model.Transaction
+			if err := json.Unmarshal(record.Data, &transaction); err != nil {
+				return err
+			}
+			r.put(transaction)
+			if transaction.ID > r.nextID {
+				r.nextID = transaction.ID
+			}
+		case wal.OpDelete:
+			var id int64
+			if err := json.Unmarshal(record.Data, &id); err != nil {
+				return err
+			}
+			r.remove(id)
+		default:
+			return fmt.Errorf("unknown log operation %q", record.Op)
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	for i, transaction := range transactions {
+	// 재생한 로그를 스냅샷으로 합쳐 다음 시작을 빠르게 합니다.
+	return r.snapshot()
+}
+
+// put 거래를 맵과 인덱스에 반영합니다. mapMutex를 잡은 상태에서 호출해야 합니다.
+func (r *transactionFileRepository) put(transaction model.Transaction) {
+	old, exists := r.transactions[transaction.ID]
+	if exists && old.AccountID == transaction.AccountID && old.CreatedAt.Equal(transaction.CreatedAt) {
 		r.transactions[transaction.ID] = transaction
-		r.transactionsByAccountID[transaction.AccountID] = append(r.transactionsByAccountID[transaction.AccountID], transaction.ID)
-		if i == len(transactions)-1 {
-			r.nextID = transaction.ID
+		return
+	}
+	if exists {
+		r.removeFromAccountIndex(old.AccountID, transaction.ID)
+	}
+	r.transactions[transaction.ID] = transaction
+
+	// 새 거래는 대부분 가장 최근이므로 끝에 붙습니다.
+	ids := r.transactionsByAccountID[transaction.AccountID]
+	i := r.searchAccountIndex(ids, transaction.Cursor())
+	ids = append(ids, 0)
+	copy(ids[i+1:], ids[i:])
+	ids[i] = transaction.ID
+	r.transactionsByAccountID[transaction.AccountID] = ids
+}
+
+// searchAccountIndex ids에서 cursor 이후 위치인 첫 번째 인덱스를 찾습니다.
+func (r *transactionFileRepository) searchAccountIndex(ids []int64, cursor model.TransactionCursor) int {
+	return sort.Search(len(ids), func(i int) bool {
+		return !r.transactions[ids[i]].Cursor().Before(cursor)
+	})
+}
+
+// remove 거래를 맵과 인덱스에서 제거합니다. mapMutex를 잡은 상태에서 호출해야 합니다.
+func (r *transactionFileRepository) remove(id int64) {
+	transaction, exists := r.transactions[id]
+	if !exists {
+		return
+	}
+
+	delete(r.transactions, id)
+	r.removeFromAccountIndex(transaction.AccountID, id)
+}
+
+func (r *transactionFileRepository) removeFromAccountIndex(accountID, id int64) {
+	accountTransactions := r.transactionsByAccountID[accountID]
+	for i, txID := range accountTransactions {
+		if txID == id {
+			r.transactionsByAccountID[accountID] = append(accountTransactions[:i], accountTransactions[i+1:]...)
+			break
 		}
 	}
+}
+
+// append 변경 내역을 로그에 기록하고, 로그가 충분히 쌓였으면 스냅샷을 만듭니다.
+func (r *transactionFileRepository) append(op string, v any) error {
+	if err := r.log.Append(op, v); err != nil {
+		return err
+	}
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
 
 	return nil
 }
 
-func (r *transactionFileRepository) save() error {
-	r.fileMutex.RLock()
-	defer r.fileMutex.RUnlock()
-
+func (r *transactionFileRepository) snapshot() error {
 	transactions := make([]model.Transaction, 0, len(r.transactions))
 	for _, transaction := range r.transactions {
 		transactions = append(transactions, transaction)
@@ -73,24 +173,58 @@ func (r *transactionFileRepository) save() error {
 		return err
 	}
 
-	return ioutil.WriteFile(r.filePath, data, 0644)
+	return r.log.Snapshot(r.nextID, data)
 }
 
 func (r *transactionFileRepository) CreateTransaction(ctx context.Context, transaction model.Transaction) (model.Transaction, error) {
 	r.mapMutex.Lock()
 	defer r.mapMutex.Unlock()
 
+	transaction.ID = r.nextID + 1
+	if err := r.append(wal.OpPut, transaction); err != nil {
+		return model.Transaction{}, err
+	}
+
+	r.nextID = transaction.ID
+	r.put(transaction)
+
+	return transaction, nil
+}
+
+// ReserveTransactionID 다른 저장소와 한 트랜잭션으로 묶어 기록할 거래의 ID를 미리 발급합니다.
+// 커밋하지 못한 ID는 다시 발급하지 않고 건너뜁니다.
+func (r *transactionFileRepository) ReserveTransactionID() int64 {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
 	r.nextID++
-	transaction.ID = r.nextID
+	return r.nextID
+}
 
-	r.transactions[transaction.ID] = transaction
-	r.transactionsByAccountID[transaction.AccountID] = append(r.transactionsByAccountID[transaction.AccountID], transaction.ID)
+// ApplyTransactions 다른 저장소와 한 트랜잭션으로 묶은 거래를 로그와 맵에 반영합니다.
+// 이미 있는 거래는 건너뛰므로 트랜잭션 로그를 다시 재생해도 됩니다.
+func (r *transactionFileRepository) ApplyTransactions(transactions []model.Transaction) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
 
-	if err := r.save(); err != nil {
-		return model.Transaction{}, err
+	for _, transaction := range transactions {
+		if _, exists := r.transactions[transaction.ID]; exists {
+			continue
+		}
+		if err := r.log.Append(wal.OpPut, transaction); err != nil {
+			return err
+		}
+		r.put(transaction)
+		if transaction.ID > r.nextID {
+			r.nextID = transaction.ID
+		}
 	}
 
-	return transaction, nil
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
 }
 
 func (r *transactionFileRepository) GetTransactionByID(ctx context.Context, id int64) (model.Transaction, error) {
@@ -113,32 +247,30 @@ func (r *transactionFileRepository) UpdateTransaction(ctx context.Context, trans
 		return fmt.Errorf("transaction with ID %d not found", transaction.ID)
 	}
 
-	r.transactions[transaction.ID] = transaction
+	if err := r.append(wal.OpPut, transaction); err != nil {
+		return err
+	}
+
+	r.put(transaction)
 
-	return r.save()
+	return nil
 }
 
 func (r *transactionFileRepository) DeleteTransaction(ctx context.Context, id int64) error {
 	r.mapMutex.Lock()
 	defer r.mapMutex.Unlock()
 
-	transaction, exists := r.transactions[id]
-	if !exists {
+	if _, exists := r.transactions[id]; !exists {
 		return fmt.Errorf("transaction with ID %d not found", id)
 	}
 
-	delete(r.transactions, id)
-
-	// Remove transaction from transactionsByAccountID
-	userTransactions := r.transactionsByAccountID[transaction.AccountID]
-	for i, accID := range userTransactions {
-		if accID == id {
-			r.transactionsByAccountID[transaction.AccountID] = append(userTransactions[:i], userTransactions[i+1:]...)
-			break
-		}
+	if err := r.append(wal.OpDelete, id); err != nil {
+		return err
 	}
 
-	return r.save()
+	r.remove(id)
+
+	return nil
 }
 
 func (r *transactionFileRepository) GetTransactionsByAccountID(ctx context.Context, accountID int64) ([]model.Transaction, error) {
@@ -158,6 +290,58 @@ func (r *transactionFileRepository) GetTransactionsByAccountID(ctx context.Conte
 	return transactions, nil
 }
 
+func (r *transactionFileRepository) ListTransactions(ctx context.Context, query model.TransactionQuery) ([]model.Transaction, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
+	ids := r.transactionsByAccountID[query.AccountID]
+	transactions := []model.Transaction{}
+	add := func(transaction model.Transaction) bool {
+		if query.Limit > 0 && len(transactions) >= query.Limit {
+			return false
+		}
+		if query.Matches(transaction) {
+			transactions = append(transactions, transaction)
+		}
+		return true
+	}
+
+	if query.Ascending {
+		start := 0
+		if query.After != nil {
+			start = sort.Search(len(ids), func(i int) bool {
+				return query.After.Before(r.transactions[ids[i]].Cursor())
+			})
+		}
+		for i := start; i < len(ids); i++ {
+			transaction := r.transactions[ids[i]]
+			if !query.EndDate.IsZero() && transaction.CreatedAt.After(query.EndDate) {
+				break
+			}
+			if !add(transaction) {
+				break
+			}
+		}
+		return transactions, nil
+	}
+
+	end := len(ids)
+	if query.After != nil {
+		end = r.searchAccountIndex(ids, *query.After)
+	}
+	for i := end - 1; i >= 0; i-- {
+		transaction := r.transactions[ids[i]]
+		if !query.StartDate.IsZero() && transaction.CreatedAt.Before(query.StartDate) {
+			break
+		}
+		if !add(transaction) {
+			break
+		}
+	}
+
+	return transactions, nil
+}
+
 func (r *transactionFileRepository) GetAllTransactions(ctx context.Context) ([]model.Transaction, error) {
 	r.mapMutex.RLock()
 	defer r.mapMutex.RUnlock()
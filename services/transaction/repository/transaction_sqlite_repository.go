@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ebank/pkg/money"
+	"ebank/pkg/sqlite"
+	"ebank/services/transaction/model"
+	"ebank/services/transaction/service"
+)
+
+const transactionColumns = `id, account_id, amount, currency, transaction_type, counterparty_account_id, memo, created_at`
+
+type transactionSQLiteRepository struct {
+	db sqlite.DB
+}
+
+func NewTransactionSQLiteRepository(db sqlite.DB) service.TransactionRepository {
+	return &transactionSQLiteRepository{db: db}
+}
+
+func scanTransaction(row interface{ Scan(...any) error }) (model.Transaction, error) {
+	var transaction model.Transaction
+	var amount, createdAt int64
+	var currency string
+	if err := row.Scan(&transaction.ID, &transaction.AccountID, &amount, &currency, &transaction.TransactionType,
+		&transaction.CounterpartyAccountID, &transaction.Memo, &createdAt); err != nil {
+		return model.Transaction{}, err
+	}
+
+	transaction.Amount = money.New(amount, currency)
+	transaction.CreatedAt = sqlite.FromUnixNano(createdAt)
+
+	return transaction, nil
+}
+
+func (r *transactionSQLiteRepository) queryTransactions(ctx context.Context, query string, args ...any) ([]model.Transaction, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []model.Transaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *transactionSQLiteRepository) CreateTransaction(ctx context.Context, transaction model.Transaction) (model.Transaction, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO transactions (account_id, amount, currency, transaction_type, counterparty_account_id, memo, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		transaction.AccountID, transaction.Amount.Amount, transaction.Amount.Currency, transaction.TransactionType,
+		transaction.CounterpartyAccountID, transaction.Memo, sqlite.ToUnixNano(transaction.CreatedAt),
+	)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+
+	transaction.ID, err = result.LastInsertId()
+	if err != nil {
+		return model.Transaction{}, err
+	}
+
+	return transaction, nil
+}
+
+func (r *transactionSQLiteRepository) GetTransactionByID(ctx context.Context, id int64) (model.Transaction, error) {
+	transaction, err := scanTransaction(r.db.QueryRowContext(ctx, `SELECT `+transactionColumns+` FROM transactions WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Transaction{}, fmt.Errorf("transaction with ID %d not found", id)
+	}
+
+	return transaction, err
+}
+
+func (r *transactionSQLiteRepository) UpdateTransaction(ctx context.Context, transaction model.Transaction) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE transactions SET account_id = ?, amount = ?, currency = ?, transaction_type = ?, counterparty_account_id = ?, memo = ?, created_at = ? WHERE id = ?`,
+		transaction.AccountID, transaction.Amount.Amount, transaction.Amount.Currency, transaction.TransactionType,
+		transaction.CounterpartyAccountID, transaction.Memo, sqlite.ToUnixNano(transaction.CreatedAt), transaction.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("transaction with ID %d not found", transaction.ID)
+	}
+
+	return nil
+}
+
+func (r *transactionSQLiteRepository) DeleteTransaction(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM transactions WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("transaction with ID %d not found", id)
+	}
+
+	return nil
+}
+
+func (r *transactionSQLiteRepository) GetTransactionsByAccountID(ctx context.Context, accountID int64) ([]model.Transaction, error) {
+	return r.queryTransactions(ctx, `SELECT `+transactionColumns+` FROM transactions WHERE account_id = ? ORDER BY created_at, id`, accountID)
+}
+
+func (r *transactionSQLiteRepository) ListTransactions(ctx context.Context, query model.TransactionQuery) ([]model.Transaction, error) {
+	conditions := []string{`account_id = ?`}
+	args := []any{query.AccountID}
+
+	if len(query.TransactionTypes) > 0 {
+		conditions = append(conditions, `transaction_type IN (?`+strings.Repeat(`, ?`, len(query.TransactionTypes)-1)+`)`)
+		for _, transactionType := range query.TransactionTypes {
+			args = append(args, transactionType)
+		}
+	}
+	if query.MinAmount != nil {
+		conditions = append(conditions, `amount >= ?`)
+		args = append(args, *query.MinAmount)
+	}
+	if query.MaxAmount != nil {
+		conditions = append(conditions, `amount <= ?`)
+		args = append(args, *query.MaxAmount)
+	}
+	if query.CounterpartyAccountID != 0 {
+		conditions = append(conditions, `counterparty_account_id = ?`)
+		args = append(args, query.CounterpartyAccountID)
+	}
+	if !query.StartDate.IsZero() {
+		conditions = append(conditions, `created_at >= ?`)
+		args = append(args, sqlite.ToUnixNano(query.StartDate))
+	}
+	if !query.EndDate.IsZero() {
+		conditions = append(conditions, `created_at <= ?`)
+		args = append(args, sqlite.ToUnixNano(query.EndDate))
+	}
+	comparison, order := `<`, `DESC`
+	if query.Ascending {
+		comparison, order = `>`, `ASC`
+	}
+	if query.After != nil {
+		createdAt := sqlite.ToUnixNano(query.After.CreatedAt)
+		conditions = append(conditions, `(created_at `+comparison+` ? OR (created_at = ? AND id `+comparison+` ?))`)
+		args = append(args, createdAt, createdAt, query.After.ID)
+	}
+
+	statement := `SELECT ` + transactionColumns + ` FROM transactions WHERE ` + strings.Join(conditions, ` AND `) +
+		` ORDER BY created_at ` + order + `, id ` + order
+	if query.Limit > 0 {
+		statement += ` LIMIT ?`
+		args = append(args, query.Limit)
+	}
+
+	return r.queryTransactions(ctx, statement, args...)
+}
+
+func (r *transactionSQLiteRepository) GetAllTransactions(ctx context.Context) ([]model.Transaction, error) {
+	return r.queryTransactions(ctx, `SELECT `+transactionColumns+` FROM transactions ORDER BY id`)
+}
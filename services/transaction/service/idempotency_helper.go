@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"ebank/pkg/jwt_manager"
+	"ebank/services/transaction/model"
+)
+
+const (
+	idempotencyKeyMetadata = "idempotency-key"
+	// idempotencyKeyCleanupInterval 오래된 멱등성 키를 지우는 최대 간격입니다.
+	idempotencyKeyCleanupInterval = time.Hour
+)
+
+// withIdempotency 같은 멱등성 키로 들어온 재시도에는 최초 응답을 그대로 돌려주고,
+// 같은 키에 다른 요청 내용이 오면 거부합니다. 키가 없으면 handler를 그대로 실행합니다.
+// handler를 실행하기 전에 키를 예약해 두므로, 처리 결과를 저장하지 못했거나 다른 프로세스가 처리 중인 키로
+// 재시도하면 다시 처리하지 않고 Aborted를 반환합니다. 실패한 요청은 예약을 지우므로 같은 키로 다시 시도할 수 있습니다.
+func withIdempotency[T proto.Message](
+	ctx context.Context,
+	repository IdempotencyRepository,
+	method string,
+	req interface {
+		proto.Message
+		GetIdempotencyKey() string
+	},
+	handler func() (T, error),
+) (T, error) {
+	var empty T
+
+	key := idempotencyKeyFromContext(ctx, req)
+	if key == "" {
+		return handler()
+	}
+	var userID int64
+	if claims, ok := jwt_manager.FromContext(ctx); ok {
+		userID = claims.UserID
+	}
+
+	requestHash, err := hashRequest(method, req)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, "Failed to hash request")
+	}
+
+	if err := repository.LockIdempotencyKey(ctx, userID, key); err != nil {
+		return empty, status.Errorf(codes.Internal, "Failed to lock idempotency key")
+	}
+	defer repository.UnlockIdempotencyKey(ctx, userID, key)
+
+	stored, err := repository.GetIdempotencyKey(ctx, userID, key)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, "Failed to load idempotency key")
+	}
+	if stored == nil && userID != 0 {
+		if stored, err = legacyIdempotencyKey(ctx, repository, key, method, requestHash); err != nil {
+			return empty, status.Errorf(codes.Internal, "Failed to load idempotency key")
+		}
+	}
+	if stored == nil {
+		err := repository.CreateIdempotencyKey(ctx, model.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      method,
+			RequestHash: requestHash,
+			Status:      model.IdempotencyStatusPending,
+			CreatedAt:   timestamppb.Now().AsTime(),
+		})
+		if err == nil {
+			return runIdempotent(ctx, repository, userID, key, handler)
+		}
+		// 다른 프로세스가 그 사이에 같은 키를 예약했으면 그 키를 다시 읽어 아래에서 확인합니다.
+		if stored, err = repository.GetIdempotencyKey(ctx, userID, key); err != nil || stored == nil {
+			return empty, status.Errorf(codes.Internal, "Failed to store idempotency key")
+		}
+	}
+
+	if stored.Method != method || stored.RequestHash != requestHash {
+		return empty, status.Errorf(codes.FailedPrecondition, "Idempotency key was already used with a different request")
+	}
+	if !stored.IsCompleted() {
+		return empty, status.Errorf(codes.Aborted, "Request with this idempotency key is in progress or its result was not saved, check the transaction history before using a new key")
+	}
+
+	resp := empty.ProtoReflect().New().Interface().(T)
+	if err := proto.Unmarshal(stored.Response, resp); err != nil {
+		return empty, status.Errorf(codes.Internal, "Failed to replay stored response")
+	}
+	return resp, nil
+}
+
+// legacyIdempotencyKey 사용자별로 나누기 전에 저장해 사용자 0으로 옮긴 키 가운데 같은 요청을 처리한 키를 찾습니다.
+// 예전 키는 누구의 키인지 모르므로 요청 내용까지 같을 때만 쓰고, 다른 요청이면 없는 것으로 보아 사용자의 키로 새로 예약합니다.
+// 요청한 계좌의 권한은 withIdempotency를 부르기 전에 확인하므로 다른 사용자의 응답을 돌려주지 않습니다.
+func legacyIdempotencyKey(ctx context.Context, repository IdempotencyRepository, key, method, requestHash string) (*model.IdempotencyKey, error) {
+	stored, err := repository.GetIdempotencyKey(ctx, 0, key)
+	if err != nil || stored == nil {
+		return nil, err
+	}
+	if stored.Method != method || stored.RequestHash != requestHash {
+		return nil, nil
+	}
+
+	return stored, nil
+}
+
+// runIdempotent 예약한 키로 handler를 실행하고 결과를 저장합니다. 결과를 저장하지 못하면 돈은 이미 움직였으므로
+// 예약을 남겨 두어 같은 키로 재시도해도 다시 처리하지 않게 합니다.
+func runIdempotent[T proto.Message](ctx context.Context, repository IdempotencyRepository, userID int64, key string, handler func() (T, error)) (T, error) {
+	var empty T
+
+	resp, err := handler()
+	if err != nil {
+		_ = repository.DeleteIdempotencyKey(ctx, userID, key)
+		return empty, err
+	}
+
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		return empty, status.Errorf(codes.Internal, "Failed to store idempotency key")
+	}
+	if err := repository.CompleteIdempotencyKey(ctx, userID, key, data); err != nil {
+		return empty, status.Errorf(codes.Internal, "Failed to store idempotency key")
+	}
+
+	return resp, nil
+}
+
+// idempotencyKeyFromContext 요청 필드를 우선하고, 없으면 gRPC 메타데이터에서 찾습니다.
+func idempotencyKeyFromContext(ctx context.Context, req interface{ GetIdempotencyKey() string }) string {
+	if key := req.GetIdempotencyKey(); key != "" {
+		return key
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(idempotencyKeyMetadata); len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+// hashRequest 멱등성 키 필드를 제외한 요청 내용의 해시를 구합니다.
+func hashRequest(method string, req proto.Message) (string, error) {
+	clone := proto.Clone(req)
+	keyField := clone.ProtoReflect().Descriptor().Fields().ByName("idempotency_key")
+	if keyField != nil {
+		clone.ProtoReflect().Clear(keyField)
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(clone)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(append([]byte(method+"\x00"), data...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// RunIdempotencyKeyCleanup ctx가 끝날 때까지 ttl보다 오래된 멱등성 키를 주기적으로 지웁니다. ttl이 0이면 지우지 않습니다.
+// 지운 키로 재시도하면 새 요청으로 처리하므로 ttl은 클라이언트가 재시도하는 기간보다 길어야 합니다.
+func RunIdempotencyKeyCleanup(ctx context.Context, repository IdempotencyRepository, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(min(ttl, idempotencyKeyCleanupInterval))
+	defer ticker.Stop()
+
+	for {
+		if err := repository.DeleteIdempotencyKeysBefore(ctx, time.Now().Add(-ttl)); err != nil {
+			log.Printf("failed to delete expired idempotency keys: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
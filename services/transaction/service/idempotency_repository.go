@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"ebank/services/transaction/model"
+)
+
+type IdempotencyRepository interface {
+	// GetIdempotencyKey 저장된 키가 없으면 nil을 반환합니다.
+	GetIdempotencyKey(ctx context.Context, userID int64, key string) (*model.IdempotencyKey, error)
+	// CreateIdempotencyKey 키를 예약합니다. 같은 사용자의 같은 키가 이미 있으면 다른 프로세스가 먼저 예약했더라도 오류를 반환합니다.
+	CreateIdempotencyKey(ctx context.Context, idempotencyKey model.IdempotencyKey) error
+	// CompleteIdempotencyKey 예약한 키에 처리 결과를 저장합니다.
+	CompleteIdempotencyKey(ctx context.Context, userID int64, key string, response []byte) error
+	// DeleteIdempotencyKey 처리에 실패한 요청의 예약을 지워 같은 키로 다시 시도할 수 있게 합니다.
+	DeleteIdempotencyKey(ctx context.Context, userID int64, key string) error
+	// DeleteIdempotencyKeysBefore before 이전에 예약한 키를 처리 결과와 함께 지웁니다.
+	DeleteIdempotencyKeysBefore(ctx context.Context, before time.Time) error
+	LockIdempotencyKey(ctx context.Context, userID int64, key string) error
+	UnlockIdempotencyKey(ctx context.Context, userID int64, key string) error
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ebank "ebank/api/v1"
+	"ebank/pkg/money"
+	accountModel "ebank/services/account/model"
+	accountService "ebank/services/account/service"
+	ledgerModel "ebank/services/ledger/model"
+	"ebank/services/transaction/model"
+)
+
+const (
+	// maturedReason 만기가 되어 해지한 정기예금에 남기는 이유입니다.
+	maturedReason = "Matured"
+	// maturityBatchSize MatureAccounts가 저장소에서 한 번에 읽는 계좌 수입니다.
+	maturityBatchSize = 100
+	// maturityInterestMemo 만기가 지난 정기예금에 붙인 이자 거래의 메모입니다.
+	maturityInterestMemo = "Maturity interest"
+)
+
+// MatureAccounts 만기가 된 정기예금의 잔액을 연결 계좌로 옮기고 해지합니다.
+// 동결된 계좌는 동결을 풀 때까지 건너뛰고, 처리하지 못한 계좌는 다음 처리 때 다시 시도합니다.
+func (s *transactionService) MatureAccounts(ctx context.Context, _ *ebank.MatureAccountsRequest) (*ebank.MatureAccountsResponse, error) {
+	query := accountModel.AccountQuery{
+		Statuses:  []string{accountModel.AccountStatusActive, accountModel.AccountStatusDormant},
+		MaturesBy: time.Now(),
+		Limit:     maturityBatchSize,
+	}
+
+	resp := &ebank.MatureAccountsResponse{}
+	for {
+		accounts, err := s.accountRepository.ListAccounts(ctx, query)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to load accounts")
+		}
+
+		for _, account := range accounts {
+			matured, err := s.closeAccount(ctx, account.ID, account.LinkedAccountID, maturedReason, false)
+			if err != nil {
+				resp.FailedAccountIds = append(resp.FailedAccountIds, account.ID)
+				continue
+			}
+			resp.Matured = append(resp.Matured, matured)
+		}
+
+		if len(accounts) < maturityBatchSize {
+			return resp, nil
+		}
+		query.AfterID = accounts[len(accounts)-1].ID
+	}
+}
+
+// creditInterest 정기예금의 만기 이자를 repos에 거래와 분개로 기록하고 account의 잔액에 더해 저장합니다.
+func creditInterest(ctx context.Context, repos Repositories, account *accountModel.Account, interest money.Money, createdAt time.Time) (model.Transaction, error) {
+	transaction, err := repos.Transactions.CreateTransaction(ctx, model.Transaction{
+		AccountID:       account.ID,
+		Amount:          interest,
+		TransactionType: model.TransactionTypeInterest,
+		Memo:            maturityInterestMemo,
+		CreatedAt:       createdAt,
+	})
+	if err != nil {
+		return model.Transaction{}, status.Errorf(codes.Internal, "Failed to save transaction data")
+	}
+	if _, err := repos.Ledger.Post(ctx, ledgerModel.NewInterestEntry(account.ID, interest, transaction.ID)); err != nil {
+		return model.Transaction{}, status.Errorf(codes.Internal, "Failed to post journal entry")
+	}
+
+	if err := account.AddBalance(interest); err != nil {
+		return model.Transaction{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := repos.Accounts.UpdateAccount(ctx, account); err != nil {
+		return model.Transaction{}, accountService.SaveAccountError(err)
+	}
+
+	return transaction, nil
+}
+
+// RunMaturity ctx가 끝날 때까지 interval마다 만기가 된 정기예금을 처리합니다. interval이 0이면 처리하지 않습니다.
+func RunMaturity(ctx context.Context, server ebank.TransactionServiceServer, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		resp, err := server.MatureAccounts(ctx, &ebank.MatureAccountsRequest{})
+		if err != nil {
+			log.Printf("failed to mature accounts: %v", err)
+		} else if len(resp.GetFailedAccountIds()) > 0 {
+			log.Printf("failed to mature accounts %v", resp.GetFailedAccountIds())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
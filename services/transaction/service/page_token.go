@@ -0,0 +1,28 @@
+package service
+
+import (
+	"time"
+
+	"ebank/pkg/pagetoken"
+	"ebank/services/transaction/model"
+)
+
+// transactionCursor 페이지 토큰에 담는 거래 위치입니다.
+type transactionCursor struct {
+	CreatedAt int64 `json:"t"`
+	ID        int64 `json:"i"`
+}
+
+func encodePageToken(cursor model.TransactionCursor, filter string) string {
+	return pagetoken.Encode(transactionCursor{CreatedAt: cursor.CreatedAt.UnixNano(), ID: cursor.ID}, filter)
+}
+
+// decodePageToken 토큰이 손상되었거나 filter와 다른 조건으로 만든 토큰이면 false를 반환합니다.
+func decodePageToken(token string, filter string) (model.TransactionCursor, bool) {
+	var decoded transactionCursor
+	if !pagetoken.Decode(token, filter, &decoded) {
+		return model.TransactionCursor{}, false
+	}
+
+	return model.TransactionCursor{CreatedAt: time.Unix(0, decoded.CreatedAt), ID: decoded.ID}, true
+}
@@ -11,6 +11,9 @@ type TransactionRepository interface {
 	GetTransactionByID(ctx context.Context, id int64) (model.Transaction, error)
 	UpdateTransaction(ctx context.Context, transaction model.Transaction) error
 	DeleteTransaction(ctx context.Context, id int64) error
+	// GetTransactionsByAccountID 계좌의 모든 거래를 오래된 순서로 반환합니다.
 	GetTransactionsByAccountID(ctx context.Context, accountID int64) ([]model.Transaction, error)
+	// ListTransactions 조건에 맞는 거래를 최신 순서로 query.Limit건까지 반환합니다.
+	ListTransactions(ctx context.Context, query model.TransactionQuery) ([]model.Transaction, error)
 	GetAllTransactions(ctx context.Context) ([]model.Transaction, error)
 }
@@ -1,18 +1,750 @@
 package service
 
 import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	ebank "ebank/api/v1"
+	"ebank/pkg/feed"
+	"ebank/pkg/jwt_manager"
+	"ebank/pkg/money"
+	"ebank/pkg/pagetoken"
+	accountModel "ebank/services/account/model"
+	accountService "ebank/services/account/service"
+	ledgerModel "ebank/services/ledger/model"
+	ledgerService "ebank/services/ledger/service"
+	"ebank/services/transaction/model"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+	// watchBatchSize WatchTransactions가 저장소에서 한 번에 읽는 거래 수입니다.
+	watchBatchSize = 100
+	// watchPollInterval 변경 알림이 없어도 새 거래를 확인하는 간격입니다.
+	// 다른 프로세스가 같은 SQLite 파일에 거래를 기록하면 알림이 오지 않으므로 이 간격으로 확인합니다.
+	watchPollInterval = 5 * time.Second
 )
 
+// historyPageFields 페이지 토큰이 바뀌어도 같은 조회로 보는 요청 필드입니다.
+var historyPageFields = []string{"page_token", "page_size"}
+
+// otpCodeMetadata 큰 금액의 출금과 이체에 필요한 OTP 코드를 담는 메타데이터 키입니다.
+const otpCodeMetadata = "otp-code"
+
+// closurePayoutMemo 계좌를 해지하며 남은 잔액을 옮긴 이체의 메모입니다.
+const closurePayoutMemo = "Account closure payout"
+
 type transactionService struct {
 	ebank.UnimplementedTransactionServiceServer
+	userHelper            accountService.UserHelper
+	accountRepository     accountService.AccountRepository
 	transactionRepository TransactionRepository
+	idempotencyRepository IdempotencyRepository
+	ledger                ledgerService.Ledger
+	unitOfWork            UnitOfWork
+	stepUpThresholds      map[string]money.Money
+	changes               *feed.Feed
 }
 
 func NewTransactionService(
+	userHelper accountService.UserHelper,
+	accountRepository accountService.AccountRepository,
 	transactionRepository TransactionRepository,
+	idempotencyRepository IdempotencyRepository,
+	ledger ledgerService.Ledger,
+	unitOfWork UnitOfWork,
+	stepUpThresholds map[string]money.Money,
+	changes *feed.Feed,
 ) ebank.TransactionServiceServer {
 	return &transactionService{
+		userHelper:            userHelper,
+		accountRepository:     accountRepository,
 		transactionRepository: transactionRepository,
+		idempotencyRepository: idempotencyRepository,
+		ledger:                ledger,
+		unitOfWork:            unitOfWork,
+		stepUpThresholds:      stepUpThresholds,
+		changes:               changes,
+	}
+}
+
+func (s *transactionService) Deposit(ctx context.Context, req *ebank.DepositRequest) (*ebank.TransactionResponse, error) {
+	amount, err := parseAmount(req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccount(ctx, req.GetAccountId()); err != nil {
+		return nil, err
+	}
+
+	return withIdempotency(ctx, s.idempotencyRepository, "Deposit", req, func() (*ebank.TransactionResponse, error) {
+		return s.applyTransaction(ctx, req.GetAccountId(), amount, model.TransactionTypeDeposit)
+	})
+}
+
+func (s *transactionService) Withdraw(ctx context.Context, req *ebank.WithdrawRequest) (*ebank.TransactionResponse, error) {
+	amount, err := parseAmount(req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeAccount(ctx, req.GetAccountId()); err != nil {
+		return nil, err
+	}
+
+	return withIdempotency(ctx, s.idempotencyRepository, "Withdraw", req, func() (*ebank.TransactionResponse, error) {
+		if err := s.requireStepUp(ctx, amount); err != nil {
+			return nil, err
+		}
+		return s.applyTransaction(ctx, req.GetAccountId(), amount, model.TransactionTypeWithdrawal)
+	})
+}
+
+func (s *transactionService) Transfer(ctx context.Context, req *ebank.TransferRequest) (*ebank.TransferResponse, error) {
+	amount, err := parseAmount(req.GetAmount())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFromAccountId() == req.GetToAccountId() {
+		return nil, status.Errorf(codes.InvalidArgument, "Cannot transfer to the same account")
 	}
+	// 받는 계좌는 다른 사람의 계좌일 수 있으므로 보내는 계좌만 확인합니다.
+	if err := s.authorizeAccount(ctx, req.GetFromAccountId()); err != nil {
+		return nil, err
+	}
+
+	return withIdempotency(ctx, s.idempotencyRepository, "Transfer", req, func() (*ebank.TransferResponse, error) {
+		if err := s.requireStepUp(ctx, amount); err != nil {
+			return nil, err
+		}
+		return s.transfer(ctx, req.GetFromAccountId(), req.GetToAccountId(), amount, req.GetMemo())
+	})
+}
+
+func (s *transactionService) CloseAccount(ctx context.Context, req *ebank.CloseAccountRequest) (*ebank.CloseAccountResponse, error) {
+	if req.GetPayoutAccountId() == req.GetAccountId() {
+		return nil, status.Errorf(codes.InvalidArgument, "Cannot pay out to the account being closed")
+	}
+	if err := s.authorizeAccount(ctx, req.GetAccountId()); err != nil {
+		return nil, err
+	}
+
+	return withIdempotency(ctx, s.idempotencyRepository, "CloseAccount", req, func() (*ebank.CloseAccountResponse, error) {
+		return s.closeAccount(ctx, req.GetAccountId(), req.GetPayoutAccountId(), req.GetReason(), true)
+	})
+}
+
+// closeAccount 남은 잔액을 payoutAccountID로 옮기고 계좌를 해지합니다. 계좌와 거래 내역은 지우지 않습니다.
+// 만기 처리처럼 사용자 요청이 아니면 stepUp 없이 큰 금액도 옮깁니다.
+func (s *transactionService) closeAccount(ctx context.Context, accountID, payoutAccountID int64, reason string, stepUp bool) (*ebank.CloseAccountResponse, error) {
+	unlock, err := s.lockAccounts(ctx, accountID, payoutAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	account, err := s.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil || account == nil {
+		return nil, status.Errorf(codes.NotFound, "Account not found")
+	}
+	if !account.CanTransitionTo(accountModel.AccountStatusClosed) {
+		return nil, accountStatusError(*account)
+	}
+	if err := accountService.CheckNotLinked(ctx, s.accountRepository, *account); err != nil {
+		return nil, err
+	}
+
+	// 만기가 지난 정기예금은 약정 이자를 붙인 뒤 이자까지 함께 옮깁니다.
+	interest := money.Zero(account.Balance.Currency)
+	if !account.MaturesAt.IsZero() && !account.IsBeforeMaturity(time.Now()) {
+		interest = account.MaturityInterest()
+	}
+
+	resp := &ebank.CloseAccountResponse{}
+	var payoutAccount *accountModel.Account
+	var payoutAmount, payoutPenalty money.Money
+	if !account.Balance.IsZero() {
+		if payoutAccountID == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "Account balance must be zero or a payout account is required")
+		}
+		foundPayoutAccount, err := s.accountRepository.GetAccountByID(ctx, payoutAccountID)
+		if err != nil || foundPayoutAccount == nil {
+			return nil, status.Errorf(codes.NotFound, "Account not found")
+		}
+		if !foundPayoutAccount.CanCredit() {
+			return nil, accountStatusError(*foundPayoutAccount)
+		}
+		penalty, err := s.withdrawalPenalty(ctx, *account, account.Balance, true)
+		if err != nil {
+			return nil, err
+		}
+		if stepUp {
+			if err := s.requireStepUp(ctx, account.Balance); err != nil {
+				return nil, err
+			}
+		}
+
+		payout, err := account.Balance.Add(interest)
+		if err == nil {
+			payout, err = payout.Sub(penalty)
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		payoutAccount, payoutAmount, payoutPenalty = foundPayoutAccount, payout, penalty
+	}
+
+	// 잔액을 옮기는 이체와 해지를 함께 저장해 잔액만 빠져나가고 해지되지 않은 계좌가 남지 않도록 합니다.
+	err = s.unitOfWork.RunInTx(ctx, func(repos Repositories) error {
+		if interest.IsPositive() {
+			credited, err := creditInterest(ctx, repos, account, interest, timestamppb.Now().AsTime())
+			if err != nil {
+				return err
+			}
+			resp.Interest = toTransactionDto(credited)
+		}
+		if payoutAccount != nil {
+			transferred, err := s.transferLocked(ctx, repos, account, payoutAccount, payoutAmount, payoutPenalty, closurePayoutMemo)
+			if err != nil {
+				return err
+			}
+			resp.Payout = transferred.Outgoing
+			resp.Penalty = transferred.Penalty
+		}
+
+		if err := account.ChangeStatus(accountModel.AccountStatusClosed, reason, timestamppb.Now().AsTime()); err != nil {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if err := repos.Accounts.UpdateAccount(ctx, account); err != nil {
+			return accountService.SaveAccountError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, txError(err)
+	}
+	s.changes.Publish(account.ID)
+	if payoutAccount != nil {
+		s.changes.Publish(payoutAccount.ID)
+	}
+	resp.Account = accountService.ToAccountDto(*account)
+
+	return resp, nil
+}
+
+func (s *transactionService) transfer(ctx context.Context, fromAccountID, toAccountID int64, amount money.Money, memo string) (*ebank.TransferResponse, error) {
+	unlock, err := s.lockAccounts(ctx, fromAccountID, toAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	fromAccount, err := s.accountRepository.GetAccountByID(ctx, fromAccountID)
+	if err != nil || fromAccount == nil {
+		return nil, status.Errorf(codes.NotFound, "Account not found")
+	}
+	toAccount, err := s.accountRepository.GetAccountByID(ctx, toAccountID)
+	if err != nil || toAccount == nil {
+		return nil, status.Errorf(codes.NotFound, "Account not found")
+	}
+
+	if !fromAccount.CanDebit() {
+		return nil, accountStatusError(*fromAccount)
+	}
+	if !toAccount.CanCredit() {
+		return nil, accountStatusError(*toAccount)
+	}
+	penalty, err := s.withdrawalPenalty(ctx, *fromAccount, amount, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *ebank.TransferResponse
+	err = s.unitOfWork.RunInTx(ctx, func(repos Repositories) error {
+		var err error
+		resp, err = s.transferLocked(ctx, repos, fromAccount, toAccount, amount, penalty, memo)
+		return err
+	})
+	if err != nil {
+		return nil, txError(err)
+	}
+	s.changes.Publish(fromAccount.ID)
+	s.changes.Publish(toAccount.ID)
+
+	return resp, nil
+}
+
+// transferLocked 두 계좌를 잠근 상태에서 repos로 이체를 기록하고 잔액을 바꿉니다. fromAccount와 toAccount에도 반영합니다.
+// penalty가 있으면 출금 계좌에서 수수료 거래로 함께 뺍니다. 저장은 RunInTx가 끝나야 반영되므로 변경은 호출한 쪽이 알립니다.
+func (s *transactionService) transferLocked(ctx context.Context, repos Repositories, fromAccount, toAccount *accountModel.Account, amount money.Money, penalty money.Money, memo string) (*ebank.TransferResponse, error) {
+	if fromAccount.Balance.Currency != amount.Currency || toAccount.Balance.Currency != amount.Currency {
+		return nil, status.Errorf(codes.InvalidArgument, "Currency mismatch")
+	}
+	total, err := amount.Add(penalty)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !fromAccount.HasSufficientBalance(total) {
+		return nil, status.Errorf(codes.FailedPrecondition, "Insufficient balance")
+	}
+
+	createdAt := timestamppb.Now().AsTime()
+
+	outgoing, err := repos.Transactions.CreateTransaction(ctx, model.Transaction{
+		AccountID:             fromAccount.ID,
+		Amount:                amount,
+		TransactionType:       model.TransactionTypeTransferOut,
+		CounterpartyAccountID: toAccount.ID,
+		Memo:                  memo,
+		CreatedAt:             createdAt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save transaction data")
+	}
+
+	incoming, err := repos.Transactions.CreateTransaction(ctx, model.Transaction{
+		AccountID:             toAccount.ID,
+		Amount:                amount,
+		TransactionType:       model.TransactionTypeTransferIn,
+		CounterpartyAccountID: fromAccount.ID,
+		Memo:                  memo,
+		CreatedAt:             createdAt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save transaction data")
+	}
+
+	journalEntry := ledgerModel.NewTransferEntry(fromAccount.ID, toAccount.ID, amount, outgoing.ID, incoming.ID)
+	fee, err := recordPenalty(ctx, repos.Transactions, fromAccount.ID, penalty, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if fee != nil {
+		journalEntry = journalEntry.WithFee(fromAccount.ID, penalty, fee.ID)
+	}
+	if _, err := repos.Ledger.Post(ctx, journalEntry); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to post journal entry")
+	}
+
+	if err := fromAccount.SubtractBalance(total); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := repos.Accounts.UpdateAccount(ctx, fromAccount); err != nil {
+		return nil, accountService.SaveAccountError(err)
+	}
+	if err := toAccount.AddBalance(amount); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := repos.Accounts.UpdateAccount(ctx, toAccount); err != nil {
+		return nil, accountService.SaveAccountError(err)
+	}
+
+	resp := &ebank.TransferResponse{
+		Outgoing:   toTransactionDto(outgoing),
+		Incoming:   toTransactionDto(incoming),
+		NewBalance: money.ToProto(fromAccount.Balance),
+	}
+	if fee != nil {
+		resp.Penalty = toTransactionDto(*fee)
+	}
+
+	return resp, nil
+}
+
+func (s *transactionService) GetTransactionHistory(ctx context.Context, req *ebank.GetTransactionHistoryRequest) (*ebank.GetTransactionHistoryResponse, error) {
+	account, err := s.accountRepository.GetAccountByID(ctx, req.GetAccountId())
+	if err != nil || account == nil {
+		return nil, status.Errorf(codes.NotFound, "Account not found")
+	}
+	if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
+		return nil, err
+	}
+
+	query, err := historyQuery(account.ID, account.Balance.Currency, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 한 건 더 읽어 다음 페이지가 있는지 확인합니다.
+	pageSize := query.Limit
+	query.Limit++
+	transactions, err := s.transactionRepository.ListTransactions(ctx, query)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to load transaction data")
+	}
+
+	resp := &ebank.GetTransactionHistoryResponse{Transactions: make([]*ebank.Transaction, 0, len(transactions))}
+	if len(transactions) > pageSize {
+		transactions = transactions[:pageSize]
+		resp.NextPageToken = encodePageToken(transactions[pageSize-1].Cursor(), pagetoken.Filter(req, historyPageFields...))
+	}
+	for _, transaction := range transactions {
+		resp.Transactions = append(resp.Transactions, toTransactionDto(transaction))
+	}
+
+	return resp, nil
+}
+
+func (s *transactionService) WatchTransactions(req *ebank.WatchTransactionsRequest, stream ebank.TransactionService_WatchTransactionsServer) error {
+	ctx := stream.Context()
+
+	account, err := s.accountRepository.GetAccountByID(ctx, req.GetAccountId())
+	if err != nil || account == nil {
+		return status.Errorf(codes.NotFound, "Account not found")
+	}
+	if _, err := s.userHelper.ValidateUser(ctx, account.CustomerID); err != nil {
+		return err
+	}
+
+	// 구독한 뒤에 시작 위치를 정해야 그 사이에 기록된 거래를 놓치지 않습니다.
+	changes, cancel := s.changes.Subscribe(account.ID)
+	defer cancel()
+
+	filter := watchFilter(account.ID)
+	var cursor *model.TransactionCursor
+	if req.GetSince() != "" {
+		since, ok := decodePageToken(req.GetSince(), filter)
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "Invalid cursor")
+		}
+		cursor = &since
+	} else {
+		latest, err := s.transactionRepository.ListTransactions(ctx, model.TransactionQuery{AccountID: account.ID, Limit: 1})
+		if err != nil {
+			return status.Errorf(codes.Internal, "Failed to load transaction data")
+		}
+		if len(latest) > 0 {
+			latestCursor := latest[0].Cursor()
+			cursor = &latestCursor
+		}
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			transactions, err := s.transactionRepository.ListTransactions(ctx, model.TransactionQuery{
+				AccountID: account.ID,
+				After:     cursor,
+				Ascending: true,
+				Limit:     watchBatchSize,
+			})
+			if err != nil {
+				return status.Errorf(codes.Internal, "Failed to load transaction data")
+			}
+
+			for _, transaction := range transactions {
+				next := transaction.Cursor()
+				if err := stream.Send(&ebank.TransactionEvent{
+					Transaction: toTransactionDto(transaction),
+					Cursor:      encodePageToken(next, filter),
+				}); err != nil {
+					return err
+				}
+				cursor = &next
+			}
+
+			if len(transactions) < watchBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changes:
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchFilter WatchTransactions의 cursor를 다른 계좌에 쓰지 못하게 cursor에 담는 값입니다.
+func watchFilter(accountID int64) string {
+	return "watch:" + strconv.FormatInt(accountID, 10)
+}
+
+// historyQuery 요청을 저장소 조회 조건으로 바꿉니다. 금액 조건은 계좌 통화의 최소 화폐 단위로 비교합니다.
+func historyQuery(accountID int64, currency string, req *ebank.GetTransactionHistoryRequest) (model.TransactionQuery, error) {
+	query := model.TransactionQuery{
+		AccountID:             accountID,
+		TransactionTypes:      req.GetTransactionTypes(),
+		CounterpartyAccountID: req.GetCounterpartyAccountId(),
+		Limit:                 defaultPageSize,
+	}
+
+	if req.StartDate != nil {
+		query.StartDate = req.StartDate.AsTime()
+	}
+	if req.EndDate != nil {
+		query.EndDate = req.EndDate.AsTime()
+	}
+	if req.StartDate != nil && req.EndDate != nil && query.EndDate.Before(query.StartDate) {
+		return model.TransactionQuery{}, status.Errorf(codes.InvalidArgument, "End date must not be before start date")
+	}
+
+	switch {
+	case req.GetPageSize() < 0:
+		return model.TransactionQuery{}, status.Errorf(codes.InvalidArgument, "Page size must not be negative")
+	case req.GetPageSize() > maxPageSize:
+		query.Limit = maxPageSize
+	case req.GetPageSize() > 0:
+		query.Limit = int(req.GetPageSize())
+	}
+
+	for _, transactionType := range query.TransactionTypes {
+		if !model.IsValidTransactionType(transactionType) {
+			return model.TransactionQuery{}, status.Errorf(codes.InvalidArgument, "Unknown transaction type %q", transactionType)
+		}
+	}
+
+	var err error
+	if query.MinAmount, err = amountBound(req.MinAmount, currency); err != nil {
+		return model.TransactionQuery{}, err
+	}
+	if query.MaxAmount, err = amountBound(req.MaxAmount, currency); err != nil {
+		return model.TransactionQuery{}, err
+	}
+	if query.MinAmount != nil && query.MaxAmount != nil && *query.MaxAmount < *query.MinAmount {
+		return model.TransactionQuery{}, status.Errorf(codes.InvalidArgument, "Maximum amount must not be less than minimum amount")
+	}
+
+	if req.GetPageToken() != "" {
+		cursor, ok := decodePageToken(req.GetPageToken(), pagetoken.Filter(req, historyPageFields...))
+		if !ok {
+			return model.TransactionQuery{}, status.Errorf(codes.InvalidArgument, "Invalid page token")
+		}
+		query.After = &cursor
+	}
+
+	return query, nil
+}
+
+func amountBound(amount *ebank.Money, currency string) (*int64, error) {
+	if amount == nil {
+		return nil, nil
+	}
+
+	parsed, err := money.FromProto(amount)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if parsed.Currency != currency {
+		return nil, status.Errorf(codes.InvalidArgument, "Currency mismatch")
+	}
+
+	return &parsed.Amount, nil
+}
+
+// authorizeAccount 요청한 사용자가 계좌 주인인지 확인합니다.
+func (s *transactionService) authorizeAccount(ctx context.Context, accountID int64) error {
+	account, err := s.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil || account == nil {
+		return status.Errorf(codes.NotFound, "Account not found")
+	}
+
+	_, err = s.userHelper.ValidateUser(ctx, account.CustomerID)
+	return err
+}
+
+// requireStepUp 기준 금액을 넘는 출금과 이체는 메타데이터의 OTP 코드로 한 번 더 인증합니다.
+// 멱등성 키로 재시도한 요청은 저장된 응답을 돌려주므로 코드를 다시 요구하지 않습니다.
+// 2단계 인증을 등록하지 않은 사용자는 등록하기 전까지 기준 금액을 넘겨 출금하거나 이체할 수 없습니다.
+func (s *transactionService) requireStepUp(ctx context.Context, amount money.Money) error {
+	threshold, ok := s.stepUpThresholds[amount.Currency]
+	if !ok {
+		return nil
+	}
+	if cmp, err := amount.Cmp(threshold); err != nil || cmp <= 0 {
+		return nil
+	}
+
+	claims, ok := jwt_manager.FromContext(ctx)
+	if !ok {
+		return status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+
+	var code string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(otpCodeMetadata); len(values) > 0 {
+			code = values[0]
+		}
+	}
+	if code == "" {
+		// 2단계 인증을 등록하지 않은 사용자는 코드를 받을 수 없으므로 먼저 등록해야 한다고 알립니다.
+		user, err := s.userHelper.ValidateUser(ctx, claims.UserID)
+		if err != nil {
+			return err
+		}
+		if !user.TOTPEnabled {
+			return status.Errorf(codes.FailedPrecondition, "Amounts over %s require two-factor authentication, enable it with EnrollTOTP and ConfirmTOTP first", threshold)
+		}
+		return status.Errorf(codes.FailedPrecondition, "OTP code required for amounts over %s", threshold)
+	}
+
+	return s.userHelper.VerifyOTP(ctx, claims.UserID, code)
+}
+
+// applyTransaction 계좌 잠금 상태에서 잔액 변경, 거래 기록, 원장 분개를 하나의 트랜잭션으로 처리해 일부만 반영되지 않도록 합니다.
+func (s *transactionService) applyTransaction(ctx context.Context, accountID int64, amount money.Money, transactionType string) (*ebank.TransactionResponse, error) {
+	if err := s.accountRepository.LockAccountByID(ctx, accountID); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to lock account")
+	}
+	defer s.accountRepository.UnlockAccountByID(ctx, accountID)
+
+	account, err := s.accountRepository.GetAccountByID(ctx, accountID)
+	if err != nil || account == nil {
+		return nil, status.Errorf(codes.NotFound, "Account not found")
+	}
+
+	if account.Balance.Currency != amount.Currency {
+		return nil, status.Errorf(codes.InvalidArgument, "Currency mismatch")
+	}
+
+	penalty := money.Zero(amount.Currency)
+	switch transactionType {
+	case model.TransactionTypeDeposit:
+		if !account.CanCredit() {
+			return nil, accountStatusError(*account)
+		}
+		err = account.AddBalance(amount)
+	case model.TransactionTypeWithdrawal:
+		if !account.CanDebit() {
+			return nil, accountStatusError(*account)
+		}
+		if penalty, err = s.withdrawalPenalty(ctx, *account, amount, false); err != nil {
+			return nil, err
+		}
+		if err = account.SubtractBalance(amount); err == nil {
+			err = account.SubtractBalance(penalty)
+		}
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	createdAt := timestamppb.Now().AsTime()
+	resp := &ebank.TransactionResponse{}
+	err = s.unitOfWork.RunInTx(ctx, func(repos Repositories) error {
+		transaction, err := repos.Transactions.CreateTransaction(ctx, model.Transaction{
+			AccountID:       account.ID,
+			Amount:          amount,
+			TransactionType: transactionType,
+			CreatedAt:       createdAt,
+		})
+		if err != nil {
+			return status.Errorf(codes.Internal, "Failed to save transaction data")
+		}
+		fee, err := recordPenalty(ctx, repos.Transactions, account.ID, penalty, createdAt)
+		if err != nil {
+			return err
+		}
+
+		var journalEntry ledgerModel.JournalEntry
+		switch transactionType {
+		case model.TransactionTypeDeposit:
+			journalEntry = ledgerModel.NewDepositEntry(account.ID, amount, transaction.ID)
+		case model.TransactionTypeWithdrawal:
+			journalEntry = ledgerModel.NewWithdrawalEntry(account.ID, amount, transaction.ID)
+		}
+		if fee != nil {
+			journalEntry = journalEntry.WithFee(account.ID, penalty, fee.ID)
+		}
+		if _, err := repos.Ledger.Post(ctx, journalEntry); err != nil {
+			return status.Errorf(codes.Internal, "Failed to post journal entry")
+		}
+
+		if err := repos.Accounts.UpdateAccount(ctx, account); err != nil {
+			return accountService.SaveAccountError(err)
+		}
+
+		resp.Transaction = toTransactionDto(transaction)
+		if fee != nil {
+			resp.Penalty = toTransactionDto(*fee)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, txError(err)
+	}
+	s.changes.Publish(account.ID)
+	resp.NewBalance = money.ToProto(account.Balance)
+
+	return resp, nil
+}
+
+// txError RunInTx의 에러를 gRPC 에러로 바꿉니다. fn이 반환한 에러는 그대로 두고, 커밋하지 못한 에러는 계좌 저장 에러로 봅니다.
+func txError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return accountService.SaveAccountError(err)
+}
+
+// lockAccounts 교착 상태를 피하기 위해 항상 ID가 작은 계좌부터 잠급니다. 0인 ID는 건너뜁니다.
+func (s *transactionService) lockAccounts(ctx context.Context, ids ...int64) (func(), error) {
+	sorted := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 {
+			sorted = append(sorted, id)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var locked []int64
+	unlock := func() {
+		for i := len(locked) - 1; i >= 0; i-- {
+			s.accountRepository.UnlockAccountByID(ctx, locked[i])
+		}
+	}
+	for i, id := range sorted {
+		if i > 0 && id == sorted[i-1] {
+			continue
+		}
+		if err := s.accountRepository.LockAccountByID(ctx, id); err != nil {
+			unlock()
+			return nil, status.Errorf(codes.Internal, "Failed to lock account")
+		}
+		locked = append(locked, id)
+	}
+
+	return unlock, nil
+}
+
+// accountStatusError 계좌 상태 때문에 거래할 수 없을 때의 오류입니다.
+func accountStatusError(account accountModel.Account) error {
+	return status.Errorf(codes.FailedPrecondition, "Account %d is %s", account.ID, strings.ToLower(account.GetStatus()))
+}
+
+func toTransactionDto(transaction model.Transaction) *ebank.Transaction {
+	return &ebank.Transaction{
+		Id:                    transaction.ID,
+		AccountId:             transaction.AccountID,
+		Amount:                money.ToProto(transaction.Amount),
+		TransactionType:       transaction.TransactionType,
+		Timestamp:             timestamppb.New(transaction.CreatedAt),
+		CounterpartyAccountId: transaction.CounterpartyAccountID,
+		Memo:                  transaction.Memo,
+	}
+}
+
+func parseAmount(amount *ebank.Money) (money.Money, error) {
+	parsed, err := money.FromProto(amount)
+	if err != nil {
+		return money.Money{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !parsed.IsPositive() {
+		return money.Money{}, status.Errorf(codes.InvalidArgument, "Amount must be positive")
+	}
+
+	return parsed, nil
 }
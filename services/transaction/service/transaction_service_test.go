@@ -0,0 +1,1522 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"ebank/api/v1"
+	"ebank/mocks"
+	"ebank/pkg/config"
+	"ebank/pkg/feed"
+	"ebank/pkg/jwt_manager"
+	"ebank/pkg/money"
+	"ebank/pkg/storage"
+	"ebank/pkg/wal"
+	accountModel "ebank/services/account/model"
+	accountService "ebank/services/account/service"
+	ledgerModel "ebank/services/ledger/model"
+	ledgerService "ebank/services/ledger/service"
+	"ebank/services/transaction/model"
+	"ebank/services/transaction/repository"
+	"ebank/services/transaction/service"
+	userModel "ebank/services/user/model"
+)
+
+func TestTransactionUsecaseSuite(t *testing.T) {
+	suite.Run(t, new(TransactionUsecaseTestSuite))
+}
+
+var (
+	_ suite.SetupTestSuite = &TransactionUsecaseTestSuite{}
+)
+
+var stepUpThresholds = map[string]money.Money{"KRW": money.New(1_000_000, "KRW")}
+
+type TransactionUsecaseTestSuite struct {
+	suite.Suite
+	userHelper            *mocks.UserHelper
+	accountRepository     *mocks.AccountRepository
+	transactionRepository *mocks.TransactionRepository
+	idempotencyRepository *mocks.IdempotencyRepository
+	ledger                *mocks.Ledger
+	unitOfWork            service.UnitOfWork
+	usecase               ebank.TransactionServiceServer
+}
+
+// mockUnitOfWork 목 저장소를 그대로 넘겨 fn을 실행합니다. 실패해도 되돌리지 않으므로 롤백은 실제 저장소로 확인합니다.
+type mockUnitOfWork struct {
+	repos service.Repositories
+}
+
+func (u mockUnitOfWork) RunInTx(ctx context.Context, fn func(repos service.Repositories) error) error {
+	return fn(u.repos)
+}
+
+func (ts *TransactionUsecaseTestSuite) SetupTest() {
+	ts.userHelper = new(mocks.UserHelper)
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(userModel.User{}, nil).Maybe()
+	ts.accountRepository = new(mocks.AccountRepository)
+	ts.transactionRepository = new(mocks.TransactionRepository)
+	ts.idempotencyRepository = new(mocks.IdempotencyRepository)
+	ts.ledger = new(mocks.Ledger)
+	ts.unitOfWork = mockUnitOfWork{service.Repositories{Accounts: ts.accountRepository, Transactions: ts.transactionRepository, Ledger: ts.ledger}}
+	ts.usecase = service.NewTransactionService(ts.userHelper, ts.accountRepository, ts.transactionRepository, ts.idempotencyRepository, ts.ledger, ts.unitOfWork, stepUpThresholds, feed.New())
+}
+
+// expectBalancedPost 원장에 균형 잡힌 분개만 기록되는지 확인합니다.
+func (ts *TransactionUsecaseTestSuite) expectBalancedPost(entryID int64) {
+	ts.ledger.EXPECT().Post(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, entry ledgerModel.JournalEntry) (ledgerModel.JournalEntry, error) {
+			ts.NoError(entry.Validate())
+			entry.ID = entryID
+			return entry, nil
+		})
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Deposit() {
+	testAccount := &accountModel.Account{
+		ID:            1,
+		AccountNumber: "1234567890",
+		CustomerID:    123,
+		Balance:       money.New(1000, "KRW"),
+	}
+
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, testAccount.ID).Return(nil)
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, testAccount.ID).Return(nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, testAccount.ID).Return(testAccount, nil)
+	ts.transactionRepository.EXPECT().CreateTransaction(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, transaction model.Transaction) (model.Transaction, error) {
+			transaction.ID = 1
+			return transaction, nil
+		})
+	ts.expectBalancedPost(1)
+	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.MatchedBy(func(account *accountModel.Account) bool {
+		return account.Balance == money.New(1500, "KRW")
+	})).Return(nil)
+
+	resp, err := ts.usecase.Deposit(context.Background(), &ebank.DepositRequest{
+		AccountId: testAccount.ID,
+		Amount:    &ebank.Money{Amount: 500, Currency: "KRW"},
+	})
+
+	ts.NoError(err)
+	ts.Equal(int64(1500), resp.NewBalance.Amount)
+	ts.Equal(model.TransactionTypeDeposit, resp.Transaction.TransactionType)
+	ts.Equal(int64(500), resp.Transaction.Amount.Amount)
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Withdraw_InsufficientBalance() {
+	testAccount := &accountModel.Account{
+		ID:            1,
+		AccountNumber: "1234567890",
+		CustomerID:    123,
+		Balance:       money.New(100, "KRW"),
+	}
+
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, testAccount.ID).Return(nil)
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, testAccount.ID).Return(nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, testAccount.ID).Return(testAccount, nil)
+
+	_, err := ts.usecase.Withdraw(context.Background(), &ebank.WithdrawRequest{
+		AccountId: testAccount.ID,
+		Amount:    &ebank.Money{Amount: 500, Currency: "KRW"},
+	})
+
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+	ts.transactionRepository.AssertNotCalled(ts.T(), "CreateTransaction", mock.Anything, mock.Anything)
+	ts.ledger.AssertNotCalled(ts.T(), "Post", mock.Anything, mock.Anything)
+	ts.accountRepository.AssertNotCalled(ts.T(), "UpdateAccount", mock.Anything, mock.Anything)
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Withdraw_NotOwner() {
+	testAccount := &accountModel.Account{
+		ID:            1,
+		AccountNumber: "1234567890",
+		CustomerID:    123,
+		Balance:       money.New(1000, "KRW"),
+	}
+
+	userHelper := new(mocks.UserHelper)
+	userHelper.EXPECT().ValidateUser(mock.Anything, testAccount.CustomerID).Return(userModel.User{}, status.Errorf(codes.PermissionDenied, "Not allowed"))
+	usecase := service.NewTransactionService(userHelper, ts.accountRepository, ts.transactionRepository, ts.idempotencyRepository, ts.ledger, ts.unitOfWork, stepUpThresholds, feed.New())
+
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, testAccount.ID).Return(testAccount, nil)
+
+	_, err := usecase.Withdraw(context.Background(), &ebank.WithdrawRequest{
+		AccountId: testAccount.ID,
+		Amount:    &ebank.Money{Amount: 500, Currency: "KRW"},
+	})
+
+	ts.Equal(codes.PermissionDenied, status.Code(err))
+	ts.accountRepository.AssertNotCalled(ts.T(), "LockAccountByID", mock.Anything, mock.Anything)
+	ts.transactionRepository.AssertNotCalled(ts.T(), "CreateTransaction", mock.Anything, mock.Anything)
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Withdraw_StepUpRequired() {
+	testAccount := &accountModel.Account{ID: 1, CustomerID: 123, Balance: money.New(5_000_000, "KRW")}
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, testAccount.ID).Return(testAccount, nil)
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: 123})
+
+	_, err := ts.usecase.Withdraw(ctx, &ebank.WithdrawRequest{
+		AccountId: testAccount.ID,
+		Amount:    &ebank.Money{Amount: 2_000_000, Currency: "KRW"},
+	})
+
+	// 2단계 인증을 등록하지 않은 사용자에게는 등록하라고 알립니다.
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+	ts.Contains(status.Convert(err).Message(), "EnrollTOTP")
+	ts.accountRepository.AssertNotCalled(ts.T(), "LockAccountByID", mock.Anything, mock.Anything)
+
+	userHelper := mocks.NewUserHelper(ts.T())
+	userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(userModel.User{ID: 123, TOTPEnabled: true}, nil)
+	usecase := service.NewTransactionService(userHelper, ts.accountRepository, ts.transactionRepository, ts.idempotencyRepository, ts.ledger, ts.unitOfWork, stepUpThresholds, feed.New())
+
+	_, err = usecase.Withdraw(ctx, &ebank.WithdrawRequest{
+		AccountId: testAccount.ID,
+		Amount:    &ebank.Money{Amount: 2_000_000, Currency: "KRW"},
+	})
+
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+	ts.Contains(status.Convert(err).Message(), "OTP code required")
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Transfer_StepUpInvalidCode() {
+	fromAccount := &accountModel.Account{ID: 1, CustomerID: 123, Balance: money.New(5_000_000, "KRW")}
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, fromAccount.ID).Return(fromAccount, nil)
+	ts.userHelper.EXPECT().VerifyOTP(mock.Anything, int64(123), "123456").Return(status.Errorf(codes.Unauthenticated, "Invalid OTP code"))
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: 123})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("otp-code", "123456"))
+
+	_, err := ts.usecase.Transfer(ctx, &ebank.TransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   2,
+		Amount:        &ebank.Money{Amount: 2_000_000, Currency: "KRW"},
+	})
+
+	ts.Equal(codes.Unauthenticated, status.Code(err))
+	ts.accountRepository.AssertNotCalled(ts.T(), "LockAccountByID", mock.Anything, mock.Anything)
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Withdraw_AccountSaveFailure() {
+	testAccount := &accountModel.Account{
+		ID:            1,
+		AccountNumber: "1234567890",
+		CustomerID:    123,
+		Balance:       money.New(1000, "KRW"),
+	}
+
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, testAccount.ID).Return(nil)
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, testAccount.ID).Return(nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, testAccount.ID).Return(testAccount, nil)
+	ts.transactionRepository.EXPECT().CreateTransaction(mock.Anything, mock.Anything).
+		Return(model.Transaction{ID: 7, AccountID: testAccount.ID}, nil)
+	ts.expectBalancedPost(3)
+	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.Anything).Return(status.Error(codes.Internal, "disk full"))
+
+	_, err := ts.usecase.Withdraw(context.Background(), &ebank.WithdrawRequest{
+		AccountId: testAccount.ID,
+		Amount:    &ebank.Money{Amount: 500, Currency: "KRW"},
+	})
+
+	// 기록한 거래와 분개는 트랜잭션이 함께 되돌리므로 지우거나 역분개하지 않습니다.
+	ts.Equal(codes.Internal, status.Code(err))
+	ts.transactionRepository.AssertNotCalled(ts.T(), "DeleteTransaction", mock.Anything, mock.Anything)
+	ts.ledger.AssertNotCalled(ts.T(), "Reverse", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Deposit_AccountChanged() {
+	testAccount := &accountModel.Account{ID: 1, AccountNumber: "1234567890", CustomerID: 123, Balance: money.New(1000, "KRW")}
+
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, testAccount.ID).Return(nil)
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, testAccount.ID).Return(nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, testAccount.ID).Return(testAccount, nil)
+	ts.transactionRepository.EXPECT().CreateTransaction(mock.Anything, mock.Anything).
+		Return(model.Transaction{ID: 7, AccountID: testAccount.ID}, nil)
+	ts.expectBalancedPost(3)
+	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.Anything).Return(accountService.ErrAccountChanged)
+
+	_, err := ts.usecase.Deposit(context.Background(), &ebank.DepositRequest{
+		AccountId: testAccount.ID,
+		Amount:    &ebank.Money{Amount: 500, Currency: "KRW"},
+	})
+
+	// 다른 프로세스가 먼저 계좌를 바꿨으면 다시 시도할 수 있도록 Aborted를 반환합니다.
+	ts.Equal(codes.Aborted, status.Code(err))
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Transfer() {
+	fromAccount := &accountModel.Account{ID: 2, AccountNumber: "222", CustomerID: 1, Balance: money.New(1000, "KRW")}
+	toAccount := &accountModel.Account{ID: 1, AccountNumber: "111", CustomerID: 2, Balance: money.New(0, "KRW")}
+
+	var locked []int64
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, id int64) error {
+			locked = append(locked, id)
+			return nil
+		})
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, mock.Anything).Return(nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, fromAccount.ID).Return(fromAccount, nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, toAccount.ID).Return(toAccount, nil)
+	ts.transactionRepository.EXPECT().CreateTransaction(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, transaction model.Transaction) (model.Transaction, error) {
+			transaction.ID = transaction.AccountID * 10
+			return transaction, nil
+		})
+	ts.expectBalancedPost(1)
+	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := ts.usecase.Transfer(context.Background(), &ebank.TransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        &ebank.Money{Amount: 300, Currency: "KRW"},
+		Memo:          "rent",
+	})
+
+	ts.NoError(err)
+	ts.Equal([]int64{1, 2}, locked)
+	ts.Equal(int64(700), resp.NewBalance.Amount)
+	ts.Equal(model.TransactionTypeTransferOut, resp.Outgoing.TransactionType)
+	ts.Equal(toAccount.ID, resp.Outgoing.CounterpartyAccountId)
+	ts.Equal(model.TransactionTypeTransferIn, resp.Incoming.TransactionType)
+	ts.Equal(fromAccount.ID, resp.Incoming.CounterpartyAccountId)
+	ts.Equal("rent", resp.Incoming.Memo)
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_Transfer_CreditFailure() {
+	fromAccount := &accountModel.Account{ID: 1, Balance: money.New(1000, "KRW")}
+	toAccount := &accountModel.Account{ID: 2, Balance: money.New(0, "KRW")}
+
+	ts.accountRepository.EXPECT().LockAccountByID(mock.Anything, mock.Anything).Return(nil)
+	ts.accountRepository.EXPECT().UnlockAccountByID(mock.Anything, mock.Anything).Return(nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, fromAccount.ID).Return(fromAccount, nil)
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, toAccount.ID).Return(toAccount, nil)
+	ts.transactionRepository.EXPECT().CreateTransaction(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, transaction model.Transaction) (model.Transaction, error) {
+			transaction.ID = transaction.AccountID * 10
+			return transaction, nil
+		})
+	ts.expectBalancedPost(5)
+	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.MatchedBy(func(account *accountModel.Account) bool {
+		return account.ID == fromAccount.ID
+	})).Return(nil)
+	ts.accountRepository.EXPECT().UpdateAccount(mock.Anything, mock.MatchedBy(func(account *accountModel.Account) bool {
+		return account.ID == toAccount.ID
+	})).Return(status.Error(codes.Internal, "disk full"))
+
+	_, err := ts.usecase.Transfer(context.Background(), &ebank.TransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        &ebank.Money{Amount: 300, Currency: "KRW"},
+	})
+
+	// 출금 계좌 저장, 거래, 분개는 트랜잭션이 함께 되돌리므로 따로 되돌리지 않습니다.
+	ts.Equal(codes.Internal, status.Code(err))
+	ts.accountRepository.AssertNumberOfCalls(ts.T(), "UpdateAccount", 2)
+	ts.transactionRepository.AssertNotCalled(ts.T(), "DeleteTransaction", mock.Anything, mock.Anything)
+	ts.ledger.AssertNotCalled(ts.T(), "Reverse", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_GetTransactionHistory() {
+	now := time.Now()
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, int64(1)).Return(&accountModel.Account{ID: 1, Balance: money.Zero("KRW")}, nil)
+	ts.transactionRepository.EXPECT().ListTransactions(mock.Anything, mock.MatchedBy(func(query model.TransactionQuery) bool {
+		return query.AccountID == 1 && query.StartDate.Equal(now.Add(-24*time.Hour)) && *query.MinAmount == 10 && query.Limit == 3
+	})).Return([]model.Transaction{
+		{ID: 3, AccountID: 1, Amount: money.New(30, "KRW"), TransactionType: model.TransactionTypeDeposit, CreatedAt: now},
+		{ID: 2, AccountID: 1, Amount: money.New(20, "KRW"), TransactionType: model.TransactionTypeDeposit, CreatedAt: now.Add(-time.Hour)},
+		{ID: 1, AccountID: 1, Amount: money.New(10, "KRW"), TransactionType: model.TransactionTypeDeposit, CreatedAt: now.Add(-2 * time.Hour)},
+	}, nil)
+
+	resp, err := ts.usecase.GetTransactionHistory(context.Background(), &ebank.GetTransactionHistoryRequest{
+		AccountId: 1,
+		StartDate: timestamppb.New(now.Add(-24 * time.Hour)),
+		MinAmount: &ebank.Money{Amount: 10, Currency: "KRW"},
+		PageSize:  2,
+	})
+
+	ts.NoError(err)
+	ts.Len(resp.Transactions, 2)
+	ts.Equal(int64(3), resp.Transactions[0].Id)
+	ts.NotEmpty(resp.NextPageToken)
+}
+
+func (ts *TransactionUsecaseTestSuite) Test_transactionService_GetTransactionHistory_PageTokenForOtherFilter() {
+	ts.accountRepository.EXPECT().GetAccountByID(mock.Anything, int64(1)).Return(&accountModel.Account{ID: 1, Balance: money.Zero("KRW")}, nil)
+	ts.transactionRepository.EXPECT().ListTransactions(mock.Anything, mock.Anything).Return([]model.Transaction{
+		{ID: 2, AccountID: 1, CreatedAt: time.Now()},
+		{ID: 1, AccountID: 1, CreatedAt: time.Now()},
+	}, nil).Once()
+
+	first, err := ts.usecase.GetTransactionHistory(context.Background(), &ebank.GetTransactionHistoryRequest{AccountId: 1, PageSize: 1})
+	ts.Require().NoError(err)
+
+	_, err = ts.usecase.GetTransactionHistory(context.Background(), &ebank.GetTransactionHistoryRequest{
+		AccountId:        1,
+		PageSize:         1,
+		PageToken:        first.NextPageToken,
+		TransactionTypes: []string{model.TransactionTypeDeposit},
+	})
+
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+// 저장소마다 최신순 정렬, 필터, 페이지 이어 보기가 같게 동작하는지 확인합니다.
+func Test_transactionService_GetTransactionHistoryPages(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			services := testServiceGeneratorWithDriver(t, t.TempDir(), driver)
+
+			account, err := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{CustomerID: 1, Balance: money.Zero("KRW")})
+			if err != nil {
+				t.Fatalf("failed to create account: %v", err)
+			}
+
+			// 같은 시각의 거래도 섞어 ID로 순서가 정해지는지 확인합니다.
+			base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i := 1; i <= 25; i++ {
+				transactionType := model.TransactionTypeDeposit
+				if i%2 == 0 {
+					transactionType = model.TransactionTypeWithdrawal
+				}
+				if _, err := services.transactionRepository.CreateTransaction(context.TODO(), model.Transaction{
+					AccountID:       account.ID,
+					Amount:          money.New(int64(i*100), "KRW"),
+					TransactionType: transactionType,
+					CreatedAt:       base.Add(time.Duration(i/3) * time.Minute),
+				}); err != nil {
+					t.Fatalf("failed to create transaction: %v", err)
+				}
+			}
+
+			req := &ebank.GetTransactionHistoryRequest{
+				AccountId:        account.ID,
+				PageSize:         4,
+				TransactionTypes: []string{model.TransactionTypeDeposit},
+				MinAmount:        &ebank.Money{Amount: 300, Currency: "KRW"},
+				MaxAmount:        &ebank.Money{Amount: 2300, Currency: "KRW"},
+			}
+			var got []int64
+			for page := 0; ; page++ {
+				resp, err := services.transactionService.GetTransactionHistory(context.TODO(), req)
+				if err != nil {
+					t.Fatalf("GetTransactionHistory() page %d error = %v", page, err)
+				}
+				for _, transaction := range resp.Transactions {
+					got = append(got, transaction.Amount.Amount/100)
+				}
+				if resp.NextPageToken == "" {
+					break
+				}
+				req.PageToken = resp.NextPageToken
+			}
+
+			want := []int64{23, 21, 19, 17, 15, 13, 11, 9, 7, 5, 3}
+			if len(got) != len(want) {
+				t.Fatalf("history = %v, want %v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("history = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+type fakeTransactionStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *ebank.TransactionEvent
+}
+
+func (s *fakeTransactionStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeTransactionStream) Send(event *ebank.TransactionEvent) error {
+	s.sent <- event
+	return nil
+}
+
+func watchTransactions(t *testing.T, services TestServices, req *ebank.WatchTransactionsRequest) (*fakeTransactionStream, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeTransactionStream{ctx: ctx, sent: make(chan *ebank.TransactionEvent, 10)}
+	done := make(chan error)
+	go func() {
+		done <- services.transactionService.WatchTransactions(req, stream)
+	}()
+
+	return stream, func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("WatchTransactions() error = %v", err)
+		}
+	}
+}
+
+func receiveTransaction(t *testing.T, stream *fakeTransactionStream) *ebank.TransactionEvent {
+	t.Helper()
+	select {
+	case event := <-stream.sent:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no transaction was sent")
+		return nil
+	}
+}
+
+// 연결 중에 기록된 거래를 바로 받고, 다시 연결하면 끊긴 동안의 거래부터 이어 받는지 확인합니다.
+func Test_transactionService_WatchTransactions(t *testing.T) {
+	services := testServiceGenerator(t, t.TempDir())
+	account, err := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{CustomerID: 1, Balance: money.Zero("KRW")})
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	deposit := func(amount int64) {
+		if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{
+			AccountId: account.ID,
+			Amount:    &ebank.Money{Amount: amount, Currency: "KRW"},
+		}); err != nil {
+			t.Fatalf("Deposit() error = %v", err)
+		}
+	}
+
+	// 연결 전의 거래는 보내지 않습니다.
+	deposit(100)
+	stream, stop := watchTransactions(t, services, &ebank.WatchTransactionsRequest{AccountId: account.ID})
+	// 구독이 시작되기 전에 입금하면 받지 못하므로 받을 때까지 입금합니다.
+	var event *ebank.TransactionEvent
+	for event == nil {
+		deposit(200)
+		select {
+		case event = <-stream.sent:
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	if event.Transaction.Amount.Amount != 200 {
+		t.Fatalf("first event amount = %d, want 200", event.Transaction.Amount.Amount)
+	}
+	stop()
+	// 마지막으로 받은 거래 뒤에 이미 기록된 거래가 있으면 이어 받을 때 함께 옵니다.
+	for len(stream.sent) > 0 {
+		event = <-stream.sent
+	}
+
+	deposit(300)
+	deposit(400)
+	stream, stop = watchTransactions(t, services, &ebank.WatchTransactionsRequest{AccountId: account.ID, Since: event.Cursor})
+	defer stop()
+	for _, want := range []int64{300, 400} {
+		if got := receiveTransaction(t, stream).Transaction.Amount.Amount; got != want {
+			t.Fatalf("resumed event amount = %d, want %d", got, want)
+		}
+	}
+}
+
+type TestServices struct {
+	userHelper            accountService.UserHelper
+	accountRepository     accountService.AccountRepository
+	transactionRepository service.TransactionRepository
+	idempotencyRepository service.IdempotencyRepository
+	ledger                ledgerService.Ledger
+	unitOfWork            service.UnitOfWork
+	transactionService    ebank.TransactionServiceServer
+}
+
+func testServiceGenerator(t *testing.T, dir string) TestServices {
+	return testServiceGeneratorWithDriver(t, dir, config.DBDriverFile)
+}
+
+func testServiceGeneratorWithDriver(t *testing.T, dir string, driver string) TestServices {
+	storage, err := storage.Open(config.DBConfig{
+		Driver:               driver,
+		SQLitePath:           filepath.Join(dir, "ebank_test.db"),
+		AccountTablePath:     filepath.Join(dir, "account_test.json"),
+		TransactionTablePath: filepath.Join(dir, "transaction_test.json"),
+		IdempotencyTablePath: filepath.Join(dir, "idempotency_test.json"),
+		LedgerTablePath:      filepath.Join(dir, "ledger_test.json"),
+		TxLogPath:            filepath.Join(dir, "tx_test.json"),
+	})
+	if err != nil {
+		t.Fatalf("failed to open storage: %v", err)
+	}
+	t.Cleanup(func() { storage.Close() })
+
+	accountRepository, err := storage.AccountRepository()
+	if err != nil {
+		t.Fatalf("failed to make accountRepository: %v", err)
+	}
+
+	transactionRepository, err := storage.TransactionRepository()
+	if err != nil {
+		t.Fatalf("failed to make transactionRepository: %v", err)
+	}
+
+	idempotencyRepository, err := storage.IdempotencyRepository()
+	if err != nil {
+		t.Fatalf("failed to make idempotencyRepository: %v", err)
+	}
+
+	journalEntryRepository, err := storage.JournalEntryRepository()
+	if err != nil {
+		t.Fatalf("failed to make journalEntryRepository: %v", err)
+	}
+
+	ledger := ledgerService.NewLedger(journalEntryRepository)
+
+	userHelper := mocks.NewUserHelper(t)
+	userHelper.EXPECT().ValidateUser(mock.Anything, mock.Anything).Return(userModel.User{}, nil).Maybe()
+
+	return TestServices{
+		userHelper:            userHelper,
+		accountRepository:     accountRepository,
+		transactionRepository: transactionRepository,
+		idempotencyRepository: idempotencyRepository,
+		ledger:                ledger,
+		unitOfWork:            storage,
+		transactionService:    service.NewTransactionService(userHelper, accountRepository, transactionRepository, idempotencyRepository, ledger, storage, stepUpThresholds, storage.Changes()),
+	}
+}
+
+func Test_transactionService_DepositAndWithdrawConcurrently(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			dir := t.TempDir()
+
+			services := testServiceGeneratorWithDriver(t, dir, driver)
+
+			testAccount, err := services.accountRepository.CreateAccount(context.Background(), accountModel.Account{
+				AccountNumber: "1234567890",
+				CustomerID:    1,
+				Balance:       money.Zero("KRW"),
+			})
+			if err != nil {
+				t.Fatalf("Failed to create account: %v", err)
+			}
+
+			const amt = 10
+			const c = 1000
+			var negBal int32
+			var start, g sync.WaitGroup
+			start.Add(1)
+			g.Add(3 * c)
+			for i := 0; i < c; i++ {
+				go func() { // deposit
+					start.Wait()
+					services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{
+						AccountId: testAccount.ID,
+						Amount:    &ebank.Money{Amount: amt, Currency: "KRW"},
+					}) // ignore return values
+					g.Done()
+				}()
+				go func() { // withdraw
+					start.Wait()
+					for {
+						_, err := services.transactionService.Withdraw(context.TODO(), &ebank.WithdrawRequest{
+							AccountId: testAccount.ID,
+							Amount:    &ebank.Money{Amount: amt, Currency: "KRW"},
+						})
+						if err == nil {
+							break
+						}
+						time.Sleep(1 * time.Millisecond)
+					}
+
+					g.Done()
+				}()
+				go func() { // watch that balance stays >= 0
+					start.Wait()
+					if account, _ := services.accountRepository.GetAccountByID(context.TODO(), testAccount.ID); account.Balance.IsNegative() {
+						atomic.StoreInt32(&negBal, 1)
+					}
+					g.Done()
+				}()
+			}
+			start.Done()
+			g.Wait()
+			if negBal == 1 {
+				t.Fatal("Balance went negative with concurrent deposits and " +
+					"withdrawals.  Want balance always >= 0.")
+			}
+			if account, err := services.accountRepository.GetAccountByID(context.TODO(), testAccount.ID); err != nil || !account.Balance.IsZero() {
+				t.Fatalf("After equal concurrent deposits and withdrawals, a.Balance = %v, %v.  Want 0, true", account.Balance, err)
+			}
+
+			transactions, err := services.transactionRepository.GetTransactionsByAccountID(context.TODO(), testAccount.ID)
+			if err != nil || len(transactions) != 2*c {
+				t.Fatalf("len(transactions) = %d, %v.  Want %d", len(transactions), err, 2*c)
+			}
+			assertLedgerMatchesAccounts(t, services, testAccount.ID)
+		})
+	}
+}
+
+func Test_transactionService_TransferConcurrently(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			dir := t.TempDir()
+
+			services := testServiceGeneratorWithDriver(t, dir, driver)
+
+			a, _ := services.accountRepository.CreateAccount(context.Background(), accountModel.Account{AccountNumber: "001-10-0000001-5", CustomerID: 1, Balance: money.Zero("KRW")})
+			b, _ := services.accountRepository.CreateAccount(context.Background(), accountModel.Account{AccountNumber: "001-10-0000002-3", CustomerID: 2, Balance: money.Zero("KRW")})
+			for _, id := range []int64{a.ID, b.ID} {
+				if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: id, Amount: &ebank.Money{Amount: 1000, Currency: "KRW"}}); err != nil {
+					t.Fatalf("Deposit() error = %v", err)
+				}
+			}
+
+			const c = 200
+			var g sync.WaitGroup
+			g.Add(2 * c)
+			for i := 0; i < c; i++ {
+				go func() { // a -> b
+					services.transactionService.Transfer(context.TODO(), &ebank.TransferRequest{FromAccountId: a.ID, ToAccountId: b.ID, Amount: &ebank.Money{Amount: 10, Currency: "KRW"}})
+					g.Done()
+				}()
+				go func() { // b -> a
+					services.transactionService.Transfer(context.TODO(), &ebank.TransferRequest{FromAccountId: b.ID, ToAccountId: a.ID, Amount: &ebank.Money{Amount: 10, Currency: "KRW"}})
+					g.Done()
+				}()
+			}
+			g.Wait()
+
+			accountA, _ := services.accountRepository.GetAccountByID(context.TODO(), a.ID)
+			accountB, _ := services.accountRepository.GetAccountByID(context.TODO(), b.ID)
+			if total, _ := accountA.Balance.Add(accountB.Balance); total != money.New(2000, "KRW") {
+				t.Fatalf("After concurrent transfers, total balance = %v.  Want 2000 KRW", total)
+			}
+			assertLedgerMatchesAccounts(t, services, a.ID, b.ID)
+		})
+	}
+}
+
+func Test_transactionService_IdempotentDeposit(t *testing.T) {
+	dir := t.TempDir()
+
+	services := testServiceGenerator(t, dir)
+
+	testAccount, _ := services.accountRepository.CreateAccount(context.Background(), accountModel.Account{CustomerID: 1, Balance: money.Zero("KRW")})
+
+	req := &ebank.DepositRequest{
+		AccountId:      testAccount.ID,
+		Amount:         &ebank.Money{Amount: 100, Currency: "KRW"},
+		IdempotencyKey: "deposit-1",
+	}
+	first, err := services.transactionService.Deposit(context.TODO(), req)
+	if err != nil {
+		t.Fatalf("Deposit() error = %v", err)
+	}
+
+	// 메타데이터로 같은 키를 보낸 재시도는 최초 응답을 그대로 돌려받습니다.
+	ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("idempotency-key", "deposit-1"))
+	retried, err := services.transactionService.Deposit(ctx, &ebank.DepositRequest{
+		AccountId: testAccount.ID,
+		Amount:    &ebank.Money{Amount: 100, Currency: "KRW"},
+	})
+	if err != nil {
+		t.Fatalf("Deposit() retry error = %v", err)
+	}
+	if !proto.Equal(first, retried) {
+		t.Fatalf("retry response = %v, want %v", retried, first)
+	}
+
+	// 재시작 후에도 키가 유지됩니다.
+	idempotencyRepository, err := repository.NewIdempotencyFileRepository(filepath.Join(dir, "idempotency_test.json"))
+	if err != nil {
+		t.Fatalf("failed to reload idempotencyRepository: %v", err)
+	}
+	restarted := service.NewTransactionService(services.userHelper, services.accountRepository, services.transactionRepository, idempotencyRepository, services.ledger, services.unitOfWork, stepUpThresholds, feed.New())
+	if _, err := restarted.Deposit(context.TODO(), req); err != nil {
+		t.Fatalf("Deposit() retry after restart error = %v", err)
+	}
+
+	_, err = services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{
+		AccountId:      testAccount.ID,
+		Amount:         &ebank.Money{Amount: 999, Currency: "KRW"},
+		IdempotencyKey: "deposit-1",
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Deposit() with reused key and different payload error = %v, want FailedPrecondition", err)
+	}
+
+	account, _ := services.accountRepository.GetAccountByID(context.TODO(), testAccount.ID)
+	if account.Balance != money.New(100, "KRW") {
+		t.Fatalf("Balance = %v, want 100 KRW", account.Balance)
+	}
+	transactions, _ := services.transactionRepository.GetTransactionsByAccountID(context.TODO(), testAccount.ID)
+	if len(transactions) != 1 {
+		t.Fatalf("len(transactions) = %d, want 1", len(transactions))
+	}
+}
+
+// completeFailingIdempotencyRepository 처리 결과를 저장하지 못하는 저장소입니다.
+type completeFailingIdempotencyRepository struct {
+	service.IdempotencyRepository
+}
+
+func (completeFailingIdempotencyRepository) CompleteIdempotencyKey(context.Context, int64, string, []byte) error {
+	return errors.New("disk full")
+}
+
+func Test_transactionService_IdempotencyKeyReservation(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			services := testServiceGeneratorWithDriver(t, t.TempDir(), driver)
+			account, _ := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "1", CustomerID: 1, Balance: money.Zero("KRW")})
+			user1 := jwt_manager.NewContext(context.TODO(), &jwt_manager.UserClaims{UserID: 1})
+			user2 := jwt_manager.NewContext(context.TODO(), &jwt_manager.UserClaims{UserID: 2})
+			deposit := func(ctx context.Context, server ebank.TransactionServiceServer, key string) error {
+				_, err := server.Deposit(ctx, &ebank.DepositRequest{AccountId: account.ID, Amount: &ebank.Money{Amount: 100, Currency: "KRW"}, IdempotencyKey: key})
+				return err
+			}
+			assertBalance := func(want int64) {
+				t.Helper()
+				if got, _ := services.accountRepository.GetAccountByID(context.TODO(), account.ID); got.Balance != money.New(want, "KRW") {
+					t.Fatalf("Balance = %v, want %d KRW", got.Balance, want)
+				}
+			}
+
+			// 키는 사용자마다 따로 관리합니다.
+			if err := deposit(user1, services.transactionService, "shared"); err != nil {
+				t.Fatalf("Deposit() error = %v", err)
+			}
+			if err := deposit(user2, services.transactionService, "shared"); err != nil {
+				t.Fatalf("Deposit() of another user with the same key error = %v", err)
+			}
+			assertBalance(200)
+
+			// 돈이 움직인 뒤 결과를 저장하지 못했으면 재시도해도 다시 처리하지 않습니다.
+			failing := service.NewTransactionService(services.userHelper, services.accountRepository, services.transactionRepository,
+				completeFailingIdempotencyRepository{services.idempotencyRepository}, services.ledger, services.unitOfWork, stepUpThresholds, feed.New())
+			if err := deposit(user1, failing, "lost"); status.Code(err) != codes.Internal {
+				t.Fatalf("Deposit() without saving the result error = %v, want Internal", err)
+			}
+			if err := deposit(user1, services.transactionService, "lost"); status.Code(err) != codes.Aborted {
+				t.Fatalf("Deposit() retry of an unsaved result error = %v, want Aborted", err)
+			}
+			assertBalance(300)
+
+			// 처리에 실패한 요청은 예약을 지우므로 같은 키로 다시 시도할 수 있습니다.
+			for i := 0; i < 2; i++ {
+				_, err := services.transactionService.Withdraw(user1, &ebank.WithdrawRequest{AccountId: account.ID, Amount: &ebank.Money{Amount: 1000, Currency: "KRW"}, IdempotencyKey: "failed"})
+				if status.Code(err) != codes.FailedPrecondition {
+					t.Fatalf("Withdraw() over the balance error = %v, want FailedPrecondition", err)
+				}
+			}
+
+			// 사용자별로 나누기 전에 저장해 사용자 0으로 옮긴 키는 같은 요청이면 최초 응답을 돌려주고, 다른 요청이면 새로 처리합니다.
+			if err := deposit(context.TODO(), services.transactionService, "legacy"); err != nil {
+				t.Fatalf("Deposit() without a user error = %v", err)
+			}
+			if err := deposit(user1, services.transactionService, "legacy"); err != nil {
+				t.Fatalf("Deposit() retry of a legacy key error = %v", err)
+			}
+			assertBalance(400)
+			if _, err := services.transactionService.Withdraw(user1, &ebank.WithdrawRequest{AccountId: account.ID, Amount: &ebank.Money{Amount: 100, Currency: "KRW"}, IdempotencyKey: "legacy"}); err != nil {
+				t.Fatalf("Withdraw() with a legacy key of another request error = %v", err)
+			}
+			assertBalance(300)
+
+			// 보관 기간이 지나 지운 키로 다시 보내면 새 요청으로 처리합니다.
+			if err := services.idempotencyRepository.DeleteIdempotencyKeysBefore(context.TODO(), time.Now().Add(time.Second)); err != nil {
+				t.Fatalf("DeleteIdempotencyKeysBefore() error = %v", err)
+			}
+			if err := deposit(user1, services.transactionService, "shared"); err != nil {
+				t.Fatalf("Deposit() with an expired key error = %v", err)
+			}
+			assertBalance(400)
+
+			assertLedgerMatchesAccounts(t, services, account.ID)
+		})
+	}
+}
+
+func Test_transactionRepository_IDsNotReusedAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "transaction_test.json")
+
+	transactionRepository, err := repository.NewTransactionFileRepository(path)
+	if err != nil {
+		t.Fatalf("failed to make transactionRepository: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		transactionRepository.CreateTransaction(context.TODO(), model.Transaction{AccountID: 1, Amount: money.New(100, "KRW"), TransactionType: model.TransactionTypeDeposit})
+	}
+	if err := transactionRepository.DeleteTransaction(context.TODO(), 3); err != nil {
+		t.Fatalf("DeleteTransaction() error = %v", err)
+	}
+
+	// 첫 재시작은 로그를 재생해 스냅샷을 만들고, 두 번째 재시작은 스냅샷의 시퀀스를 읽습니다.
+	for restart := 1; restart <= 2; restart++ {
+		transactionRepository, err = repository.NewTransactionFileRepository(path)
+		if err != nil {
+			t.Fatalf("failed to reload transactionRepository: %v", err)
+		}
+	}
+
+	created, err := transactionRepository.CreateTransaction(context.TODO(), model.Transaction{AccountID: 1, Amount: money.New(100, "KRW"), TransactionType: model.TransactionTypeDeposit})
+	if err != nil {
+		t.Fatalf("CreateTransaction() error = %v", err)
+	}
+	if created.ID != 4 {
+		t.Fatalf("created.ID = %d, want 4", created.ID)
+	}
+}
+
+func Test_accountRepository_AccountNumbers(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			dir := t.TempDir()
+			open := func() (*storage.Storage, accountService.AccountRepository) {
+				storage, err := storage.Open(config.DBConfig{
+					Driver:           driver,
+					SQLitePath:       filepath.Join(dir, "ebank_test.db"),
+					AccountTablePath: filepath.Join(dir, "account_test.json"),
+				})
+				if err != nil {
+					t.Fatalf("failed to open storage: %v", err)
+				}
+				accountRepository, err := storage.AccountRepository()
+				if err != nil {
+					t.Fatalf("failed to make accountRepository: %v", err)
+				}
+				return storage, accountRepository
+			}
+
+			opened, accountRepository := open()
+			for want := int64(1); want <= 2; want++ {
+				sequence, err := accountRepository.NextAccountNumberSequence(context.TODO(), "00110")
+				if err != nil || sequence != want {
+					t.Fatalf("NextAccountNumberSequence() = %d, %v, want %d", sequence, err, want)
+				}
+			}
+			if sequence, _ := accountRepository.NextAccountNumberSequence(context.TODO(), "00120"); sequence != 1 {
+				t.Fatalf("NextAccountNumberSequence() of another prefix = %d, want 1", sequence)
+			}
+
+			a, err := accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "001-10-0000002-3", CustomerID: 1, Balance: money.Zero("KRW")})
+			if err != nil {
+				t.Fatalf("CreateAccount() error = %v", err)
+			}
+			if _, err := accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: a.AccountNumber, CustomerID: 2, Balance: money.Zero("KRW")}); err == nil {
+				t.Fatal("CreateAccount() with a duplicate account number error = nil")
+			}
+			b, err := accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "001-10-0000001-5", CustomerID: 2, Balance: money.Zero("KRW")})
+			if err != nil {
+				t.Fatalf("CreateAccount() error = %v", err)
+			}
+			b.AccountNumber = a.AccountNumber
+			if err := accountRepository.UpdateAccount(context.TODO(), &b); err == nil {
+				t.Fatal("UpdateAccount() to a duplicate account number error = nil")
+			}
+
+			// 재시작해도 이미 발급한 일련번호는 다시 발급하지 않습니다.
+			opened.Close()
+			_, accountRepository = open()
+			if sequence, _ := accountRepository.NextAccountNumberSequence(context.TODO(), "00110"); sequence != 3 {
+				t.Fatalf("NextAccountNumberSequence() after restart = %d, want 3", sequence)
+			}
+		})
+	}
+}
+
+func Test_accountRepository_LegacyDuplicateAccountNumbers(t *testing.T) {
+	dir := t.TempDir()
+	tablePath := filepath.Join(dir, "account_test.json")
+
+	// 계좌번호를 직접 입력하던 때 저장된 겹친 번호와 빈 번호입니다.
+	legacy := []accountModel.Account{
+		{ID: 1, AccountNumber: "111", CustomerID: 1, Balance: money.Zero("KRW")},
+		{ID: 2, AccountNumber: "111", CustomerID: 2, Balance: money.Zero("KRW")},
+		{ID: 3, AccountNumber: "", CustomerID: 1, Balance: money.Zero("KRW")},
+		{ID: 4, AccountNumber: "", CustomerID: 2, Balance: money.Zero("KRW")},
+	}
+	records, err := json.Marshal(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := json.Marshal(map[string]any{"sequence": 4, "records": json.RawMessage(records)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tablePath, snapshot, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	open := func() (*storage.Storage, accountService.AccountRepository) {
+		storage, err := storage.Open(config.DBConfig{Driver: config.DBDriverFile, AccountTablePath: tablePath})
+		if err != nil {
+			t.Fatalf("failed to open storage: %v", err)
+		}
+		accountRepository, err := storage.AccountRepository()
+		if err != nil {
+			t.Fatalf("failed to make accountRepository: %v", err)
+		}
+		return storage, accountRepository
+	}
+
+	opened, accountRepository := open()
+	want := map[int64]string{1: "111", 2: "111-2", 3: "", 4: "-4"}
+	for id, number := range want {
+		account, err := accountRepository.GetAccountByID(context.TODO(), id)
+		if err != nil {
+			t.Fatalf("GetAccountByID(%d) error = %v", id, err)
+		}
+		if account.AccountNumber != number {
+			t.Fatalf("GetAccountByID(%d).AccountNumber = %q, want %q", id, account.AccountNumber, number)
+		}
+		// 번호가 가려졌던 계좌도 저장할 수 있습니다.
+		account.Balance = money.New(100, "KRW")
+		if err := accountRepository.UpdateAccount(context.TODO(), account); err != nil {
+			t.Fatalf("UpdateAccount(%d) error = %v", id, err)
+		}
+	}
+
+	// 바꾼 번호는 스냅샷에 남아 재시작해도 그대로입니다.
+	opened.Close()
+	_, accountRepository = open()
+	for id, number := range want {
+		if account, _ := accountRepository.GetAccountByID(context.TODO(), id); account == nil || account.AccountNumber != number || account.Balance != money.New(100, "KRW") {
+			t.Fatalf("GetAccountByID(%d) after restart = %v, want number %q and 100 KRW", id, account, number)
+		}
+	}
+}
+
+func Test_accountRepository_StaleUpdate(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			dir := t.TempDir()
+			open := func() accountService.AccountRepository {
+				storage, err := storage.Open(config.DBConfig{
+					Driver:           driver,
+					SQLitePath:       filepath.Join(dir, "ebank_test.db"),
+					AccountTablePath: filepath.Join(dir, "account_test.json"),
+				})
+				if err != nil {
+					t.Fatalf("failed to open storage: %v", err)
+				}
+				t.Cleanup(func() { storage.Close() })
+				accountRepository, err := storage.AccountRepository()
+				if err != nil {
+					t.Fatalf("failed to make accountRepository: %v", err)
+				}
+				return accountRepository
+			}
+
+			accountRepository := open()
+			created, err := accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "1", CustomerID: 1, Balance: money.New(1000, "KRW")})
+			if err != nil {
+				t.Fatalf("CreateAccount() error = %v", err)
+			}
+
+			// 같은 계좌를 두 번 읽은 뒤 먼저 저장한 잔액을 나중에 저장하는 쪽이 덮어쓰지 않습니다.
+			deposited, _ := accountRepository.GetAccountByID(context.TODO(), created.ID)
+			stale, _ := accountRepository.GetAccountByID(context.TODO(), created.ID)
+			deposited.Balance = money.New(1500, "KRW")
+			if err := accountRepository.UpdateAccount(context.TODO(), deposited); err != nil {
+				t.Fatalf("UpdateAccount() error = %v", err)
+			}
+			if err := stale.ChangeStatus(accountModel.AccountStatusFrozen, "court order", time.Now()); err != nil {
+				t.Fatalf("ChangeStatus() error = %v", err)
+			}
+			if err := accountRepository.UpdateAccount(context.TODO(), stale); !errors.Is(err, accountService.ErrAccountChanged) {
+				t.Fatalf("UpdateAccount() of a stale account error = %v, want ErrAccountChanged", err)
+			}
+
+			// 저장에 성공하면 버전이 올라 같은 값으로 이어서 저장할 수 있습니다.
+			deposited.Balance = money.New(2000, "KRW")
+			if err := accountRepository.UpdateAccount(context.TODO(), deposited); err != nil {
+				t.Fatalf("UpdateAccount() after a successful update error = %v", err)
+			}
+
+			if driver != config.DBDriverSQLite {
+				return
+			}
+			// 같은 DB 파일을 여는 다른 프로세스가 먼저 저장한 경우도 알아냅니다.
+			other := open()
+			stale, _ = accountRepository.GetAccountByID(context.TODO(), created.ID)
+			changed, _ := other.GetAccountByID(context.TODO(), created.ID)
+			changed.Balance = money.New(2500, "KRW")
+			if err := other.UpdateAccount(context.TODO(), changed); err != nil {
+				t.Fatalf("UpdateAccount() from another storage error = %v", err)
+			}
+			stale.Balance = money.New(1000, "KRW")
+			if err := accountRepository.UpdateAccount(context.TODO(), stale); !errors.Is(err, accountService.ErrAccountChanged) {
+				t.Fatalf("UpdateAccount() after another storage updated error = %v, want ErrAccountChanged", err)
+			}
+			if account, _ := accountRepository.GetAccountByID(context.TODO(), created.ID); account.Balance != money.New(2500, "KRW") {
+				t.Fatalf("balance = %v, want 2500 KRW", account.Balance)
+			}
+		})
+	}
+}
+
+func Test_storage_RunInTx_RollsBack(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			services := testServiceGeneratorWithDriver(t, t.TempDir(), driver)
+
+			account, _ := services.accountRepository.CreateAccount(context.Background(), accountModel.Account{AccountNumber: "1", CustomerID: 1, Balance: money.Zero("KRW")})
+			if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: account.ID, Amount: &ebank.Money{Amount: 1000, Currency: "KRW"}}); err != nil {
+				t.Fatalf("Deposit() error = %v", err)
+			}
+
+			// 거래, 분개, 잔액을 모두 쓴 뒤에 실패하면 하나도 남지 않습니다.
+			errFailed := errors.New("failed after writes")
+			err := services.unitOfWork.RunInTx(context.TODO(), func(repos service.Repositories) error {
+				transaction, err := repos.Transactions.CreateTransaction(context.TODO(), model.Transaction{AccountID: account.ID, Amount: money.New(500, "KRW"), TransactionType: model.TransactionTypeDeposit, CreatedAt: time.Now()})
+				if err != nil {
+					return err
+				}
+				if _, err := repos.Ledger.Post(context.TODO(), ledgerModel.NewDepositEntry(account.ID, money.New(500, "KRW"), transaction.ID)); err != nil {
+					return err
+				}
+				updated, err := repos.Accounts.GetAccountByID(context.TODO(), account.ID)
+				if err != nil {
+					return err
+				}
+				updated.Balance = money.New(1500, "KRW")
+				if err := repos.Accounts.UpdateAccount(context.TODO(), updated); err != nil {
+					return err
+				}
+				return errFailed
+			})
+			if !errors.Is(err, errFailed) {
+				t.Fatalf("RunInTx() error = %v, want %v", err, errFailed)
+			}
+
+			// 읽은 뒤에 다른 곳에서 계좌를 바꿨으면 커밋하지 않습니다. 파일 저장소는 커밋할 때 한 번 더 확인하므로 fn 안에서 바꿉니다.
+			// SQLite는 연결이 하나뿐이라 fn 안에서 바깥 저장소를 쓸 수 없으므로 fn에 들어가기 전에 바꿉니다.
+			stale, _ := services.accountRepository.GetAccountByID(context.TODO(), account.ID)
+			touch := func() error {
+				changed, err := services.accountRepository.GetAccountByID(context.TODO(), account.ID)
+				if err != nil {
+					return err
+				}
+				return services.accountRepository.UpdateAccount(context.TODO(), changed)
+			}
+			if driver == config.DBDriverSQLite {
+				if err := touch(); err != nil {
+					t.Fatalf("UpdateAccount() error = %v", err)
+				}
+			}
+			err = services.unitOfWork.RunInTx(context.TODO(), func(repos service.Repositories) error {
+				transaction, err := repos.Transactions.CreateTransaction(context.TODO(), model.Transaction{AccountID: account.ID, Amount: money.New(500, "KRW"), TransactionType: model.TransactionTypeDeposit, CreatedAt: time.Now()})
+				if err != nil {
+					return err
+				}
+				if _, err := repos.Ledger.Post(context.TODO(), ledgerModel.NewDepositEntry(account.ID, money.New(500, "KRW"), transaction.ID)); err != nil {
+					return err
+				}
+				stale.Balance = money.New(1500, "KRW")
+				if err := repos.Accounts.UpdateAccount(context.TODO(), stale); err != nil {
+					return err
+				}
+				if driver == config.DBDriverFile {
+					return touch()
+				}
+				return nil
+			})
+			if !errors.Is(err, accountService.ErrAccountChanged) {
+				t.Fatalf("RunInTx() over a changed account error = %v, want ErrAccountChanged", err)
+			}
+
+			transactions, err := services.transactionRepository.GetTransactionsByAccountID(context.TODO(), account.ID)
+			if err != nil {
+				t.Fatalf("GetTransactionsByAccountID() error = %v", err)
+			}
+			if len(transactions) != 1 {
+				t.Fatalf("len(transactions) = %d after rolled back transactions, want 1", len(transactions))
+			}
+			if account, _ := services.accountRepository.GetAccountByID(context.TODO(), account.ID); account.Balance != money.New(1000, "KRW") {
+				t.Fatalf("balance = %v, want 1000 KRW", account.Balance)
+			}
+			assertLedgerMatchesAccounts(t, services, account.ID)
+		})
+	}
+}
+
+// failingUnitOfWork fail이 참인 계좌를 트랜잭션 안에서 저장하면 실패시킵니다.
+type failingUnitOfWork struct {
+	service.UnitOfWork
+	fail func(account *accountModel.Account) bool
+}
+
+func (u failingUnitOfWork) RunInTx(ctx context.Context, fn func(repos service.Repositories) error) error {
+	return u.UnitOfWork.RunInTx(ctx, func(repos service.Repositories) error {
+		repos.Accounts = failingAccountRepository{AccountRepository: repos.Accounts, fail: u.fail}
+		return fn(repos)
+	})
+}
+
+type failingAccountRepository struct {
+	accountService.AccountRepository
+	fail func(account *accountModel.Account) bool
+}
+
+func (r failingAccountRepository) UpdateAccount(ctx context.Context, account *accountModel.Account) error {
+	if r.fail(account) {
+		return errors.New("disk full")
+	}
+	return r.AccountRepository.UpdateAccount(ctx, account)
+}
+
+func Test_transactionService_TransferAndCloseRollBack(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			services := testServiceGeneratorWithDriver(t, t.TempDir(), driver)
+
+			a, _ := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "1", CustomerID: 1, Balance: money.Zero("KRW")})
+			b, _ := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "2", CustomerID: 1, Balance: money.Zero("KRW")})
+			if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: a.ID, Amount: &ebank.Money{Amount: 1000, Currency: "KRW"}}); err != nil {
+				t.Fatalf("Deposit() error = %v", err)
+			}
+
+			newService := func(fail func(account *accountModel.Account) bool) ebank.TransactionServiceServer {
+				return service.NewTransactionService(services.userHelper, services.accountRepository, services.transactionRepository, services.idempotencyRepository, services.ledger, failingUnitOfWork{services.unitOfWork, fail}, stepUpThresholds, feed.New())
+			}
+
+			// 입금 계좌를 저장하지 못하면 먼저 저장한 출금 계좌도 반영되지 않습니다.
+			failCredit := newService(func(account *accountModel.Account) bool { return account.ID == b.ID })
+			if _, err := failCredit.Transfer(context.TODO(), &ebank.TransferRequest{FromAccountId: a.ID, ToAccountId: b.ID, Amount: &ebank.Money{Amount: 300, Currency: "KRW"}}); status.Code(err) != codes.Internal {
+				t.Fatalf("Transfer() error = %v, want Internal", err)
+			}
+
+			// 해지 상태를 저장하지 못하면 남은 잔액도 옮기지 않습니다.
+			failClose := newService(func(account *accountModel.Account) bool { return account.Status == accountModel.AccountStatusClosed })
+			if _, err := failClose.CloseAccount(context.TODO(), &ebank.CloseAccountRequest{AccountId: a.ID, PayoutAccountId: b.ID}); status.Code(err) != codes.Internal {
+				t.Fatalf("CloseAccount() error = %v, want Internal", err)
+			}
+
+			accountA, _ := services.accountRepository.GetAccountByID(context.TODO(), a.ID)
+			accountB, _ := services.accountRepository.GetAccountByID(context.TODO(), b.ID)
+			if accountA.Balance != money.New(1000, "KRW") || accountB.Balance != money.Zero("KRW") || accountA.GetStatus() != accountModel.AccountStatusActive {
+				t.Fatalf("after failed transfer and close, a = %v %s, b = %v.  Want 1000 KRW ACTIVE, 0 KRW", accountA.Balance, accountA.GetStatus(), accountB.Balance)
+			}
+			for _, id := range []int64{a.ID, b.ID} {
+				transactions, _ := services.transactionRepository.GetTransactionsByAccountID(context.TODO(), id)
+				if id == b.ID && len(transactions) != 0 || id == a.ID && len(transactions) != 1 {
+					t.Fatalf("account %d has %d transactions after rolled back transfers", id, len(transactions))
+				}
+			}
+			assertLedgerMatchesAccounts(t, services, a.ID, b.ID)
+
+			// 실패한 시도가 남긴 것이 없으므로 그대로 다시 해지할 수 있습니다.
+			closed, err := services.transactionService.CloseAccount(context.TODO(), &ebank.CloseAccountRequest{AccountId: a.ID, PayoutAccountId: b.ID})
+			if err != nil {
+				t.Fatalf("CloseAccount() error = %v", err)
+			}
+			if closed.GetAccount().GetStatus() != accountModel.AccountStatusClosed || closed.GetPayout().GetAmount().GetAmount() != 1000 {
+				t.Fatalf("CloseAccount() = %v, want a closed account and a 1000 KRW payout", closed)
+			}
+			assertLedgerMatchesAccounts(t, services, a.ID, b.ID)
+		})
+	}
+}
+
+func Test_storage_RunInTx_RecoversFileTxLog(t *testing.T) {
+	dir := t.TempDir()
+	services := testServiceGenerator(t, dir)
+
+	account, _ := services.accountRepository.CreateAccount(context.Background(), accountModel.Account{AccountNumber: "1", CustomerID: 1, Balance: money.Zero("KRW")})
+	if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: account.ID, Amount: &ebank.Money{Amount: 1000, Currency: "KRW"}}); err != nil {
+		t.Fatalf("Deposit() error = %v", err)
+	}
+
+	// 트랜잭션 로그에 기록한 뒤 각 저장소에 반영하기 전에 멈춘 것처럼 만듭니다.
+	deposited, _ := services.accountRepository.GetAccountByID(context.TODO(), account.ID)
+	deposited.Balance = money.New(1500, "KRW")
+	deposited.Version++
+	entry := ledgerModel.NewDepositEntry(account.ID, money.New(500, "KRW"), 2)
+	entry.ID = 2
+	entry.CreatedAt = time.Now()
+	txLog, err := wal.Open(filepath.Join(dir, "tx_test.json"), wal.DefaultSnapshotInterval)
+	if err != nil {
+		t.Fatalf("failed to open tx log: %v", err)
+	}
+	err = txLog.Append(wal.OpPut, map[string]any{
+		"accounts":        []accountModel.Account{*deposited},
+		"transactions":    []model.Transaction{{ID: 2, AccountID: account.ID, Amount: money.New(500, "KRW"), TransactionType: model.TransactionTypeDeposit, CreatedAt: time.Now()}},
+		"journal_entries": []ledgerModel.JournalEntry{entry},
+	})
+	txLog.Close()
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	// 다시 열면 남은 변경을 마저 반영하고, 한 번 더 열어도 두 번 반영하지 않습니다.
+	for restart := 1; restart <= 2; restart++ {
+		services = testServiceGenerator(t, dir)
+
+		if account, _ := services.accountRepository.GetAccountByID(context.TODO(), account.ID); account.Balance != money.New(1500, "KRW") {
+			t.Fatalf("restart %d: balance = %v, want 1500 KRW", restart, account.Balance)
+		}
+		transactions, err := services.transactionRepository.GetTransactionsByAccountID(context.TODO(), account.ID)
+		if err != nil || len(transactions) != 2 {
+			t.Fatalf("restart %d: len(transactions) = %d, %v.  Want 2", restart, len(transactions), err)
+		}
+		assertLedgerMatchesAccounts(t, services, account.ID)
+	}
+
+	// 이어서 발급하는 ID는 반영한 거래와 겹치지 않습니다.
+	if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: account.ID, Amount: &ebank.Money{Amount: 500, Currency: "KRW"}}); err != nil {
+		t.Fatalf("Deposit() after recovery error = %v", err)
+	}
+	transactions, _ := services.transactionRepository.GetTransactionsByAccountID(context.TODO(), account.ID)
+	if len(transactions) != 3 {
+		t.Fatalf("len(transactions) = %d after recovery, want 3", len(transactions))
+	}
+	assertLedgerMatchesAccounts(t, services, account.ID)
+}
+
+func Test_transactionService_AccountStatus(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			services := testServiceGeneratorWithDriver(t, t.TempDir(), driver)
+
+			frozen, _ := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "1", CustomerID: 1, Balance: money.Zero("KRW")})
+			closing, _ := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "2", CustomerID: 1, Balance: money.Zero("KRW")})
+			payout, _ := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{AccountNumber: "3", CustomerID: 1, Balance: money.Zero("KRW")})
+
+			if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: frozen.ID, Amount: &ebank.Money{Amount: 1000, Currency: "KRW"}}); err != nil {
+				t.Fatalf("Deposit() error = %v", err)
+			}
+			if updated, _ := services.accountRepository.GetAccountByID(context.TODO(), frozen.ID); updated != nil {
+				frozen = *updated
+			}
+			if err := frozen.ChangeStatus(accountModel.AccountStatusFrozen, "court order", time.Now()); err != nil {
+				t.Fatalf("ChangeStatus() error = %v", err)
+			}
+			if err := services.accountRepository.UpdateAccount(context.TODO(), &frozen); err != nil {
+				t.Fatalf("UpdateAccount() error = %v", err)
+			}
+
+			// 동결된 계좌는 출금과 이체 출금은 막고 입금은 받습니다.
+			if _, err := services.transactionService.Withdraw(context.TODO(), &ebank.WithdrawRequest{AccountId: frozen.ID, Amount: &ebank.Money{Amount: 100, Currency: "KRW"}}); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("Withdraw() from frozen account error = %v, want FailedPrecondition", err)
+			}
+			if _, err := services.transactionService.Transfer(context.TODO(), &ebank.TransferRequest{FromAccountId: frozen.ID, ToAccountId: closing.ID, Amount: &ebank.Money{Amount: 100, Currency: "KRW"}}); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("Transfer() from frozen account error = %v, want FailedPrecondition", err)
+			}
+			if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: frozen.ID, Amount: &ebank.Money{Amount: 100, Currency: "KRW"}}); err != nil {
+				t.Fatalf("Deposit() to frozen account error = %v", err)
+			}
+			if _, err := services.transactionService.CloseAccount(context.TODO(), &ebank.CloseAccountRequest{AccountId: frozen.ID, PayoutAccountId: payout.ID}); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("CloseAccount() of frozen account error = %v, want FailedPrecondition", err)
+			}
+
+			if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: closing.ID, Amount: &ebank.Money{Amount: 700, Currency: "KRW"}}); err != nil {
+				t.Fatalf("Deposit() error = %v", err)
+			}
+			if _, err := services.transactionService.CloseAccount(context.TODO(), &ebank.CloseAccountRequest{AccountId: closing.ID}); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("CloseAccount() without payout account error = %v, want FailedPrecondition", err)
+			}
+
+			resp, err := services.transactionService.CloseAccount(context.TODO(), &ebank.CloseAccountRequest{AccountId: closing.ID, PayoutAccountId: payout.ID, Reason: "moving abroad"})
+			if err != nil {
+				t.Fatalf("CloseAccount() error = %v", err)
+			}
+			if resp.Account.Status != accountModel.AccountStatusClosed || resp.Account.StatusReason != "moving abroad" {
+				t.Fatalf("closed account = %v", resp.Account)
+			}
+			if resp.Payout == nil || resp.Payout.Amount.Amount != 700 {
+				t.Fatalf("payout = %v, want 700 KRW", resp.Payout)
+			}
+
+			paidOut, _ := services.accountRepository.GetAccountByID(context.TODO(), payout.ID)
+			if paidOut.Balance != money.New(700, "KRW") {
+				t.Fatalf("payout account balance = %v, want 700 KRW", paidOut.Balance)
+			}
+			assertLedgerMatchesAccounts(t, services, frozen.ID, closing.ID, payout.ID)
+
+			// 해지한 계좌는 거래를 받지 않지만 내역은 계속 조회할 수 있습니다.
+			if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: closing.ID, Amount: &ebank.Money{Amount: 100, Currency: "KRW"}}); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("Deposit() to closed account error = %v, want FailedPrecondition", err)
+			}
+			history, err := services.transactionService.GetTransactionHistory(context.TODO(), &ebank.GetTransactionHistoryRequest{AccountId: closing.ID})
+			if err != nil {
+				t.Fatalf("GetTransactionHistory() of closed account error = %v", err)
+			}
+			if len(history.Transactions) != 2 {
+				t.Fatalf("len(history) = %d, want 2", len(history.Transactions))
+			}
+		})
+	}
+}
+
+func Test_transactionService_Products(t *testing.T) {
+	for _, driver := range []string{config.DBDriverFile, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			services := testServiceGeneratorWithDriver(t, t.TempDir(), driver)
+
+			create := func(number string, productID string, maturesAt time.Time, linkedAccountID int64, deposit int64) accountModel.Account {
+				t.Helper()
+				account, err := services.accountRepository.CreateAccount(context.TODO(), accountModel.Account{
+					AccountNumber:   number,
+					CustomerID:      1,
+					Balance:         money.Zero("KRW"),
+					ProductID:       productID,
+					MaturesAt:       maturesAt,
+					LinkedAccountID: linkedAccountID,
+				})
+				if err != nil {
+					t.Fatalf("CreateAccount() error = %v", err)
+				}
+				if _, err := services.transactionService.Deposit(context.TODO(), &ebank.DepositRequest{AccountId: account.ID, Amount: &ebank.Money{Amount: deposit, Currency: "KRW"}}); err != nil {
+					t.Fatalf("Deposit() error = %v", err)
+				}
+				return account
+			}
+			withdraw := func(accountID int64, amount int64) (*ebank.TransactionResponse, error) {
+				return services.transactionService.Withdraw(context.TODO(), &ebank.WithdrawRequest{AccountId: accountID, Amount: &ebank.Money{Amount: amount, Currency: "KRW"}})
+			}
+
+			checking := create("1", "", time.Time{}, 0, 1000)
+			savings := create("2", "SAVINGS_KRW", time.Time{}, 0, 50_000)
+			penalized := create("3", "FIXED_12M_KRW", time.Now().AddDate(1, 0, 0), checking.ID, 100_000)
+			blocked := create("4", "FIXED_6M_KRW", time.Now().AddDate(0, 6, 0), checking.ID, 5000)
+			matured := create("5", "FIXED_6M_KRW", time.Now().Add(-time.Minute), checking.ID, 7000)
+			frozen := create("6", "FIXED_6M_KRW", time.Now().Add(-time.Minute), checking.ID, 3000)
+
+			// 만기가 된 정기예금은 6개월 전에 3.00%로 개설한 것으로 맞춥니다.
+			maturedAccount, _ := services.accountRepository.GetAccountByID(context.TODO(), matured.ID)
+			maturedAccount.InterestRate = 300
+			maturedAccount.CreatedAt = maturedAccount.MaturesAt.AddDate(0, -6, 0)
+			if err := services.accountRepository.UpdateAccount(context.TODO(), maturedAccount); err != nil {
+				t.Fatalf("UpdateAccount() error = %v", err)
+			}
+			interest := maturedAccount.MaturityInterest()
+			if !interest.IsPositive() {
+				t.Fatalf("MaturityInterest() = %v, want a positive interest", interest)
+			}
+
+			// 정기예금이 만기에 잔액을 옮길 계좌는 해지할 수 없습니다.
+			if _, err := services.transactionService.CloseAccount(context.TODO(), &ebank.CloseAccountRequest{AccountId: checking.ID, PayoutAccountId: savings.ID}); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("CloseAccount() of a linked account error = %v, want FailedPrecondition", err)
+			}
+
+			// 적금은 최소 잔액 아래로 출금할 수 없고, 한 달에 세 번까지만 출금합니다.
+			if _, err := withdraw(savings.ID, 45_000); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("Withdraw() below minimum balance error = %v, want FailedPrecondition", err)
+			}
+			for i := 0; i < 3; i++ {
+				if _, err := withdraw(savings.ID, 1000); err != nil {
+					t.Fatalf("Withdraw() %d error = %v", i, err)
+				}
+			}
+			if _, err := withdraw(savings.ID, 1000); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("Withdraw() over monthly limit error = %v, want FailedPrecondition", err)
+			}
+
+			// 만기 전 출금은 수수료를 함께 빼거나, 상품에 따라 막습니다.
+			resp, err := withdraw(penalized.ID, 10_000)
+			if err != nil {
+				t.Fatalf("Withdraw() before maturity error = %v", err)
+			}
+			if resp.Penalty == nil || resp.Penalty.Amount.Amount != 100 || resp.NewBalance.Amount != 89_900 {
+				t.Fatalf("Withdraw() before maturity = %v, want 100 KRW penalty and 89900 KRW balance", resp)
+			}
+			if _, err := withdraw(blocked.ID, 1000); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("Withdraw() from blocked deposit error = %v, want FailedPrecondition", err)
+			}
+			if _, err := services.transactionService.Transfer(context.TODO(), &ebank.TransferRequest{FromAccountId: blocked.ID, ToAccountId: checking.ID, Amount: &ebank.Money{Amount: 1000, Currency: "KRW"}}); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("Transfer() from blocked deposit error = %v, want FailedPrecondition", err)
+			}
+			if _, err := services.transactionService.CloseAccount(context.TODO(), &ebank.CloseAccountRequest{AccountId: blocked.ID, PayoutAccountId: checking.ID}); status.Code(err) != codes.FailedPrecondition {
+				t.Fatalf("CloseAccount() of blocked deposit error = %v, want FailedPrecondition", err)
+			}
+
+			closed, err := services.transactionService.CloseAccount(context.TODO(), &ebank.CloseAccountRequest{AccountId: penalized.ID, PayoutAccountId: checking.ID})
+			if err != nil {
+				t.Fatalf("CloseAccount() before maturity error = %v", err)
+			}
+			if closed.Penalty == nil || closed.Penalty.Amount.Amount != 899 || closed.Payout.Amount.Amount != 89_001 {
+				t.Fatalf("CloseAccount() before maturity = %v, want 899 KRW penalty and 89001 KRW payout", closed)
+			}
+
+			// 만기가 된 정기예금은 연결 계좌로 옮기고 해지합니다. 동결된 계좌는 건너뜁니다.
+			frozenAccount, _ := services.accountRepository.GetAccountByID(context.TODO(), frozen.ID)
+			if err := frozenAccount.ChangeStatus(accountModel.AccountStatusFrozen, "court order", time.Now()); err != nil {
+				t.Fatalf("ChangeStatus() error = %v", err)
+			}
+			if err := services.accountRepository.UpdateAccount(context.TODO(), frozenAccount); err != nil {
+				t.Fatalf("UpdateAccount() error = %v", err)
+			}
+
+			maturity, err := services.transactionService.MatureAccounts(context.TODO(), &ebank.MatureAccountsRequest{})
+			if err != nil {
+				t.Fatalf("MatureAccounts() error = %v", err)
+			}
+			if len(maturity.Matured) != 1 || len(maturity.FailedAccountIds) != 0 {
+				t.Fatalf("MatureAccounts() = %v, want only account %d", maturity, matured.ID)
+			}
+			if account := maturity.Matured[0].Account; account.Id != matured.ID || account.Status != accountModel.AccountStatusClosed || account.StatusReason != "Matured" {
+				t.Fatalf("matured account = %v", account)
+			}
+			// 만기 이자를 붙인 뒤 이자까지 연결 계좌로 옮깁니다.
+			if credited := maturity.Matured[0].Interest; credited == nil || credited.TransactionType != model.TransactionTypeInterest || credited.Amount.Amount != interest.Amount {
+				t.Fatalf("matured interest = %v, want %v", credited, interest)
+			}
+			if payout := maturity.Matured[0].Payout; payout.Amount.Amount != 7000+interest.Amount {
+				t.Fatalf("matured payout = %v, want %d KRW", payout.Amount, 7000+interest.Amount)
+			}
+
+			linked, _ := services.accountRepository.GetAccountByID(context.TODO(), checking.ID)
+			if want := money.New(1000+89_001+7000+interest.Amount, "KRW"); linked.Balance != want {
+				t.Fatalf("linked account balance = %v, want %v", linked.Balance, want)
+			}
+			assertLedgerMatchesAccounts(t, services, checking.ID, savings.ID, penalized.ID, blocked.ID, matured.ID, frozen.ID)
+
+			if again, err := services.transactionService.MatureAccounts(context.TODO(), &ebank.MatureAccountsRequest{}); err != nil || len(again.Matured) != 0 {
+				t.Fatalf("MatureAccounts() again = %v, %v, want nothing to mature", again, err)
+			}
+		})
+	}
+}
+
+// assertLedgerMatchesAccounts 원장 분개로 계산한 잔액이 계좌 잔액과 같고 시산표가 균형인지 확인합니다.
+func assertLedgerMatchesAccounts(t *testing.T, services TestServices, accountIDs ...int64) {
+	t.Helper()
+
+	for _, accountID := range accountIDs {
+		account, err := services.accountRepository.GetAccountByID(context.TODO(), accountID)
+		if err != nil {
+			t.Fatalf("GetAccountByID() error = %v", err)
+		}
+
+		ledgerBalance, err := services.ledger.GetCustomerBalance(context.TODO(), accountID, account.Balance.Currency)
+		if err != nil {
+			t.Fatalf("GetCustomerBalance() error = %v", err)
+		}
+		if ledgerBalance != account.Balance {
+			t.Fatalf("ledger balance of account %d = %v, want %v", accountID, ledgerBalance, account.Balance)
+		}
+	}
+
+	sums, err := services.ledger.TrialBalance(context.TODO())
+	if err != nil {
+		t.Fatalf("TrialBalance() error = %v", err)
+	}
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			t.Fatalf("trial balance in %s = %v, want 0", currency, sum)
+		}
+	}
+}
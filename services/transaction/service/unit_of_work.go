@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	accountService "ebank/services/account/service"
+	ledgerService "ebank/services/ledger/service"
+)
+
+// Repositories RunInTx 안에서 쓰는 저장소입니다. 이 저장소로 쓴 내용은 RunInTx가 끝날 때 함께 반영됩니다.
+type Repositories struct {
+	Accounts     accountService.AccountRepository
+	Transactions TransactionRepository
+	Ledger       ledgerService.Ledger
+}
+
+// UnitOfWork 잔액, 거래, 분개처럼 한 번의 돈의 이동으로 바뀌는 여러 저장소의 쓰기를 하나로 묶습니다.
+type UnitOfWork interface {
+	// RunInTx fn이 nil을 반환하면 repos로 쓴 내용을 모두 반영하고, 에러를 반환하거나 반영하지 못하면 하나도 반영하지 않습니다.
+	// SQLite는 연결이 하나뿐이므로 fn 안에서 repos가 아닌 저장소를 쓰면 트랜잭션이 끝나기를 기다리며 멈춥니다.
+	// 계좌 잠금은 fn에 들어가기 전에 바깥 저장소로 잡습니다.
+	RunInTx(ctx context.Context, fn func(repos Repositories) error) error
+}
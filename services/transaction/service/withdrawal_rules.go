@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ebank/pkg/money"
+	accountModel "ebank/services/account/model"
+	"ebank/services/transaction/model"
+)
+
+// earlyWithdrawalPenaltyMemo 정기예금을 만기 전에 출금해 뺀 수수료 거래의 메모입니다.
+const earlyWithdrawalPenaltyMemo = "Early withdrawal penalty"
+
+// withdrawalPenalty 계좌 상품의 출금 규칙을 확인하고, 출금액과 함께 뺄 중도 해지 수수료를 반환합니다.
+// closing이면 해지하며 잔액을 모두 옮기는 경우로, 최소 잔액과 월 출금 횟수는 확인하지 않습니다.
+func (s *transactionService) withdrawalPenalty(ctx context.Context, account accountModel.Account, amount money.Money, closing bool) (money.Money, error) {
+	if account.Balance.Currency != amount.Currency {
+		return money.Money{}, status.Errorf(codes.InvalidArgument, "Currency mismatch")
+	}
+	product, ok := account.GetProduct()
+	if !ok {
+		return money.Money{}, status.Errorf(codes.Internal, "Unknown product %q", account.ProductID)
+	}
+
+	now := time.Now()
+	penalty := money.Zero(amount.Currency)
+	if account.IsBeforeMaturity(now) {
+		if product.EarlyWithdrawal == accountModel.EarlyWithdrawalBlocked {
+			return money.Money{}, status.Errorf(codes.FailedPrecondition, "Fixed-term deposit cannot be withdrawn before maturity on %s", account.MaturesAt.Format(time.DateOnly))
+		}
+		penalty = product.Penalty(amount)
+	}
+	if closing {
+		return penalty, nil
+	}
+
+	total, err := amount.Add(penalty)
+	if err != nil {
+		return money.Money{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !account.HasSufficientBalance(total) {
+		return money.Money{}, status.Errorf(codes.FailedPrecondition, "Insufficient balance")
+	}
+	if product.MinimumBalance.IsPositive() {
+		remaining, err := account.Balance.Sub(total)
+		if err != nil {
+			return money.Money{}, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if cmp, err := remaining.Cmp(product.MinimumBalance); err == nil && cmp < 0 {
+			return money.Money{}, status.Errorf(codes.FailedPrecondition, "Balance cannot go below the minimum balance of %s", product.MinimumBalance)
+		}
+	}
+
+	if product.MaxMonthlyWithdrawals > 0 {
+		year, month, _ := now.UTC().Date()
+		withdrawals, err := s.transactionRepository.ListTransactions(ctx, model.TransactionQuery{
+			AccountID:        account.ID,
+			TransactionTypes: []string{model.TransactionTypeWithdrawal, model.TransactionTypeTransferOut},
+			StartDate:        time.Date(year, month, 1, 0, 0, 0, 0, time.UTC),
+			Limit:            product.MaxMonthlyWithdrawals,
+		})
+		if err != nil {
+			return money.Money{}, status.Errorf(codes.Internal, "Failed to load transactions")
+		}
+		if len(withdrawals) >= product.MaxMonthlyWithdrawals {
+			return money.Money{}, status.Errorf(codes.FailedPrecondition, "Monthly withdrawal limit of %d reached", product.MaxMonthlyWithdrawals)
+		}
+	}
+
+	return penalty, nil
+}
+
+// recordPenalty 중도 해지 수수료 거래를 transactionRepository에 기록합니다. penalty가 0이면 기록하지 않고 nil을 반환합니다.
+func recordPenalty(ctx context.Context, transactionRepository TransactionRepository, accountID int64, penalty money.Money, createdAt time.Time) (*model.Transaction, error) {
+	if !penalty.IsPositive() {
+		return nil, nil
+	}
+
+	fee, err := transactionRepository.CreateTransaction(ctx, model.Transaction{
+		AccountID:       accountID,
+		Amount:          penalty,
+		TransactionType: model.TransactionTypeFee,
+		Memo:            earlyWithdrawalPenaltyMemo,
+		CreatedAt:       createdAt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save transaction data")
+	}
+
+	return &fee, nil
+}
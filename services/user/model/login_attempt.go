@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// LoginAttempt 전화번호 또는 접속 IP별 로그인 실패 기록입니다.
+type LoginAttempt struct {
+	Key          string // "phone:<전화번호>" 또는 "ip:<주소>"
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  time.Time // 잠기지 않았으면 zero
+}
@@ -0,0 +1,17 @@
+package model
+
+// 사용자 역할. 역할이 비어 있는 기존 사용자는 RoleCustomer로 취급합니다.
+const (
+	RoleCustomer = "customer" // 본인 계좌와 거래만 다룰 수 있음
+	RoleTeller   = "teller"   // 창구 직원, 모든 계좌 조회와 현금 입금 가능
+	RoleAdmin    = "admin"    // 사용자 관리와 역할 부여 가능
+)
+
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleCustomer, RoleTeller, RoleAdmin:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Session 로그인 한 번으로 시작되는 리프레시 토큰 계열입니다.
+// 리프레시할 때마다 RefreshTokenID가 바뀌며, 이미 교체된 리프레시 토큰이 다시 쓰이면 탈취로 보고 세션을 폐기합니다.
+type Session struct {
+	ID             string
+	UserID         int64
+	RefreshTokenID string
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+	RevokedAt      time.Time // 폐기되지 않았으면 zero
+}
+
+func (session Session) IsRevoked() bool {
+	return !session.RevokedAt.IsZero()
+}
@@ -13,6 +13,21 @@ type User struct {
 	PhoneNumber string
 	Password    string
 	IsDeleted   bool
+	Role        string
+
+	// TOTP 2단계 인증. 등록 중에는 TOTPSecret만 있고 확인 코드를 받으면 TOTPEnabled가 됩니다.
+	TOTPSecret    string
+	TOTPEnabled   bool
+	TOTPLastStep  int64    // 마지막으로 쓴 코드의 시간 단계, 같은 코드를 다시 쓰지 못하게 합니다
+	RecoveryCodes []string // bcrypt 해시, 쓰면 지웁니다
+}
+
+// GetRole 역할이 없는 사용자는 고객으로 취급합니다.
+func (user User) GetRole() string {
+	if user.Role == "" {
+		return RoleCustomer
+	}
+	return user.Role
 }
 
 func (user User) IsCorrectPassword(password string) bool {
@@ -20,6 +35,18 @@ func (user User) IsCorrectPassword(password string) bool {
 	return err == nil
 }
 
+// UseRecoveryCode 맞는 복구 코드를 찾아 지웁니다. 저장은 호출한 쪽에서 합니다.
+func (user *User) UseRecoveryCode(code string) bool {
+	code = strings.ToLower(strings.TrimSpace(code))
+	for i, hashed := range user.RecoveryCodes {
+		if bcrypt.CompareHashAndPassword([]byte(hashed), []byte(code)) == nil {
+			user.RecoveryCodes = append(user.RecoveryCodes[:i:i], user.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 /*
 일반적인 휴대전화 5,6,9,10번쨰 마킹 0107**11**4
 */
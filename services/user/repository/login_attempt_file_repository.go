@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"ebank/pkg/wal"
+	"ebank/services/user/model"
+	"ebank/services/user/service"
+)
+
+type loginAttemptFileRepository struct {
+	attempts map[string]model.LoginAttempt
+	mapMutex sync.RWMutex
+	log      *wal.Log
+}
+
+func NewLoginAttemptFileRepository(filePath string) (service.LoginAttemptRepository, error) {
+	log, err := wal.Open(filePath, wal.DefaultSnapshotInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &loginAttemptFileRepository{
+		attempts: make(map[string]model.LoginAttempt),
+		log:      log,
+	}
+
+	if err := repo.load(); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *loginAttemptFileRepository) load() error {
+	err := r.log.Replay(func(_ int64, data []byte) error {
+		var attempts []model.LoginAttempt
+		if err := json.Unmarshal(data, &attempts); err != nil {
+			return err
+		}
+
+		for _, attempt := range attempts {
+			r.attempts[attempt.Key] = attempt
+		}
+
+		return nil
+	}, func(record wal.Record) error {
+		var attempt model.LoginAttempt
+		if err := json.Unmarshal(record.Data, &attempt); err != nil {
+			return err
+		}
+
+		switch record.Op {
+		case wal.OpPut:
+			r.attempts[attempt.Key] = attempt
+		case wal.OpDelete:
+			delete(r.attempts, attempt.Key)
+		default:
+			return fmt.Errorf("unknown log operation %q", record.Op)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 재생한 로그를 스냅샷으로 합쳐 다음 시작을 빠르게 합니다.
+	return r.snapshot()
+}
+
+func (r *loginAttemptFileRepository) snapshot() error {
+	attempts := make([]model.LoginAttempt, 0, len(r.attempts))
+	for _, attempt := range r.attempts {
+		attempts = append(attempts, attempt)
+	}
+
+	data, err := json.Marshal(attempts)
+	if err != nil {
+		return err
+	}
+
+	return r.log.Snapshot(0, data)
+}
+
+// append 변경 내역을 로그에 기록하고, 로그가 충분히 쌓였으면 스냅샷을 만듭니다.
+func (r *loginAttemptFileRepository) append(op string, attempt model.LoginAttempt) error {
+	if err := r.log.Append(op, attempt); err != nil {
+		return err
+	}
+
+	switch op {
+	case wal.OpPut:
+		r.attempts[attempt.Key] = attempt
+	case wal.OpDelete:
+		delete(r.attempts, attempt.Key)
+	}
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
+}
+
+func (r *loginAttemptFileRepository) GetLoginAttempt(ctx context.Context, key string) (*model.LoginAttempt, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
+	attempt, exists := r.attempts[key]
+	if !exists {
+		return nil, nil
+	}
+
+	return &attempt, nil
+}
+
+func (r *loginAttemptFileRepository) SaveLoginAttempt(ctx context.Context, attempt model.LoginAttempt) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	return r.append(wal.OpPut, attempt)
+}
+
+func (r *loginAttemptFileRepository) DeleteLoginAttempt(ctx context.Context, key string) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	if _, exists := r.attempts[key]; !exists {
+		return nil
+	}
+
+	return r.append(wal.OpDelete, model.LoginAttempt{Key: key})
+}
+
+func (r *loginAttemptFileRepository) DeleteLoginAttemptsBefore(ctx context.Context, before time.Time) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	for key, attempt := range r.attempts {
+		if attempt.LastFailedAt.Before(before) && attempt.LockedUntil.Before(before) {
+			if err := r.append(wal.OpDelete, model.LoginAttempt{Key: key}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"ebank/pkg/sqlite"
+	"ebank/services/user/model"
+	"ebank/services/user/service"
+)
+
+type loginAttemptSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptSQLiteRepository(db *sql.DB) service.LoginAttemptRepository {
+	return &loginAttemptSQLiteRepository{db: db}
+}
+
+func (r *loginAttemptSQLiteRepository) GetLoginAttempt(ctx context.Context, key string) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	var lastFailedAt, lockedUntil int64
+	err := r.db.QueryRowContext(ctx, `SELECT key, failures, last_failed_at, locked_until FROM login_attempts WHERE key = ?`, key).
+		Scan(&attempt.Key, &attempt.Failures, &lastFailedAt, &lockedUntil)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	attempt.LastFailedAt = sqlite.FromUnixNano(lastFailedAt)
+	attempt.LockedUntil = sqlite.FromUnixNano(lockedUntil)
+
+	return &attempt, nil
+}
+
+func (r *loginAttemptSQLiteRepository) SaveLoginAttempt(ctx context.Context, attempt model.LoginAttempt) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO login_attempts (key, failures, last_failed_at, locked_until) VALUES (?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET failures = excluded.failures, last_failed_at = excluded.last_failed_at, locked_until = excluded.locked_until`,
+		attempt.Key, attempt.Failures, sqlite.ToUnixNano(attempt.LastFailedAt), sqlite.ToUnixNano(attempt.LockedUntil),
+	)
+	return err
+}
+
+func (r *loginAttemptSQLiteRepository) DeleteLoginAttempt(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE key = ?`, key)
+	return err
+}
+
+func (r *loginAttemptSQLiteRepository) DeleteLoginAttemptsBefore(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM login_attempts WHERE last_failed_at < ? AND locked_until < ?`,
+		sqlite.ToUnixNano(before), sqlite.ToUnixNano(before),
+	)
+	return err
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"ebank/pkg/wal"
+	"ebank/services/user/model"
+	"ebank/services/user/service"
+)
+
+type sessionFileRepository struct {
+	sessions map[string]model.Session
+	mapMutex sync.RWMutex
+	log      *wal.Log
+}
+
+func NewSessionFileRepository(filePath string) (service.SessionRepository, error) {
+	log, err := wal.Open(filePath, wal.DefaultSnapshotInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &sessionFileRepository{
+		sessions: make(map[string]model.Session),
+		log:      log,
+	}
+
+	if err := repo.load(); err != nil {
+		log.Close()
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *sessionFileRepository) load() error {
+	err := r.log.Replay(func(_ int64, data []byte) error {
+		var sessions []model.Session
+		if err := json.Unmarshal(data, &sessions); err != nil {
+			return err
+		}
+
+		for _, session := range sessions {
+			r.sessions[session.ID] = session
+		}
+
+		return nil
+	}, func(record wal.Record) error {
+		if record.Op != wal.OpPut {
+			return fmt.Errorf("unknown log operation %q", record.Op)
+		}
+
+		var session model.Session
+		if err := json.Unmarshal(record.Data, &session); err != nil {
+			return err
+		}
+		r.sessions[session.ID] = session
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 재생한 로그를 스냅샷으로 합쳐 다음 시작을 빠르게 합니다.
+	return r.snapshot()
+}
+
+// snapshot 만료된 세션은 리프레시할 수 없으므로 스냅샷에서 뺍니다.
+func (r *sessionFileRepository) snapshot() error {
+	now := time.Now()
+	sessions := make([]model.Session, 0, len(r.sessions))
+	for id, session := range r.sessions {
+		if session.ExpiresAt.Before(now) {
+			delete(r.sessions, id)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	data, err := json.Marshal(sessions)
+	if err != nil {
+		return err
+	}
+
+	return r.log.Snapshot(0, data)
+}
+
+// put 세션을 로그에 기록한 뒤 맵에 반영합니다. mapMutex를 잡은 상태에서 호출해야 합니다.
+func (r *sessionFileRepository) put(session model.Session) error {
+	if err := r.log.Append(wal.OpPut, session); err != nil {
+		return err
+	}
+
+	r.sessions[session.ID] = session
+
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
+	}
+
+	return nil
+}
+
+func (r *sessionFileRepository) CreateSession(ctx context.Context, session model.Session) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	if _, exists := r.sessions[session.ID]; exists {
+		return fmt.Errorf("session %s already exists", session.ID)
+	}
+
+	return r.put(session)
+}
+
+func (r *sessionFileRepository) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	r.mapMutex.RLock()
+	defer r.mapMutex.RUnlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return nil, fmt.Errorf("session %s not found", id)
+	}
+
+	return &session, nil
+}
+
+func (r *sessionFileRepository) RotateRefreshToken(ctx context.Context, id string, oldTokenID string, newTokenID string) (bool, error) {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return false, fmt.Errorf("session %s not found", id)
+	}
+	if session.IsRevoked() || session.RefreshTokenID != oldTokenID {
+		return false, nil
+	}
+
+	session.RefreshTokenID = newTokenID
+	if err := r.put(session); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *sessionFileRepository) RevokeSession(ctx context.Context, id string, revokedAt time.Time) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return fmt.Errorf("session %s not found", id)
+	}
+	if session.IsRevoked() {
+		return nil
+	}
+
+	session.RevokedAt = revokedAt
+	return r.put(session)
+}
+
+func (r *sessionFileRepository) RevokeUserSessions(ctx context.Context, userID int64, revokedAt time.Time) error {
+	r.mapMutex.Lock()
+	defer r.mapMutex.Unlock()
+
+	for _, session := range r.sessions {
+		if session.UserID != userID || session.IsRevoked() {
+			continue
+		}
+
+		session.RevokedAt = revokedAt
+		if err := r.put(session); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"ebank/pkg/sqlite"
+	"ebank/services/user/model"
+	"ebank/services/user/service"
+)
+
+type sessionSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewSessionSQLiteRepository(db *sql.DB) service.SessionRepository {
+	return &sessionSQLiteRepository{db: db}
+}
+
+func (r *sessionSQLiteRepository) CreateSession(ctx context.Context, session model.Session) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO sessions (id, user_id, refresh_token_id, created_at, expires_at, revoked_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		session.ID, session.UserID, session.RefreshTokenID,
+		sqlite.ToUnixNano(session.CreatedAt), sqlite.ToUnixNano(session.ExpiresAt), sqlite.ToUnixNano(session.RevokedAt),
+	)
+	return err
+}
+
+func (r *sessionSQLiteRepository) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	var session model.Session
+	var createdAt, expiresAt, revokedAt int64
+	err := r.db.QueryRowContext(ctx, `SELECT id, user_id, refresh_token_id, created_at, expires_at, revoked_at FROM sessions WHERE id = ?`, id).
+		Scan(&session.ID, &session.UserID, &session.RefreshTokenID, &createdAt, &expiresAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("session %s not found", id)
+	} else if err != nil {
+		return nil, err
+	}
+
+	session.CreatedAt = sqlite.FromUnixNano(createdAt)
+	session.ExpiresAt = sqlite.FromUnixNano(expiresAt)
+	session.RevokedAt = sqlite.FromUnixNano(revokedAt)
+
+	return &session, nil
+}
+
+func (r *sessionSQLiteRepository) RotateRefreshToken(ctx context.Context, id string, oldTokenID string, newTokenID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET refresh_token_id = ? WHERE id = ? AND refresh_token_id = ? AND revoked_at = 0`,
+		newTokenID, id, oldTokenID,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *sessionSQLiteRepository) RevokeSession(ctx context.Context, id string, revokedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET revoked_at = ? WHERE id = ? AND revoked_at = 0`,
+		sqlite.ToUnixNano(revokedAt), id,
+	)
+	return err
+}
+
+func (r *sessionSQLiteRepository) RevokeUserSessions(ctx context.Context, userID int64, revokedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at = 0`,
+		sqlite.ToUnixNano(revokedAt), userID,
+	)
+	return err
+}
@@ -4,10 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"os"
 	"sync"
 
+	"ebank/pkg/wal"
 	"ebank/services/user/model"
 	"ebank/services/user/service"
 )
@@ -16,21 +15,25 @@ type userFileRepository struct {
 	users              map[int64]model.User
 	usersByPhoneNumber map[string]int64
 	mapMutex           sync.RWMutex
-	fileMutex          sync.RWMutex
 	nextID             int64
-	filePath           string
+	log                *wal.Log
 }
 
 func NewUserFileRepository(filePath string) (service.UserRepository, error) {
+	log, err := wal.Open(filePath, wal.DefaultSnapshotInterval)
+	if err != nil {
+		return nil, err
+	}
+
 	repo := &userFileRepository{
 		users:              make(map[int64]model.User),
 		usersByPhoneNumber: make(map[string]int64),
-		filePath:           filePath,
 		mapMutex:           sync.RWMutex{},
-		fileMutex:          sync.RWMutex{},
+		log:                log,
 	}
 
 	if err := repo.load(); err != nil {
+		log.Close()
 		return nil, err
 	}
 
@@ -38,30 +41,74 @@ func NewUserFileRepository(filePath string) (service.UserRepository, error) {
 }
 
 func (r *userFileRepository) load() error {
-	data, err := ioutil.ReadFile(r.filePath)
-	if os.IsNotExist(err) {
-		return nil // 파일이 없으면 새로 시작
-	} else if err != nil {
+	err := r.log.Replay(func(sequence int64, data []byte) error {
+		var users []model.User
+		if err := json.Unmarshal(data, &users); err != nil {
+			return err
+		}
+
+		var maxID int64
+		for _, user := range users {
+			r.put(user)
+			if user.ID > maxID {
+				maxID = user.ID
+			}
+		}
+
+		nextID, err := wal.CheckSequence(sequence, maxID)
+		if err != nil {
+			return err
+		}
+		r.nextID = nextID
+
+		return nil
+	}, func(record wal.Record) error {
+		if record.Op != wal.OpPut {
+			return fmt.Errorf("unknown log operation %q", record.Op)
+		}
+
+		var user model.User
+		if err := json.Unmarshal(record.Data, &user); err != nil {
+			return err
+		}
+		r.put(user)
+		if user.ID > r.nextID {
+			r.nextID = user.ID
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	var users []model.User
-	if err := json.Unmarshal(data, &users); err != nil {
+	// 재생한 로그를 스냅샷으로 합쳐 다음 시작을 빠르게 합니다.
+	return r.snapshot()
+}
+
+// put 사용자를 맵과 전화번호 인덱스에 반영합니다. mapMutex를 잡은 상태에서 호출해야 합니다.
+func (r *userFileRepository) put(user model.User) {
+	if oldUser, exists := r.users[user.ID]; exists && oldUser.PhoneNumber != user.PhoneNumber {
+		delete(r.usersByPhoneNumber, oldUser.PhoneNumber)
+	}
+
+	r.users[user.ID] = user
+	r.usersByPhoneNumber[user.PhoneNumber] = user.ID
+}
+
+// append 변경 내역을 로그에 기록하고, 로그가 충분히 쌓였으면 스냅샷을 만듭니다.
+func (r *userFileRepository) append(op string, v any) error {
+	if err := r.log.Append(op, v); err != nil {
 		return err
 	}
 
-	for i, user := range users {
-		r.users[user.ID] = user
-		r.usersByPhoneNumber[user.PhoneNumber] = user.ID
-		if i == len(users)-1 {
-			r.nextID = user.ID
-		}
+	if r.log.NeedsSnapshot() {
+		return r.snapshot()
 	}
 
 	return nil
 }
 
-func (r *userFileRepository) save() error {
+func (r *userFileRepository) snapshot() error {
 	users := make([]model.User, 0, len(r.users))
 	for _, user := range r.users {
 		users = append(users, user)
@@ -72,7 +119,7 @@ func (r *userFileRepository) save() error {
 		return err
 	}
 
-	return ioutil.WriteFile(r.filePath, data, 0644)
+	return r.log.Snapshot(r.nextID, data)
 }
 
 func (r *userFileRepository) CreateUser(ctx context.Context, user model.User) (model.User, error) {
@@ -82,15 +129,14 @@ func (r *userFileRepository) CreateUser(ctx context.Context, user model.User) (m
 	if _, exists := r.usersByPhoneNumber[user.PhoneNumber]; exists {
 		return model.User{}, fmt.Errorf("user with phone number %s already exists", user.PhoneNumber)
 	}
-	r.nextID++
-	user.ID = r.nextID
-	r.users[user.ID] = user
-	r.usersByPhoneNumber[user.PhoneNumber] = user.ID
-
-	if err := r.save(); err != nil {
+	user.ID = r.nextID + 1
+	if err := r.append(wal.OpPut, user); err != nil {
 		return model.User{}, err
 	}
 
+	r.nextID = user.ID
+	r.put(user)
+
 	return user, nil
 }
 
@@ -129,15 +175,13 @@ func (r *userFileRepository) UpdateUser(ctx context.Context, user model.User) er
 		return fmt.Errorf("user with ID %d not found", user.ID)
 	}
 
-	oldUser := r.users[user.ID]
-	if oldUser.PhoneNumber != user.PhoneNumber {
-		delete(r.usersByPhoneNumber, oldUser.PhoneNumber)
-		r.usersByPhoneNumber[user.PhoneNumber] = user.ID
+	if err := r.append(wal.OpPut, user); err != nil {
+		return err
 	}
 
-	r.users[user.ID] = user
+	r.put(user)
 
-	return r.save()
+	return nil
 }
 
 func (r *userFileRepository) GetAllUsers(ctx context.Context, isDeleted *bool) ([]model.User, error) {
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"ebank/services/user/model"
+	"ebank/services/user/service"
+)
+
+const userColumns = `id, name, birth, phone_number, password, is_deleted, role, totp_secret, totp_enabled, totp_last_step, recovery_codes`
+
+type userSQLiteRepository struct {
+	db *sql.DB
+}
+
+func NewUserSQLiteRepository(db *sql.DB) service.UserRepository {
+	return &userSQLiteRepository{db: db}
+}
+
+func scanUser(row interface{ Scan(...any) error }) (model.User, error) {
+	var user model.User
+	var recoveryCodes string
+	err := row.Scan(&user.ID, &user.Name, &user.Birth, &user.PhoneNumber, &user.Password, &user.IsDeleted, &user.Role,
+		&user.TOTPSecret, &user.TOTPEnabled, &user.TOTPLastStep, &recoveryCodes)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if err := json.Unmarshal([]byte(recoveryCodes), &user.RecoveryCodes); err != nil {
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
+func marshalRecoveryCodes(codes []string) (string, error) {
+	if codes == nil {
+		codes = []string{}
+	}
+	data, err := json.Marshal(codes)
+	return string(data), err
+}
+
+func (r *userSQLiteRepository) CreateUser(ctx context.Context, user model.User) (model.User, error) {
+	recoveryCodes, err := marshalRecoveryCodes(user.RecoveryCodes)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO users (name, birth, phone_number, password, is_deleted, role, totp_secret, totp_enabled, totp_last_step, recovery_codes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (phone_number) DO NOTHING`,
+		user.Name, user.Birth, user.PhoneNumber, user.Password, user.IsDeleted, user.GetRole(),
+		user.TOTPSecret, user.TOTPEnabled, user.TOTPLastStep, recoveryCodes,
+	)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return model.User{}, err
+	} else if affected == 0 {
+		return model.User{}, fmt.Errorf("user with phone number %s already exists", user.PhoneNumber)
+	}
+
+	user.ID, err = result.LastInsertId()
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return user, nil
+}
+
+func (r *userSQLiteRepository) GetUserByID(ctx context.Context, id int64) (*model.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ? AND is_deleted = 0`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("user with ID %d not found", id)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *userSQLiteRepository) GetUserByPhoneNumber(ctx context.Context, phoneNumber string) (model.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE phone_number = ?`, phoneNumber))
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, fmt.Errorf("user with phone number %s not found", phoneNumber)
+	}
+
+	return user, err
+}
+
+func (r *userSQLiteRepository) UpdateUser(ctx context.Context, user model.User) error {
+	recoveryCodes, err := marshalRecoveryCodes(user.RecoveryCodes)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET name = ?, birth = ?, phone_number = ?, password = ?, is_deleted = ?, role = ?,
+		totp_secret = ?, totp_enabled = ?, totp_last_step = ?, recovery_codes = ? WHERE id = ?`,
+		user.Name, user.Birth, user.PhoneNumber, user.Password, user.IsDeleted, user.GetRole(),
+		user.TOTPSecret, user.TOTPEnabled, user.TOTPLastStep, recoveryCodes, user.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("user with ID %d not found", user.ID)
+	}
+
+	return nil
+}
+
+func (r *userSQLiteRepository) GetAllUsers(ctx context.Context, isDeleted *bool) ([]model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users`
+	args := []any{}
+	if isDeleted != nil {
+		query += ` WHERE is_deleted = ?`
+		args = append(args, *isDeleted)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []model.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
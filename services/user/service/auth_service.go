@@ -2,48 +2,156 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 
 	ebank "ebank/api/v1"
 	"ebank/pkg/jwt_manager"
 	"ebank/pkg/zero"
+	"ebank/services/user/model"
 )
 
 type authService struct {
 	ebank.UnimplementedAuthServiceServer
-	userRepository UserRepository
-	jwtManager     jwt_manager.JWTManager
+	userHelper        UserHelper
+	userRepository    UserRepository
+	sessionRepository SessionRepository
+	jwtManager        jwt_manager.JWTManager
+	loginLimiter      *LoginLimiter
 }
 
 func (a *authService) Login(ctx context.Context, req *ebank.LoginRequest) (*ebank.LoginResponse, error) {
-	user, err := a.userRepository.GetUserByPhoneNumber(ctx, req.GetPhoneNumber())
+	var user model.User
+	err := a.loginLimiter.Guard(ctx, req.GetPhoneNumber(), clientIP(ctx), func() error {
+		var err error
+		user, err = a.userRepository.GetUserByPhoneNumber(ctx, req.GetPhoneNumber())
+		if err != nil || zero.IsStructZero(user) {
+			return status.Errorf(codes.NotFound, "User not found")
+		}
+
+		if !user.IsCorrectPassword(req.Password) {
+			return status.Errorf(codes.Unauthenticated, "Invalid password")
+		}
+
+		// 2단계 인증을 등록한 사용자는 코드까지 맞아야 합니다. 틀린 코드는 실패로 셉니다.
+		if user.TOTPEnabled {
+			if req.GetOtpCode() == "" {
+				return status.Errorf(codes.FailedPrecondition, "OTP code required")
+			}
+			return a.userHelper.VerifyOTP(ctx, user.ID, req.GetOtpCode())
+		}
+
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	if zero.IsStructZero(user) {
-		return nil, status.Errorf(codes.NotFound, "User not found")
+
+	session := a.jwtManager.NewSession(user.ID)
+	if err := a.sessionRepository.CreateSession(ctx, session); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save session")
+	}
+
+	return a.generateTokens(user, session)
+}
+
+func (a *authService) RefreshToken(ctx context.Context, req *ebank.RefreshTokenRequest) (*ebank.LoginResponse, error) {
+	claims, err := a.jwtManager.VerifyRefreshToken(req.GetRefreshToken())
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token is invalid: %v", err)
+	}
+
+	session, err := a.sessionRepository.GetSession(ctx, claims.SessionID)
+	if err != nil || session == nil || session.IsRevoked() {
+		return nil, status.Errorf(codes.Unauthenticated, "refresh token is revoked")
+	}
+
+	// 이미 교체된 리프레시 토큰이 다시 들어오면 탈취된 것으로 보고 세션 전체를 폐기합니다.
+	if session.RefreshTokenID != claims.Id {
+		return nil, a.revokeReusedSession(ctx, session.ID)
+	}
+
+	user, err := a.userRepository.GetUserByID(ctx, session.UserID)
+	if err != nil || user == nil || user.IsDeleted {
+		return nil, status.Errorf(codes.Unauthenticated, "User not found")
 	}
 
-	if !user.IsCorrectPassword(req.Password) {
-		return nil, status.Errorf(codes.Unauthenticated, "Invalid password")
+	newTokenID := jwt_manager.NewTokenID()
+	rotated, err := a.sessionRepository.RotateRefreshToken(ctx, session.ID, claims.Id, newTokenID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to rotate refresh token")
+	}
+	if !rotated {
+		return nil, a.revokeReusedSession(ctx, session.ID)
+	}
+	session.RefreshTokenID = newTokenID
+
+	return a.generateTokens(*user, *session)
+}
+
+func (a *authService) Logout(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	claims, ok := jwt_manager.FromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+
+	if err := a.sessionRepository.RevokeSession(ctx, claims.SessionID, time.Now()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to revoke session")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (a *authService) LogoutAllSessions(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	claims, ok := jwt_manager.FromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+
+	if err := a.sessionRepository.RevokeUserSessions(ctx, claims.UserID, time.Now()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to revoke sessions")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (a *authService) revokeReusedSession(ctx context.Context, sessionID string) error {
+	if err := a.sessionRepository.RevokeSession(ctx, sessionID, time.Now()); err != nil {
+		return status.Errorf(codes.Internal, "Failed to revoke session")
+	}
+
+	return status.Errorf(codes.Unauthenticated, "refresh token was already used")
+}
+
+func (a *authService) generateTokens(user model.User, session model.Session) (*ebank.LoginResponse, error) {
+	accessToken, err := a.jwtManager.Generate(user, session.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to generate token")
 	}
 
-	tokenString, err := a.jwtManager.Generate(user)
+	refreshToken, err := a.jwtManager.GenerateRefreshToken(session)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to generate token")
 	}
 
-	return &ebank.LoginResponse{Token: tokenString}, nil
+	return &ebank.LoginResponse{Token: accessToken, RefreshToken: refreshToken}, nil
 }
 
 func NewAuthService(
+	userHelper UserHelper,
 	userRepository UserRepository,
+	sessionRepository SessionRepository,
 	jwtManager jwt_manager.JWTManager,
+	loginLimiter *LoginLimiter,
 ) ebank.AuthServiceServer {
 	return &authService{
-		userRepository: userRepository,
-		jwtManager:     jwtManager,
+		userHelper:        userHelper,
+		userRepository:    userRepository,
+		sessionRepository: sessionRepository,
+		jwtManager:        jwtManager,
+		loginLimiter:      loginLimiter,
 	}
 }
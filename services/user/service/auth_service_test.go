@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ebank/api/v1"
+	"ebank/mocks"
+	"ebank/pkg/jwt_manager"
+	"ebank/services/user/model"
+)
+
+func TestAuthServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuthServiceTestSuite))
+}
+
+type AuthServiceTestSuite struct {
+	suite.Suite
+	userRepository    *mocks.UserRepository
+	sessionRepository *mocks.SessionRepository
+	loginAttempts     *mocks.LoginAttemptRepository
+	userHelper        *mocks.UserHelper
+	jwtManager        *mocks.JWTManager
+	service           ebank.AuthServiceServer
+}
+
+func (ts *AuthServiceTestSuite) SetupTest() {
+	ts.userRepository = mocks.NewUserRepository(ts.T())
+	ts.sessionRepository = mocks.NewSessionRepository(ts.T())
+	ts.loginAttempts = mocks.NewLoginAttemptRepository(ts.T())
+	ts.userHelper = mocks.NewUserHelper(ts.T())
+	ts.jwtManager = mocks.NewJWTManager(ts.T())
+	loginLimiter := NewLoginLimiter(ts.loginAttempts, LoginPolicy{MaxFailures: 3, MaxFailuresPerIP: 10, Backoff: time.Second, Lockout: time.Minute})
+	ts.service = NewAuthService(ts.userHelper, ts.userRepository, ts.sessionRepository, ts.jwtManager, loginLimiter)
+}
+
+func (ts *AuthServiceTestSuite) refreshClaims(tokenID string) *jwt_manager.RefreshClaims {
+	return &jwt_manager.RefreshClaims{
+		StandardClaims: jwt.StandardClaims{Id: tokenID},
+		UserID:         1,
+		SessionID:      "session",
+		TokenType:      jwt_manager.TokenTypeRefresh,
+	}
+}
+
+func (ts *AuthServiceTestSuite) Test_RefreshToken_Rotates() {
+	ts.jwtManager.EXPECT().VerifyRefreshToken("refresh").Return(ts.refreshClaims("old"), nil)
+	ts.sessionRepository.EXPECT().GetSession(mock.Anything, "session").
+		Return(&model.Session{ID: "session", UserID: 1, RefreshTokenID: "old"}, nil)
+	ts.userRepository.EXPECT().GetUserByID(mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+	ts.sessionRepository.EXPECT().RotateRefreshToken(mock.Anything, "session", "old", mock.Anything).Return(true, nil)
+	ts.jwtManager.EXPECT().Generate(model.User{ID: 1}, "session").Return("access", nil)
+	ts.jwtManager.EXPECT().GenerateRefreshToken(mock.MatchedBy(func(session model.Session) bool {
+		return session.ID == "session" && session.RefreshTokenID != "old"
+	})).Return("new-refresh", nil)
+
+	resp, err := ts.service.RefreshToken(context.Background(), &ebank.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	ts.NoError(err)
+	ts.Equal("access", resp.Token)
+	ts.Equal("new-refresh", resp.RefreshToken)
+}
+
+func (ts *AuthServiceTestSuite) Test_RefreshToken_ReuseRevokesSession() {
+	ts.jwtManager.EXPECT().VerifyRefreshToken("refresh").Return(ts.refreshClaims("old"), nil)
+	ts.sessionRepository.EXPECT().GetSession(mock.Anything, "session").
+		Return(&model.Session{ID: "session", UserID: 1, RefreshTokenID: "rotated"}, nil)
+	ts.sessionRepository.EXPECT().RevokeSession(mock.Anything, "session", mock.Anything).Return(nil)
+
+	_, err := ts.service.RefreshToken(context.Background(), &ebank.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	ts.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func (ts *AuthServiceTestSuite) Test_RefreshToken_ConcurrentReuseRevokesSession() {
+	ts.jwtManager.EXPECT().VerifyRefreshToken("refresh").Return(ts.refreshClaims("old"), nil)
+	ts.sessionRepository.EXPECT().GetSession(mock.Anything, "session").
+		Return(&model.Session{ID: "session", UserID: 1, RefreshTokenID: "old"}, nil)
+	ts.userRepository.EXPECT().GetUserByID(mock.Anything, int64(1)).Return(&model.User{ID: 1}, nil)
+	ts.sessionRepository.EXPECT().RotateRefreshToken(mock.Anything, "session", "old", mock.Anything).Return(false, nil)
+	ts.sessionRepository.EXPECT().RevokeSession(mock.Anything, "session", mock.Anything).Return(nil)
+
+	_, err := ts.service.RefreshToken(context.Background(), &ebank.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	ts.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func (ts *AuthServiceTestSuite) Test_LogoutAllSessions() {
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: 1, SessionID: "session"})
+	ts.sessionRepository.EXPECT().RevokeUserSessions(mock.Anything, int64(1), mock.Anything).Return(nil)
+
+	_, err := ts.service.LogoutAllSessions(ctx, nil)
+
+	ts.NoError(err)
+}
+
+func (ts *AuthServiceTestSuite) Test_Login_LockedOut() {
+	ts.loginAttempts.EXPECT().GetLoginAttempt(mock.Anything, "phone:01012345678").
+		Return(&model.LoginAttempt{Key: "phone:01012345678", Failures: 3, LastFailedAt: time.Now(), LockedUntil: time.Now().Add(time.Minute)}, nil)
+
+	_, err := ts.service.Login(context.Background(), &ebank.LoginRequest{PhoneNumber: "01012345678", Password: "password"})
+
+	ts.Equal(codes.ResourceExhausted, status.Code(err))
+}
+
+func (ts *AuthServiceTestSuite) Test_Login_WrongPasswordCountsFailure() {
+	ts.loginAttempts.EXPECT().GetLoginAttempt(mock.Anything, "phone:01012345678").Return(nil, nil)
+	ts.userRepository.EXPECT().GetUserByPhoneNumber(mock.Anything, "01012345678").
+		Return(model.User{ID: 1, PhoneNumber: "01012345678", Password: "not a bcrypt hash"}, nil)
+	ts.loginAttempts.EXPECT().SaveLoginAttempt(mock.Anything, mock.MatchedBy(func(attempt model.LoginAttempt) bool {
+		return attempt.Key == "phone:01012345678" && attempt.Failures == 1 && attempt.LockedUntil.IsZero()
+	})).Return(nil)
+	ts.loginAttempts.EXPECT().DeleteLoginAttemptsBefore(mock.Anything, mock.Anything).Return(nil)
+
+	_, err := ts.service.Login(context.Background(), &ebank.LoginRequest{PhoneNumber: "01012345678", Password: "password"})
+
+	ts.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func (ts *AuthServiceTestSuite) Test_Login_OTPRequired() {
+	password, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	ts.loginAttempts.EXPECT().GetLoginAttempt(mock.Anything, "phone:01012345678").Return(nil, nil)
+	ts.userRepository.EXPECT().GetUserByPhoneNumber(mock.Anything, "01012345678").
+		Return(model.User{ID: 1, PhoneNumber: "01012345678", Password: string(password), TOTPEnabled: true}, nil)
+
+	_, err := ts.service.Login(context.Background(), &ebank.LoginRequest{PhoneNumber: "01012345678", Password: "password"})
+
+	// 코드가 없는 것은 실패로 세지 않습니다.
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+	ts.loginAttempts.AssertNotCalled(ts.T(), "SaveLoginAttempt", mock.Anything, mock.Anything)
+}
+
+func (ts *AuthServiceTestSuite) Test_Login_WithOTP() {
+	password, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user := model.User{ID: 1, PhoneNumber: "01012345678", Password: string(password), TOTPEnabled: true}
+	ts.loginAttempts.EXPECT().GetLoginAttempt(mock.Anything, "phone:01012345678").Return(nil, nil)
+	ts.userRepository.EXPECT().GetUserByPhoneNumber(mock.Anything, "01012345678").Return(user, nil)
+	ts.userHelper.EXPECT().VerifyOTP(mock.Anything, int64(1), "123456").Return(nil)
+	ts.loginAttempts.EXPECT().DeleteLoginAttempt(mock.Anything, "phone:01012345678").Return(nil)
+	ts.jwtManager.EXPECT().NewSession(int64(1)).Return(model.Session{ID: "session", UserID: 1})
+	ts.sessionRepository.EXPECT().CreateSession(mock.Anything, mock.Anything).Return(nil)
+	ts.jwtManager.EXPECT().Generate(user, "session").Return("access", nil)
+	ts.jwtManager.EXPECT().GenerateRefreshToken(mock.Anything).Return("refresh", nil)
+
+	resp, err := ts.service.Login(context.Background(), &ebank.LoginRequest{PhoneNumber: "01012345678", Password: "password", OtpCode: "123456"})
+
+	ts.NoError(err)
+	ts.Equal("access", resp.Token)
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"ebank/services/user/model"
+)
+
+type LoginAttemptRepository interface {
+	// GetLoginAttempt 기록이 없으면 nil을 반환합니다.
+	GetLoginAttempt(ctx context.Context, key string) (*model.LoginAttempt, error)
+	SaveLoginAttempt(ctx context.Context, attempt model.LoginAttempt) error
+	DeleteLoginAttempt(ctx context.Context, key string) error
+	// DeleteLoginAttemptsBefore 마지막 실패와 잠금 해제가 모두 before 이전인 기록을 지웁니다.
+	DeleteLoginAttemptsBefore(ctx context.Context, before time.Time) error
+}
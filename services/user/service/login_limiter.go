@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"ebank/services/user/model"
+)
+
+// cleanupInterval 오래된 실패 기록을 지우는 최소 간격입니다.
+const cleanupInterval = time.Hour
+
+// LoginPolicy 로그인 실패에 대한 백오프와 잠금 기준입니다.
+type LoginPolicy struct {
+	MaxFailures      int           // 전화번호별 연속 실패 허용 횟수
+	MaxFailuresPerIP int           // 접속 IP별 연속 실패 허용 횟수
+	Backoff          time.Duration // 전화번호별 재시도 대기 시간의 시작값, 실패할 때마다 두 배
+	Lockout          time.Duration // 잠금 시간. 마지막 실패 후 이만큼 지나면 실패 횟수도 초기화
+}
+
+// LoginLimiter 전화번호와 접속 IP별로 로그인 실패를, 사용자별로 OTP 코드 확인 실패를 세어 무차별 대입을 막습니다.
+// 같은 전화번호의 로그인은 한 번에 하나씩 처리해, 동시에 보낸 추측이 백오프를 건너뛰지 못하게 합니다.
+type LoginLimiter struct {
+	repository  LoginAttemptRepository
+	policy      LoginPolicy
+	now         func() time.Time
+	keyMutex    map[string]*keyLock
+	mapMutex    sync.Mutex
+	lastCleanup time.Time
+}
+
+func NewLoginLimiter(repository LoginAttemptRepository, policy LoginPolicy) *LoginLimiter {
+	return &LoginLimiter{
+		repository: repository,
+		policy:     policy,
+		now:        time.Now,
+		keyMutex:   make(map[string]*keyLock),
+	}
+}
+
+func phoneNumberKey(phoneNumber string) string {
+	return "phone:" + phoneNumber
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func otpKey(userID int64) string {
+	return fmt.Sprintf("otp:%d", userID)
+}
+
+// Guard 잠금과 백오프를 확인한 뒤 login을 실행합니다.
+// login이 Unauthenticated나 NotFound를 반환하면 실패로 세고, 성공하면 전화번호의 실패 기록을 지웁니다.
+func (l *LoginLimiter) Guard(ctx context.Context, phoneNumber string, ip string, login func() error) error {
+	unlock := l.lock(phoneNumberKey(phoneNumber))
+	defer unlock()
+
+	now := l.now()
+	phoneAttempt, err := l.get(ctx, phoneNumberKey(phoneNumber), now)
+	if err != nil {
+		return err
+	}
+	if err := l.check(phoneAttempt, now, true, "Too many failed login attempts"); err != nil {
+		return err
+	}
+
+	var ipAttempt model.LoginAttempt
+	if ip != "" {
+		ipAttempt, err = l.get(ctx, ipKey(ip), now)
+		if err != nil {
+			return err
+		}
+		if err := l.check(ipAttempt, now, false, "Too many failed login attempts"); err != nil {
+			return err
+		}
+	}
+
+	loginErr := login()
+	switch status.Code(loginErr) {
+	case codes.OK:
+		if err := l.repository.DeleteLoginAttempt(ctx, phoneAttempt.Key); err != nil {
+			return status.Errorf(codes.Internal, "Failed to reset login attempts")
+		}
+		return nil
+	case codes.Unauthenticated, codes.NotFound:
+		if err := l.recordFailure(ctx, phoneAttempt, l.policy.MaxFailures, now); err != nil {
+			return err
+		}
+		if ip != "" {
+			if err := l.recordFailure(ctx, ipAttempt, l.policy.MaxFailuresPerIP, now); err != nil {
+				return err
+			}
+		}
+		l.cleanup(ctx, now)
+		return loginErr
+	default:
+		return loginErr
+	}
+}
+
+// GuardOTP 잠금과 백오프를 확인한 뒤 verify를 실행합니다. 로그인과 같은 기준으로 사용자별로 세며,
+// verify가 Unauthenticated를 반환하면 실패로 세고 성공하면 실패 기록을 지웁니다.
+// 로그인, 큰 금액의 출금과 이체, 2단계 인증 해제가 같은 실패 횟수를 나눠 씁니다.
+func (l *LoginLimiter) GuardOTP(ctx context.Context, userID int64, verify func() error) error {
+	key := otpKey(userID)
+	unlock := l.lock(key)
+	defer unlock()
+
+	now := l.now()
+	attempt, err := l.get(ctx, key, now)
+	if err != nil {
+		return err
+	}
+	if err := l.check(attempt, now, true, "Too many failed OTP attempts"); err != nil {
+		return err
+	}
+
+	verifyErr := verify()
+	switch status.Code(verifyErr) {
+	case codes.OK:
+		if attempt.Failures == 0 {
+			return nil
+		}
+		if err := l.repository.DeleteLoginAttempt(ctx, key); err != nil {
+			return status.Errorf(codes.Internal, "Failed to reset OTP attempts")
+		}
+		return nil
+	case codes.Unauthenticated:
+		if err := l.recordFailure(ctx, attempt, l.policy.MaxFailures, now); err != nil {
+			return err
+		}
+		l.cleanup(ctx, now)
+		return verifyErr
+	default:
+		return verifyErr
+	}
+}
+
+// Unlock 관리자가 잠긴 전화번호나 IP를 풉니다. 빈 값은 건너뜁니다.
+func (l *LoginLimiter) Unlock(ctx context.Context, phoneNumber string, ip string) error {
+	if phoneNumber != "" {
+		if err := l.repository.DeleteLoginAttempt(ctx, phoneNumberKey(phoneNumber)); err != nil {
+			return err
+		}
+	}
+	if ip != "" {
+		if err := l.repository.DeleteLoginAttempt(ctx, ipKey(ip)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// get 저장된 실패 기록을 읽습니다. 마지막 실패 후 잠금 시간이 지났으면 처음부터 다시 셉니다.
+func (l *LoginLimiter) get(ctx context.Context, key string, now time.Time) (model.LoginAttempt, error) {
+	attempt, err := l.repository.GetLoginAttempt(ctx, key)
+	if err != nil {
+		return model.LoginAttempt{}, status.Errorf(codes.Internal, "Failed to load login attempts")
+	}
+	if attempt == nil || (now.Sub(attempt.LastFailedAt) >= l.policy.Lockout && !now.Before(attempt.LockedUntil)) {
+		return model.LoginAttempt{Key: key}, nil
+	}
+
+	return *attempt, nil
+}
+
+func (l *LoginLimiter) check(attempt model.LoginAttempt, now time.Time, backoff bool, message string) error {
+	retryAt := attempt.LockedUntil
+	if backoff && attempt.Failures > 0 {
+		if delayedUntil := attempt.LastFailedAt.Add(l.backoff(attempt.Failures)); delayedUntil.After(retryAt) {
+			retryAt = delayedUntil
+		}
+	}
+
+	if !now.Before(retryAt) {
+		return nil
+	}
+
+	st := status.New(codes.ResourceExhausted, message)
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAt.Sub(now))}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// backoff failures번 실패한 뒤의 대기 시간입니다. 잠금 시간을 넘지 않습니다.
+func (l *LoginLimiter) backoff(failures int) time.Duration {
+	delay := l.policy.Backoff
+	for i := 1; i < failures && delay < l.policy.Lockout; i++ {
+		delay *= 2
+	}
+	if delay > l.policy.Lockout {
+		delay = l.policy.Lockout
+	}
+	return delay
+}
+
+func (l *LoginLimiter) recordFailure(ctx context.Context, attempt model.LoginAttempt, maxFailures int, now time.Time) error {
+	attempt.Failures++
+	attempt.LastFailedAt = now
+	if attempt.Failures >= maxFailures {
+		attempt.LockedUntil = now.Add(l.policy.Lockout)
+	}
+
+	if err := l.repository.SaveLoginAttempt(ctx, attempt); err != nil {
+		return status.Errorf(codes.Internal, "Failed to save login attempts")
+	}
+
+	return nil
+}
+
+// cleanup 잠금 시간이 지나 의미가 없어진 실패 기록을 가끔 지웁니다.
+func (l *LoginLimiter) cleanup(ctx context.Context, now time.Time) {
+	l.mapMutex.Lock()
+	if now.Sub(l.lastCleanup) < cleanupInterval {
+		l.mapMutex.Unlock()
+		return
+	}
+	l.lastCleanup = now
+	l.mapMutex.Unlock()
+
+	l.repository.DeleteLoginAttemptsBefore(ctx, now.Add(-l.policy.Lockout))
+}
+
+// keyLock 기다리는 요청이 없으면 맵에서 지워, 임의의 전화번호로 요청해도 잠금이 쌓이지 않게 합니다.
+type keyLock struct {
+	sync.Mutex
+	waiters int
+}
+
+func (l *LoginLimiter) lock(key string) func() {
+	l.mapMutex.Lock()
+	mutex, ok := l.keyMutex[key]
+	if !ok {
+		mutex = &keyLock{}
+		l.keyMutex[key] = mutex
+	}
+	mutex.waiters++
+	l.mapMutex.Unlock()
+
+	mutex.Lock()
+	return func() {
+		mutex.Unlock()
+
+		l.mapMutex.Lock()
+		mutex.waiters--
+		if mutex.waiters == 0 {
+			delete(l.keyMutex, key)
+		}
+		l.mapMutex.Unlock()
+	}
+}
+
+// clientIP 요청한 클라이언트의 IP입니다. 같은 호스트의 grpc-gateway를 거친 요청이면 게이트웨이가 넘긴 x-forwarded-for를 씁니다.
+// 게이트웨이는 클라이언트가 보낸 X-Forwarded-For 뒤에 실제 주소를 덧붙이므로 마지막 값을 씁니다.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("x-forwarded-for"); len(values) > 0 {
+				addresses := strings.Split(values[len(values)-1], ",")
+				forwarded := strings.TrimSpace(addresses[len(addresses)-1])
+				if forwarded != "" {
+					return forwarded
+				}
+			}
+		}
+	}
+
+	return host
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ebank/services/user/model"
+)
+
+type loginAttemptStore map[string]model.LoginAttempt
+
+func (s loginAttemptStore) GetLoginAttempt(_ context.Context, key string) (*model.LoginAttempt, error) {
+	attempt, ok := s[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+func (s loginAttemptStore) SaveLoginAttempt(_ context.Context, attempt model.LoginAttempt) error {
+	s[attempt.Key] = attempt
+	return nil
+}
+
+func (s loginAttemptStore) DeleteLoginAttempt(_ context.Context, key string) error {
+	delete(s, key)
+	return nil
+}
+
+func (s loginAttemptStore) DeleteLoginAttemptsBefore(_ context.Context, before time.Time) error {
+	for key, attempt := range s {
+		if attempt.LastFailedAt.Before(before) && attempt.LockedUntil.Before(before) {
+			delete(s, key)
+		}
+	}
+	return nil
+}
+
+func TestLoginLimiter(t *testing.T) {
+	store := loginAttemptStore{}
+	limiter := NewLoginLimiter(store, LoginPolicy{MaxFailures: 3, MaxFailuresPerIP: 2, Backoff: time.Second, Lockout: time.Minute})
+	now := time.Unix(1_700_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	wrongPassword := func() error { return status.Error(codes.Unauthenticated, "Invalid password") }
+	correctPassword := func() error { return nil }
+	guard := func(phoneNumber string, ip string, login func() error) codes.Code {
+		return status.Code(limiter.Guard(context.Background(), phoneNumber, ip, login))
+	}
+
+	if code := guard("01011112222", "", wrongPassword); code != codes.Unauthenticated {
+		t.Fatalf("1st failure = %v", code)
+	}
+	// 첫 실패 후 1초, 두 번째 실패 후 2초 동안 기다려야 합니다.
+	if code := guard("01011112222", "", correctPassword); code != codes.ResourceExhausted {
+		t.Fatalf("retry within backoff = %v, want ResourceExhausted", code)
+	}
+	now = now.Add(time.Second)
+	if code := guard("01011112222", "", wrongPassword); code != codes.Unauthenticated {
+		t.Fatalf("2nd failure = %v", code)
+	}
+	now = now.Add(time.Second)
+	if code := guard("01011112222", "", correctPassword); code != codes.ResourceExhausted {
+		t.Fatalf("retry within doubled backoff = %v, want ResourceExhausted", code)
+	}
+
+	// 세 번째 실패로 잠기면 맞는 비밀번호도 거부합니다.
+	now = now.Add(time.Second)
+	guard("01011112222", "", wrongPassword)
+	now = now.Add(30 * time.Second)
+	if code := guard("01011112222", "", correctPassword); code != codes.ResourceExhausted {
+		t.Fatalf("login while locked = %v, want ResourceExhausted", code)
+	}
+
+	// 잠금이 풀리면 로그인할 수 있고, 성공하면 전화번호의 실패 기록을 지웁니다.
+	now = now.Add(time.Minute)
+	if code := guard("01011112222", "", correctPassword); code != codes.OK {
+		t.Fatalf("login after lockout = %v", code)
+	}
+	if _, ok := store[phoneNumberKey("01011112222")]; ok {
+		t.Error("phone number attempts were not reset after success")
+	}
+
+	// 같은 IP에서 여러 전화번호를 시도하면 IP가 잠깁니다.
+	for _, phoneNumber := range []string{"01000000001", "01000000002"} {
+		guard(phoneNumber, "10.0.0.1", wrongPassword)
+	}
+	if code := guard("01099999999", "10.0.0.1", correctPassword); code != codes.ResourceExhausted {
+		t.Fatalf("login from locked ip = %v, want ResourceExhausted", code)
+	}
+	if code := guard("01099999999", "10.0.0.2", correctPassword); code != codes.OK {
+		t.Fatalf("login from another ip = %v", code)
+	}
+
+	if err := limiter.Unlock(context.Background(), "", "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if code := guard("01099999999", "10.0.0.1", correctPassword); code != codes.OK {
+		t.Fatalf("login after unlock = %v", code)
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ebank/api/v1"
+	"ebank/pkg/jwt_manager"
+	"ebank/services/user/model"
+)
+
+// methodPolicy RPC 하나를 호출할 수 있는 역할과, 그 중 다른 사용자의 리소스까지 다룰 수 있는 역할입니다.
+// anyResource에 없는 역할은 ValidateUser에서 본인 리소스만 허용됩니다.
+type methodPolicy struct {
+	roles       []string
+	anyResource []string
+}
+
+var (
+	allRoles = []string{model.RoleCustomer, model.RoleTeller, model.RoleAdmin}
+	staff    = []string{model.RoleTeller, model.RoleAdmin}
+	admin    = []string{model.RoleAdmin}
+)
+
+// policies 인증이 필요한 RPC별 권한 표입니다. 표에 없는 RPC는 거부합니다.
+var policies = map[string]methodPolicy{
+	ebank.UserService_GetUser_FullMethodName:     {roles: allRoles, anyResource: staff},
+	ebank.UserService_UpdateUser_FullMethodName:  {roles: allRoles, anyResource: admin},
+	ebank.UserService_DeleteUser_FullMethodName:  {roles: allRoles, anyResource: admin},
+	ebank.UserService_GetAllUsers_FullMethodName: {roles: admin},
+	ebank.UserService_GrantRole_FullMethodName:   {roles: admin},
+	ebank.UserService_RevokeRole_FullMethodName:  {roles: admin},
+	ebank.UserService_UnlockLogin_FullMethodName: {roles: admin},
+	ebank.UserService_EnrollTOTP_FullMethodName:  {roles: allRoles},
+	ebank.UserService_ConfirmTOTP_FullMethodName: {roles: allRoles},
+	ebank.UserService_DisableTOTP_FullMethodName: {roles: allRoles},
+
+	ebank.AuthService_Logout_FullMethodName:            {roles: allRoles},
+	ebank.AuthService_LogoutAllSessions_FullMethodName: {roles: allRoles},
+
+	ebank.AccountService_CreateAccount_FullMethodName:         {roles: allRoles},
+	ebank.AccountService_GetAccount_FullMethodName:            {roles: allRoles, anyResource: staff},
+	ebank.AccountService_WatchAccount_FullMethodName:          {roles: allRoles, anyResource: staff},
+	ebank.AccountService_UpdateAccount_FullMethodName:         {roles: allRoles, anyResource: admin},
+	ebank.AccountService_DeleteAccount_FullMethodName:         {roles: allRoles, anyResource: admin},
+	ebank.AccountService_ListMyAccounts_FullMethodName:        {roles: allRoles},
+	ebank.AccountService_ListAccounts_FullMethodName:          {roles: admin},
+	ebank.AccountService_ValidateAccountNumber_FullMethodName: {roles: allRoles},
+	ebank.AccountService_ChangeAccountStatus_FullMethodName:   {roles: staff, anyResource: staff},
+
+	// 창구 직원은 어느 계좌에나 현금을 입금할 수 있지만, 출금과 이체는 계좌 주인만 할 수 있습니다.
+	ebank.TransactionService_Deposit_FullMethodName:               {roles: allRoles, anyResource: staff},
+	ebank.TransactionService_Withdraw_FullMethodName:              {roles: allRoles},
+	ebank.TransactionService_Transfer_FullMethodName:              {roles: allRoles},
+	ebank.TransactionService_CloseAccount_FullMethodName:          {roles: allRoles, anyResource: admin},
+	ebank.TransactionService_MatureAccounts_FullMethodName:        {roles: admin},
+	ebank.TransactionService_GetTransactionHistory_FullMethodName: {roles: allRoles, anyResource: staff},
+	ebank.TransactionService_WatchTransactions_FullMethodName:     {roles: allRoles, anyResource: staff},
+}
+
+type anyResourceContextKey struct{}
+
+// authorizeMethod 토큰의 역할로 RPC를 호출할 수 있는지 확인하고, 다른 사용자의 리소스를 다룰 수 있으면 ctx에 표시합니다.
+func authorizeMethod(ctx context.Context, method string, claims *jwt_manager.UserClaims) (context.Context, error) {
+	policy, ok := policies[method]
+	if !ok {
+		return ctx, status.Errorf(codes.PermissionDenied, "no access policy for %s", method)
+	}
+
+	role := model.User{Role: claims.Role}.GetRole()
+	if !containsRole(policy.roles, role) {
+		return ctx, status.Errorf(codes.PermissionDenied, "role %s is not allowed to call %s", role, method)
+	}
+
+	if containsRole(policy.anyResource, role) {
+		ctx = context.WithValue(ctx, anyResourceContextKey{}, true)
+	}
+
+	return ctx, nil
+}
+
+// canAccessAnyResource 현재 RPC에서 다른 사용자의 리소스를 다룰 수 있는지 반환합니다.
+func canAccessAnyResource(ctx context.Context) bool {
+	allowed, _ := ctx.Value(anyResourceContextKey{}).(bool)
+	return allowed
+}
+
+func containsRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
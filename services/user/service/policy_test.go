@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"ebank/api/v1"
+	"ebank/pkg/jwt_manager"
+	"ebank/services/user/model"
+)
+
+// 새 RPC를 추가하고 권한 표를 빠뜨리면 모든 호출이 거부되므로, 등록된 RPC마다 정책이 있는지 확인합니다.
+func Test_policies_coverAllMethods(t *testing.T) {
+	interceptor := &UserInterceptor{}
+	for _, desc := range []grpc.ServiceDesc{
+		ebank.UserService_ServiceDesc,
+		ebank.AuthService_ServiceDesc,
+		ebank.AccountService_ServiceDesc,
+		ebank.TransactionService_ServiceDesc,
+	} {
+		for _, method := range desc.Methods {
+			fullMethod := "/" + desc.ServiceName + "/" + method.MethodName
+			if _, ok := policies[fullMethod]; !ok && !interceptor.skipper(fullMethod) {
+				t.Errorf("%s has no access policy", fullMethod)
+			}
+		}
+	}
+}
+
+func Test_authorizeMethod(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		role            string
+		wantCode        codes.Code
+		wantAnyResource bool
+	}{
+		{
+			name:     "고객은 전체 사용자 조회 불가",
+			method:   ebank.UserService_GetAllUsers_FullMethodName,
+			role:     model.RoleCustomer,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "역할이 없는 토큰은 고객으로 취급",
+			method:   ebank.UserService_GetAllUsers_FullMethodName,
+			role:     "",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "관리자는 전체 사용자 조회 가능",
+			method:   ebank.UserService_GetAllUsers_FullMethodName,
+			role:     model.RoleAdmin,
+			wantCode: codes.OK,
+		},
+		{
+			name:            "창구 직원은 모든 계좌에 입금 가능",
+			method:          ebank.TransactionService_Deposit_FullMethodName,
+			role:            model.RoleTeller,
+			wantCode:        codes.OK,
+			wantAnyResource: true,
+		},
+		{
+			name:     "창구 직원도 다른 사람 계좌에서 출금 불가",
+			method:   ebank.TransactionService_Withdraw_FullMethodName,
+			role:     model.RoleTeller,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "고객은 본인 계좌에만 입금",
+			method:   ebank.TransactionService_Deposit_FullMethodName,
+			role:     model.RoleCustomer,
+			wantCode: codes.OK,
+		},
+		{
+			name:     "정책이 없는 RPC는 거부",
+			method:   "/proto.UnknownService/Unknown",
+			role:     model.RoleAdmin,
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := authorizeMethod(context.Background(), tt.method, &jwt_manager.UserClaims{Role: tt.role})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("authorizeMethod() error = %v, want %v", err, tt.wantCode)
+			}
+			if got := canAccessAnyResource(ctx); got != tt.wantAnyResource {
+				t.Errorf("canAccessAnyResource() = %v, want %v", got, tt.wantAnyResource)
+			}
+		})
+	}
+}
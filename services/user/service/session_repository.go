@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"ebank/services/user/model"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session model.Session) error
+	GetSession(ctx context.Context, id string) (*model.Session, error)
+	// RotateRefreshToken 세션의 리프레시 토큰 ID가 아직 oldTokenID일 때만 newTokenID로 바꾸고 true를 반환합니다.
+	// 동시에 들어온 같은 리프레시 토큰 중 하나만 성공합니다.
+	RotateRefreshToken(ctx context.Context, id string, oldTokenID string, newTokenID string) (bool, error)
+	RevokeSession(ctx context.Context, id string, revokedAt time.Time) error
+	RevokeUserSessions(ctx context.Context, userID int64, revokedAt time.Time) error
+}
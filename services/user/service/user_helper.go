@@ -2,29 +2,39 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"ebank/pkg/jwt_manager"
+	"ebank/pkg/totp"
 	"ebank/services/user/model"
 )
 
 type UserHelper interface {
 	ValidateUser(ctx context.Context, userID int64) (model.User, error)
+	VerifyOTP(ctx context.Context, userID int64, code string) error
 }
 
 type userHelper struct {
 	userRepository UserRepository
+	// otpLimiter 사용자별로 틀린 OTP 코드를 세어 잠급니다.
+	otpLimiter *LoginLimiter
+	// otpMutex 같은 코드를 동시에 두 번 써서 재사용 검사를 건너뛰지 못하게 합니다.
+	otpMutex sync.Mutex
 }
 
-func NewUserHelper(userRepository UserRepository) UserHelper {
+func NewUserHelper(userRepository UserRepository, otpLimiter *LoginLimiter) UserHelper {
 	return &userHelper{
 		userRepository: userRepository,
+		otpLimiter:     otpLimiter,
 	}
 }
 
-func (u userHelper) ValidateUser(ctx context.Context, userID int64) (model.User, error) {
+func (u *userHelper) ValidateUser(ctx context.Context, userID int64) (model.User, error) {
 	user, err := u.userRepository.GetUserByID(ctx, userID)
 	if err != nil {
 		return model.User{}, status.Errorf(codes.Internal, err.Error())
@@ -38,10 +48,70 @@ func (u userHelper) ValidateUser(ctx context.Context, userID int64) (model.User,
 		return model.User{}, status.Errorf(codes.NotFound, "User not found")
 	}
 
-	if claims, ok := ctx.Value("user").(*jwt_manager.UserClaims); ok &&
-		claims.PhoneNumber != user.PhoneNumber {
+	claims, ok := jwt_manager.FromContext(ctx)
+	if !ok {
+		return model.User{}, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+	if claims.UserID != user.ID && !canAccessAnyResource(ctx) {
 		return model.User{}, status.Errorf(codes.PermissionDenied, "Not allowed")
 	}
 
 	return *user, nil
 }
+
+// VerifyOTP TOTP 코드나 복구 코드를 확인합니다. 한 번 쓴 코드는 다시 받지 않습니다.
+// 틀린 코드가 -max_login_failures번 쌓이면 -lockout_duration 동안 ResourceExhausted를 반환합니다.
+func (u *userHelper) VerifyOTP(ctx context.Context, userID int64, code string) error {
+	return u.otpLimiter.GuardOTP(ctx, userID, func() error {
+		return u.verifyOTP(ctx, userID, code)
+	})
+}
+
+func (u *userHelper) verifyOTP(ctx context.Context, userID int64, code string) error {
+	u.otpMutex.Lock()
+	defer u.otpMutex.Unlock()
+
+	user, err := u.userRepository.GetUserByID(ctx, userID)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if user == nil || user.IsDeleted {
+		return status.Errorf(codes.NotFound, "User not found")
+	}
+	if !user.TOTPEnabled {
+		return status.Errorf(codes.FailedPrecondition, "Two-factor authentication is not enabled")
+	}
+
+	if totp.IsCode(code) {
+		step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+		if !ok {
+			return status.Errorf(codes.Unauthenticated, "Invalid OTP code")
+		}
+		user.TOTPLastStep = step
+	} else if !user.UseRecoveryCode(code) {
+		return status.Errorf(codes.Unauthenticated, "Invalid OTP code")
+	}
+
+	if err := u.userRepository.UpdateUser(ctx, *user); err != nil {
+		return status.Errorf(codes.Internal, "Failed to save user data")
+	}
+
+	return nil
+}
+
+// EnsureAdmin 시작 시 지정한 전화번호의 사용자에게 admin 역할을 부여합니다. 첫 관리자를 만들 때 사용합니다.
+func EnsureAdmin(ctx context.Context, userRepository UserRepository, phoneNumber string) error {
+	user, err := userRepository.GetUserByPhoneNumber(ctx, phoneNumber)
+	if err != nil {
+		return err
+	}
+	if user.IsDeleted {
+		return fmt.Errorf("user with phone number %s is deleted", phoneNumber)
+	}
+	if user.Role == model.RoleAdmin {
+		return nil
+	}
+
+	user.Role = model.RoleAdmin
+	return userRepository.UpdateUser(ctx, user)
+}
@@ -3,12 +3,18 @@ package service
 import (
 	"context"
 	"strings"
+	"sync"
 
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 
+	"ebank/api/v1"
 	"ebank/pkg/jwt_manager"
 )
 
@@ -29,7 +35,7 @@ func (interceptor *UserInterceptor) Unary() grpc.UnaryServerInterceptor {
 	) (interface{}, error) {
 		var err error
 		if !interceptor.skipper(info.FullMethod) {
-			ctx, err = interceptor.authorize(ctx)
+			ctx, err = interceptor.authorize(ctx, info.FullMethod)
 			if err != nil {
 				return nil, err
 			}
@@ -39,7 +45,30 @@ func (interceptor *UserInterceptor) Unary() grpc.UnaryServerInterceptor {
 	}
 }
 
-func (interceptor *UserInterceptor) authorize(ctx context.Context) (context.Context, error) {
+// Stream 스트리밍 RPC도 Unary와 같이 토큰과 권한을 확인하고, 핸들러가 stream.Context()로 클레임을 꺼낼 수 있게 합니다.
+func (interceptor *UserInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if interceptor.skipper(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		ctx, err := interceptor.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
+
+func (interceptor *UserInterceptor) authorize(ctx context.Context, method string) (context.Context, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return ctx, status.Errorf(codes.Unauthenticated, "metadata is not provided")
@@ -62,16 +91,33 @@ func (interceptor *UserInterceptor) authorize(ctx context.Context) (context.Cont
 		return ctx, status.Error(codes.Unauthenticated, "access token is invalid")
 	}
 
-	return context.WithValue(ctx, "user", claims), nil
+	return authorizeMethod(jwt_manager.NewContext(ctx, claims), method, claims)
 }
 
+// skipper proto에 `option (ebank.auth).public = true;`로 표시한 RPC는 토큰 없이 통과시킵니다.
 func (interceptor *UserInterceptor) skipper(method string) bool {
-	switch method {
-	case "/proto.UserService/Login":
-		return true
-	case "/proto.UserService/CreateUser":
-		return true
-	default:
-		return false
+	return isPublicMethod(method)
+}
+
+// publicMethods 메서드별 isPublicMethod 결과를 저장합니다. 등록된 디스크립터는 바뀌지 않습니다.
+var publicMethods sync.Map
+
+// isPublicMethod "/proto.AuthService/Login" 형태의 메서드 이름으로 디스크립터를 찾아 인증 옵션을 읽습니다.
+// 디스크립터를 찾지 못한 메서드는 인증이 필요한 것으로 봅니다.
+func isPublicMethod(fullMethod string) bool {
+	if public, ok := publicMethods.Load(fullMethod); ok {
+		return public.(bool)
 	}
+
+	public := false
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", "."))
+	if desc, err := protoregistry.GlobalFiles.FindDescriptorByName(name); err == nil {
+		if method, ok := desc.(protoreflect.MethodDescriptor); ok {
+			rule, _ := proto.GetExtension(method.Options(), ebank.E_Auth).(*ebank.AuthRule)
+			public = rule.GetPublic()
+		}
+	}
+
+	publicMethods.Store(fullMethod, public)
+	return public
 }
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"ebank/api/v1"
+	"ebank/mocks"
+	"ebank/pkg/jwt_manager"
+	"ebank/services/user/model"
+)
+
+// 등록된 모든 RPC의 인증 방식을 확인합니다. RPC를 추가하면 이 표에도 추가해야 합니다.
+func Test_methodAuthModes(t *testing.T) {
+	const (
+		public        = "public"
+		authenticated = "authenticated"
+	)
+	want := map[string]string{
+		ebank.AuthService_Login_FullMethodName:             public,
+		ebank.AuthService_RefreshToken_FullMethodName:      public,
+		ebank.AuthService_Logout_FullMethodName:            authenticated,
+		ebank.AuthService_LogoutAllSessions_FullMethodName: authenticated,
+
+		ebank.UserService_CreateUser_FullMethodName:  public,
+		ebank.UserService_GetUser_FullMethodName:     authenticated,
+		ebank.UserService_UpdateUser_FullMethodName:  authenticated,
+		ebank.UserService_DeleteUser_FullMethodName:  authenticated,
+		ebank.UserService_GetAllUsers_FullMethodName: authenticated,
+		ebank.UserService_GrantRole_FullMethodName:   authenticated,
+		ebank.UserService_RevokeRole_FullMethodName:  authenticated,
+		ebank.UserService_UnlockLogin_FullMethodName: authenticated,
+		ebank.UserService_EnrollTOTP_FullMethodName:  authenticated,
+		ebank.UserService_ConfirmTOTP_FullMethodName: authenticated,
+		ebank.UserService_DisableTOTP_FullMethodName: authenticated,
+
+		ebank.AccountService_CreateAccount_FullMethodName:         authenticated,
+		ebank.AccountService_GetAccount_FullMethodName:            authenticated,
+		ebank.AccountService_UpdateAccount_FullMethodName:         authenticated,
+		ebank.AccountService_DeleteAccount_FullMethodName:         authenticated,
+		ebank.AccountService_WatchAccount_FullMethodName:          authenticated,
+		ebank.AccountService_ListMyAccounts_FullMethodName:        authenticated,
+		ebank.AccountService_ListAccounts_FullMethodName:          authenticated,
+		ebank.AccountService_ValidateAccountNumber_FullMethodName: authenticated,
+		ebank.AccountService_ChangeAccountStatus_FullMethodName:   authenticated,
+		ebank.AccountService_ListProducts_FullMethodName:          public,
+
+		ebank.TransactionService_Deposit_FullMethodName:               authenticated,
+		ebank.TransactionService_Withdraw_FullMethodName:              authenticated,
+		ebank.TransactionService_Transfer_FullMethodName:              authenticated,
+		ebank.TransactionService_CloseAccount_FullMethodName:          authenticated,
+		ebank.TransactionService_MatureAccounts_FullMethodName:        authenticated,
+		ebank.TransactionService_GetTransactionHistory_FullMethodName: authenticated,
+		ebank.TransactionService_WatchTransactions_FullMethodName:     authenticated,
+	}
+
+	interceptor := &UserInterceptor{}
+	seen := make(map[string]bool)
+	protoregistry.GlobalFiles.RangeFilesByPackage("proto", func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			methods := services.Get(i).Methods()
+			for j := 0; j < methods.Len(); j++ {
+				fullMethod := "/" + string(services.Get(i).FullName()) + "/" + string(methods.Get(j).Name())
+				seen[fullMethod] = true
+
+				mode := authenticated
+				if interceptor.skipper(fullMethod) {
+					mode = public
+				}
+				if want[fullMethod] != mode {
+					t.Errorf("%s auth mode = %s, want %q", fullMethod, mode, want[fullMethod])
+				}
+				if mode == authenticated {
+					if _, ok := policies[fullMethod]; !ok {
+						t.Errorf("%s has no access policy", fullMethod)
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	for fullMethod := range want {
+		if !seen[fullMethod] {
+			t.Errorf("%s is not registered", fullMethod)
+		}
+	}
+}
+
+func Test_UserInterceptor_Unary_PublicMethodWithoutToken(t *testing.T) {
+	interceptor := NewUserInterceptor(nil)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	resp, err := interceptor.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: ebank.AuthService_Login_FullMethodName}, handler)
+	if err != nil || resp != "ok" {
+		t.Fatalf("Login without token = (%v, %v), want ok", resp, err)
+	}
+
+	_, err = interceptor.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: ebank.AuthService_Logout_FullMethodName}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Logout without token error = %v, want Unauthenticated", err)
+	}
+
+	_, err = interceptor.Unary()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/proto.UserService/Login"}, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unknown method without token error = %v, want Unauthenticated", err)
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func Test_UserInterceptor_Stream(t *testing.T) {
+	jwtManager := mocks.NewJWTManager(t)
+	jwtManager.EXPECT().Verify("token").Return(&jwt_manager.UserClaims{UserID: 1, Role: model.RoleCustomer}, nil)
+	interceptor := NewUserInterceptor(jwtManager)
+	info := &grpc.StreamServerInfo{FullMethod: ebank.UserService_GetUser_FullMethodName, IsServerStream: true}
+
+	var claims *jwt_manager.UserClaims
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		claims, _ = jwt_manager.FromContext(stream.Context())
+		return nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	if err := interceptor.Stream()(nil, &fakeServerStream{ctx: ctx}, info, handler); err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	if claims == nil || claims.UserID != 1 {
+		t.Fatalf("stream context claims = %v, want user 1", claims)
+	}
+
+	err := interceptor.Stream()(nil, &fakeServerStream{ctx: context.Background()}, info, handler)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Stream() without token error = %v, want Unauthenticated", err)
+	}
+}
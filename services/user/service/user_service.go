@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
@@ -10,26 +11,38 @@ import (
 
 	"ebank/api/v1"
 	"ebank/pkg/jwt_manager"
-	"ebank/pkg/zero"
+	"ebank/pkg/totp"
+	accountService "ebank/services/account/service"
 	"ebank/services/user/model"
 )
 
+const (
+	totpIssuer        = "ebank"
+	recoveryCodeCount = 10
+)
+
 type userService struct {
 	ebank.UnimplementedUserServiceServer
-	userHelper     UserHelper
-	userRepository UserRepository
-	jwtManager     jwt_manager.JWTManager
+	userHelper        UserHelper
+	userRepository    UserRepository
+	accountRepository accountService.AccountRepository
+	sessionRepository SessionRepository
+	loginLimiter      *LoginLimiter
 }
 
 func NewUserService(
 	userHelper UserHelper,
 	userRepository UserRepository,
-	jwtManager jwt_manager.JWTManager,
+	accountRepository accountService.AccountRepository,
+	sessionRepository SessionRepository,
+	loginLimiter *LoginLimiter,
 ) ebank.UserServiceServer {
 	return &userService{
-		userHelper:     userHelper,
-		userRepository: userRepository,
-		jwtManager:     jwtManager,
+		userHelper:        userHelper,
+		userRepository:    userRepository,
+		accountRepository: accountRepository,
+		sessionRepository: sessionRepository,
+		loginLimiter:      loginLimiter,
 	}
 }
 
@@ -51,46 +64,29 @@ func (s *userService) CreateUser(ctx context.Context, req *ebank.CreateUserReque
 		return nil, err
 	}
 
-	return &ebank.UserResponse{User: &ebank.User{
-		Id:          user.ID,
-		Name:        user.Name,
-		Birth:       user.Birth,
-		PhoneNumber: user.PhoneNumber,
-	}}, nil
+	return &ebank.UserResponse{User: toUserDto(user)}, nil
 }
 
 func (s *userService) GetUser(ctx context.Context, req *ebank.GetUserRequest) (*ebank.UserResponse, error) {
-	user, err := s.userRepository.GetUserByID(ctx, req.GetId())
+	user, err := s.userHelper.ValidateUser(ctx, req.GetId())
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "User not found")
+		return nil, err
 	}
 
-	if user == nil {
-		return nil, status.Errorf(codes.NotFound, "User not found")
+	userDto := toUserDto(user)
+	if req.GetIncludeAccounts() {
+		accounts, err := s.accountRepository.GetAccountsByUserID(ctx, user.ID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to load account data")
+		}
+
+		userDto.Accounts = make([]*ebank.Account, 0, len(accounts))
+		for _, account := range accounts {
+			userDto.Accounts = append(userDto.Accounts, accountService.ToAccountDto(account))
+		}
 	}
 
-	// accounts, err := s.accountRepository.GetAccountsByUserID(ctx, req.GetId())
-	// if err != nil {
-	// 	return nil, err
-	// }
-	// accountDtos := make([]*ebank.Account, 0)
-	// for _, account := range accounts {
-	// 	accountDtos = append(accountDtos, &ebank.Account{
-	// 		Id:            account.ID,
-	// 		AccountNumber: account.AccountNumber,
-	// 		CustomerId:    account.CustomerID,
-	// 		Balance:       account.Balance,
-	// 		CreatedAt:     timestamppb.New(account.CreatedAt),
-	// 	})
-	// }
-
-	return &ebank.UserResponse{User: &ebank.User{
-		Id:          user.ID,
-		Name:        user.Name,
-		Birth:       user.Birth,
-		PhoneNumber: user.PhoneNumber,
-		// Accounts:    accountDtos,
-	}}, nil
+	return &ebank.UserResponse{User: userDto}, nil
 }
 
 func (s *userService) UpdateUser(ctx context.Context, req *ebank.UpdateUserRequest) (*ebank.UserResponse, error) {
@@ -115,12 +111,7 @@ func (s *userService) UpdateUser(ctx context.Context, req *ebank.UpdateUserReque
 		return nil, status.Errorf(codes.Internal, "Failed to save user data")
 	}
 
-	return &ebank.UserResponse{User: &ebank.User{
-		Id:          validateUser.ID,
-		Name:        validateUser.Name,
-		Birth:       validateUser.Birth,
-		PhoneNumber: validateUser.PhoneNumber,
-	}}, nil
+	return &ebank.UserResponse{User: toUserDto(validateUser)}, nil
 }
 
 func (s *userService) DeleteUser(ctx context.Context, req *ebank.DeleteUserRequest) (*emptypb.Empty, error) {
@@ -147,34 +138,181 @@ func (s *userService) GetAllUsers(ctx context.Context, req *ebank.GetAllUsersReq
 
 	userDtos := &ebank.UserListResponse{Users: make([]*ebank.User, 0, len(users))}
 	for _, user := range users {
-		userDtos.Users = append(userDtos.Users, &ebank.User{
-			Id:          user.ID,
-			Name:        user.Name,
-			Birth:       user.Birth,
-			PhoneNumber: user.PhoneNumber,
-		})
+		userDtos.Users = append(userDtos.Users, toUserDto(user))
 	}
 
 	return userDtos, nil
 }
 
-func (s *userService) Login(ctx context.Context, req *ebank.LoginRequest) (*ebank.LoginResponse, error) {
-	user, err := s.userRepository.GetUserByPhoneNumber(ctx, req.GetPhoneNumber())
+func (s *userService) GrantRole(ctx context.Context, req *ebank.GrantRoleRequest) (*ebank.UserResponse, error) {
+	if !model.IsValidRole(req.GetRole()) {
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown role %q", req.GetRole())
+	}
+
+	return s.setRole(ctx, req.GetUserId(), req.GetRole())
+}
+
+func (s *userService) RevokeRole(ctx context.Context, req *ebank.RevokeRoleRequest) (*ebank.UserResponse, error) {
+	// 마지막 관리자가 스스로 권한을 잃지 않도록 본인 역할은 회수할 수 없습니다.
+	if claims, ok := jwt_manager.FromContext(ctx); ok && claims.UserID == req.GetUserId() {
+		return nil, status.Errorf(codes.FailedPrecondition, "Cannot revoke own role")
+	}
+
+	return s.setRole(ctx, req.GetUserId(), model.RoleCustomer)
+}
+
+// setRole 사용자의 역할을 바꿉니다. 역할이 바뀌면 이전 역할이 담긴 토큰을 쓸 수 없도록 사용자의 모든 세션을 폐기합니다.
+func (s *userService) setRole(ctx context.Context, userID int64, role string) (*ebank.UserResponse, error) {
+	user, err := s.userRepository.GetUserByID(ctx, userID)
+	if err != nil || user == nil || user.IsDeleted {
+		return nil, status.Errorf(codes.NotFound, "User not found")
+	}
+
+	if user.GetRole() == role {
+		return &ebank.UserResponse{User: toUserDto(*user)}, nil
+	}
+
+	user.Role = role
+	if err := s.userRepository.UpdateUser(ctx, *user); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save user data")
+	}
+
+	if err := s.sessionRepository.RevokeUserSessions(ctx, userID, time.Now()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to revoke sessions")
+	}
+
+	return &ebank.UserResponse{User: toUserDto(*user)}, nil
+}
+
+func (s *userService) UnlockLogin(ctx context.Context, req *ebank.UnlockLoginRequest) (*emptypb.Empty, error) {
+	if req.GetUserId() == 0 && req.GetIpAddress() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "user_id or ip_address is required")
+	}
+
+	var phoneNumber string
+	if req.GetUserId() != 0 {
+		user, err := s.userRepository.GetUserByID(ctx, req.GetUserId())
+		if err != nil || user == nil {
+			return nil, status.Errorf(codes.NotFound, "User not found")
+		}
+		phoneNumber = user.PhoneNumber
+	}
+
+	if err := s.loginLimiter.Unlock(ctx, phoneNumber, req.GetIpAddress()); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to unlock login")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *userService) EnrollTOTP(ctx context.Context, _ *ebank.EnrollTOTPRequest) (*ebank.EnrollTOTPResponse, error) {
+	user, err := s.currentUser(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if zero.IsStructZero(user) {
-		return nil, status.Errorf(codes.NotFound, "User not found")
+	if user.TOTPEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "Two-factor authentication is already enabled")
+	}
+
+	// 확인 전까지는 다시 등록하면 새 비밀 키로 바뀝니다.
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to generate secret")
+	}
+	user.TOTPSecret = secret
+	user.TOTPLastStep = 0
+	if err := s.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save user data")
+	}
+
+	return &ebank.EnrollTOTPResponse{
+		Secret:     secret,
+		OtpauthUri: totp.URI(totpIssuer, user.PhoneNumber, secret),
+	}, nil
+}
+
+func (s *userService) ConfirmTOTP(ctx context.Context, req *ebank.ConfirmTOTPRequest) (*ebank.ConfirmTOTPResponse, error) {
+	user, err := s.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "Two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "Two-factor authentication is not enrolled")
+	}
+
+	step, ok := totp.Validate(user.TOTPSecret, req.GetCode(), time.Now(), user.TOTPLastStep)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid OTP code")
+	}
+
+	recoveryCodes, err := totp.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to generate recovery codes")
+	}
+	hashedCodes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(code), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to hash recovery codes")
+		}
+		hashedCodes = append(hashedCodes, string(hashed))
 	}
 
-	if !user.IsCorrectPassword(req.Password) {
-		return nil, status.Errorf(codes.Unauthenticated, "Invalid password")
+	user.TOTPEnabled = true
+	user.TOTPLastStep = step
+	user.RecoveryCodes = hashedCodes
+	if err := s.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save user data")
+	}
+
+	return &ebank.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *userService) DisableTOTP(ctx context.Context, req *ebank.DisableTOTPRequest) (*emptypb.Empty, error) {
+	claims, ok := jwt_manager.FromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+
+	if err := s.userHelper.VerifyOTP(ctx, claims.UserID, req.GetCode()); err != nil {
+		return nil, err
 	}
 
-	tokenString, err := s.jwtManager.Generate(user)
+	user, err := s.currentUser(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to generate token")
+		return nil, err
+	}
+	user.TOTPSecret = ""
+	user.TOTPEnabled = false
+	user.TOTPLastStep = 0
+	user.RecoveryCodes = nil
+	if err := s.userRepository.UpdateUser(ctx, user); err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to save user data")
 	}
 
-	return &ebank.LoginResponse{Token: tokenString}, nil
+	return &emptypb.Empty{}, nil
+}
+
+// currentUser 토큰의 사용자를 찾습니다.
+func (s *userService) currentUser(ctx context.Context) (model.User, error) {
+	claims, ok := jwt_manager.FromContext(ctx)
+	if !ok {
+		return model.User{}, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+
+	return s.userHelper.ValidateUser(ctx, claims.UserID)
+}
+
+func toUserDto(user model.User) *ebank.User {
+	return &ebank.User{
+		Id:          user.ID,
+		Name:        user.Name,
+		Birth:       user.Birth,
+		PhoneNumber: user.PhoneNumber,
+		Role:        user.GetRole(),
+		TotpEnabled: user.TOTPEnabled,
+	}
 }
@@ -3,13 +3,21 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"ebank/api/v1"
-	"ebank/internal/model"
 	"ebank/mocks"
+	"ebank/pkg/jwt_manager"
+	"ebank/pkg/money"
+	"ebank/pkg/totp"
+	accountModel "ebank/services/account/model"
+	"ebank/services/user/model"
 )
 
 func TestUserUsecaseSuite(t *testing.T) {
@@ -25,7 +33,8 @@ type UserUsecaseTestSuite struct {
 	userRepository    *mocks.UserRepository
 	accountRepository *mocks.AccountRepository
 	userHelper        *mocks.UserHelper
-	jwtManager        *mocks.JWTManager
+	sessionRepository *mocks.SessionRepository
+	loginAttempts     *mocks.LoginAttemptRepository
 	usecase           ebank.UserServiceServer
 }
 
@@ -33,8 +42,9 @@ func (ts *UserUsecaseTestSuite) SetupTest() {
 	ts.userRepository = new(mocks.UserRepository)
 	ts.accountRepository = new(mocks.AccountRepository)
 	ts.userHelper = new(mocks.UserHelper)
-	ts.jwtManager = new(mocks.JWTManager)
-	ts.usecase = NewUserService(ts.userHelper, ts.userRepository, ts.accountRepository, ts.jwtManager)
+	ts.sessionRepository = new(mocks.SessionRepository)
+	ts.loginAttempts = new(mocks.LoginAttemptRepository)
+	ts.usecase = NewUserService(ts.userHelper, ts.userRepository, ts.accountRepository, ts.sessionRepository, NewLoginLimiter(ts.loginAttempts, LoginPolicy{}))
 }
 
 func (ts *UserUsecaseTestSuite) Test_userService_GetUser() {
@@ -43,20 +53,17 @@ func (ts *UserUsecaseTestSuite) Test_userService_GetUser() {
 		Name:        "Test User",
 		Birth:       "2000-01-01",
 		PhoneNumber: "010-1234-5678",
-		// Accounts:    []*ebank.Account{},
+		Role:        model.RoleCustomer,
 	}
 
-	ts.userRepository.EXPECT().GetUserByID(mock.Anything, testUser.GetId()).
-		Return(&model.User{
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, testUser.GetId()).
+		Return(model.User{
 			ID:          testUser.Id,
 			Name:        testUser.Name,
 			Birth:       testUser.Birth,
 			PhoneNumber: testUser.PhoneNumber,
 		}, nil)
 
-	ts.accountRepository.EXPECT().GetAccountsByUserID(mock.Anything, testUser.GetId()).
-		Return([]model.Account{}, nil)
-
 	req := &ebank.GetUserRequest{
 		Id: testUser.Id,
 	}
@@ -65,4 +72,167 @@ func (ts *UserUsecaseTestSuite) Test_userService_GetUser() {
 
 	ts.Equal(testUser, resp.User)
 	ts.NoErrorf(err, "error should be nil")
+	ts.accountRepository.AssertNotCalled(ts.T(), "GetAccountsByUserID", mock.Anything, mock.Anything)
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_GetUser_IncludeAccounts() {
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, int64(1)).
+		Return(model.User{ID: 1, Name: "Test User"}, nil)
+	ts.accountRepository.EXPECT().GetAccountsByUserID(mock.Anything, int64(1)).
+		Return([]accountModel.Account{
+			{ID: 10, AccountNumber: "1234567890", CustomerID: 1, Balance: money.New(1000, "KRW")},
+		}, nil)
+
+	resp, err := ts.usecase.GetUser(context.Background(), &ebank.GetUserRequest{Id: 1, IncludeAccounts: true})
+
+	ts.NoError(err)
+	ts.Require().Len(resp.User.Accounts, 1)
+	ts.Equal(int64(10), resp.User.Accounts[0].Id)
+	ts.Equal(int64(1000), resp.User.Accounts[0].Balance.Amount)
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_GrantRole() {
+	ts.userRepository.EXPECT().GetUserByID(mock.Anything, int64(2)).
+		Return(&model.User{ID: 2, PhoneNumber: "01012345678"}, nil)
+	ts.userRepository.EXPECT().UpdateUser(mock.Anything, model.User{ID: 2, PhoneNumber: "01012345678", Role: model.RoleTeller}).
+		Return(nil)
+	ts.sessionRepository.EXPECT().RevokeUserSessions(mock.Anything, int64(2), mock.Anything).Return(nil)
+
+	resp, err := ts.usecase.GrantRole(context.Background(), &ebank.GrantRoleRequest{UserId: 2, Role: model.RoleTeller})
+
+	ts.NoError(err)
+	ts.Equal(model.RoleTeller, resp.User.Role)
+	ts.sessionRepository.AssertExpectations(ts.T())
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_RevokeRole() {
+	ts.userRepository.EXPECT().GetUserByID(mock.Anything, int64(2)).
+		Return(&model.User{ID: 2, PhoneNumber: "01012345678", Role: model.RoleTeller}, nil)
+	ts.userRepository.EXPECT().UpdateUser(mock.Anything, model.User{ID: 2, PhoneNumber: "01012345678", Role: model.RoleCustomer}).
+		Return(nil)
+	ts.sessionRepository.EXPECT().RevokeUserSessions(mock.Anything, int64(2), mock.Anything).Return(nil)
+
+	resp, err := ts.usecase.RevokeRole(context.Background(), &ebank.RevokeRoleRequest{UserId: 2})
+
+	ts.NoError(err)
+	ts.Equal(model.RoleCustomer, resp.User.Role)
+	ts.sessionRepository.AssertExpectations(ts.T())
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_GrantRole_Unchanged() {
+	ts.userRepository.EXPECT().GetUserByID(mock.Anything, int64(2)).
+		Return(&model.User{ID: 2, PhoneNumber: "01012345678", Role: model.RoleTeller}, nil)
+
+	resp, err := ts.usecase.GrantRole(context.Background(), &ebank.GrantRoleRequest{UserId: 2, Role: model.RoleTeller})
+
+	ts.NoError(err)
+	ts.Equal(model.RoleTeller, resp.User.Role)
+	ts.sessionRepository.AssertNotCalled(ts.T(), "RevokeUserSessions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_GrantRole_UnknownRole() {
+	_, err := ts.usecase.GrantRole(context.Background(), &ebank.GrantRoleRequest{UserId: 2, Role: "root"})
+
+	ts.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_RevokeRole_Self() {
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: 1, Role: model.RoleAdmin})
+
+	_, err := ts.usecase.RevokeRole(ctx, &ebank.RevokeRoleRequest{UserId: 1})
+
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_UnlockLogin() {
+	ts.userRepository.EXPECT().GetUserByID(mock.Anything, int64(2)).
+		Return(&model.User{ID: 2, PhoneNumber: "01012345678"}, nil)
+	ts.loginAttempts.EXPECT().DeleteLoginAttempt(mock.Anything, "phone:01012345678").Return(nil)
+	ts.loginAttempts.EXPECT().DeleteLoginAttempt(mock.Anything, "ip:10.0.0.1").Return(nil)
+
+	_, err := ts.usecase.UnlockLogin(context.Background(), &ebank.UnlockLoginRequest{UserId: 2, IpAddress: "10.0.0.1"})
+
+	ts.NoError(err)
+	ts.loginAttempts.AssertExpectations(ts.T())
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_ConfirmTOTP() {
+	secret, err := totp.GenerateSecret()
+	ts.Require().NoError(err)
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	ts.Require().NoError(err)
+
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: 1})
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, int64(1)).Return(model.User{ID: 1, TOTPSecret: secret}, nil)
+	var saved model.User
+	ts.userRepository.EXPECT().UpdateUser(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, user model.User) error {
+			saved = user
+			return nil
+		})
+
+	resp, err := ts.usecase.ConfirmTOTP(ctx, &ebank.ConfirmTOTPRequest{Code: code})
+
+	ts.NoError(err)
+	ts.Len(resp.RecoveryCodes, recoveryCodeCount)
+	ts.True(saved.TOTPEnabled)
+	ts.Len(saved.RecoveryCodes, recoveryCodeCount)
+	ts.NotEqual(resp.RecoveryCodes[0], saved.RecoveryCodes[0])
+}
+
+func (ts *UserUsecaseTestSuite) Test_userService_ConfirmTOTP_NotEnrolled() {
+	ctx := jwt_manager.NewContext(context.Background(), &jwt_manager.UserClaims{UserID: 1})
+	ts.userHelper.EXPECT().ValidateUser(mock.Anything, int64(1)).Return(model.User{ID: 1}, nil)
+
+	_, err := ts.usecase.ConfirmTOTP(ctx, &ebank.ConfirmTOTPRequest{Code: "123456"})
+
+	ts.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func Test_userHelper_VerifyOTP(t *testing.T) {
+	secret, _ := totp.GenerateSecret()
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	recoveryCode, _ := bcrypt.GenerateFromPassword([]byte("abcd-efgh"), bcrypt.MinCost)
+
+	user := &model.User{ID: 1, TOTPSecret: secret, TOTPEnabled: true, RecoveryCodes: []string{string(recoveryCode)}}
+	userRepository := mocks.NewUserRepository(t)
+	userRepository.EXPECT().GetUserByID(mock.Anything, int64(1)).
+		RunAndReturn(func(ctx context.Context, id int64) (*model.User, error) {
+			copied := *user
+			return &copied, nil
+		})
+	userRepository.EXPECT().UpdateUser(mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, updated model.User) error {
+			*user = updated
+			return nil
+		})
+	helper := NewUserHelper(userRepository, NewLoginLimiter(loginAttemptStore{}, LoginPolicy{MaxFailures: 3, Backoff: time.Nanosecond, Lockout: time.Minute}))
+
+	if err := helper.VerifyOTP(context.Background(), 1, code); err != nil {
+		t.Fatalf("VerifyOTP() error = %v", err)
+	}
+	if err := helper.VerifyOTP(context.Background(), 1, code); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("VerifyOTP() replay error = %v, want Unauthenticated", err)
+	}
+
+	if err := helper.VerifyOTP(context.Background(), 1, "ABCD-EFGH"); err != nil {
+		t.Fatalf("VerifyOTP() recovery code error = %v", err)
+	}
+	if len(user.RecoveryCodes) != 0 {
+		t.Fatalf("recovery code was not consumed")
+	}
+	if err := helper.VerifyOTP(context.Background(), 1, "abcd-efgh"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("VerifyOTP() reused recovery code error = %v, want Unauthenticated", err)
+	}
+
+	// 틀린 코드가 MaxFailures번 쌓이면 잠금 시간 동안 코드를 확인하지 않습니다.
+	for i := 0; i < 2; i++ {
+		if err := helper.VerifyOTP(context.Background(), 1, "000000"); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("VerifyOTP() wrong code error = %v, want Unauthenticated", err)
+		}
+	}
+	nextCode, _ := totp.Code(secret, totp.Step(time.Now())+1)
+	if err := helper.VerifyOTP(context.Background(), 1, nextCode); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("VerifyOTP() after too many failures error = %v, want ResourceExhausted", err)
+	}
 }